@@ -0,0 +1,68 @@
+package salesforce
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rolledBackStatusCode the error salesforce reports against records rolled back by another record's failure
+const rolledBackStatusCode = "ALL_OR_NONE_OPERATION_ROLLED_BACK"
+
+// RolledBack whether the record was valid but rolled back because another record of an all or none write failed
+func (r CollectionResult) RolledBack() bool {
+	for _, e := range r.Errors {
+		if e.StatusCode == rolledBackStatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// AllOrNoneError returned when a record of an all or none write failed, so none of the records were written
+type AllOrNoneError struct {
+	// Results a CollectionResult per record, in the order of the records written
+	Results []CollectionResult
+}
+
+// Failed the indexes of the records which caused the rollback, excluding those rolled back
+func (e AllOrNoneError) Failed() []int {
+	var failed []int
+	for i, r := range e.Results {
+		if !r.Success && !r.RolledBack() {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
+
+func (e AllOrNoneError) Error() string {
+	failed := e.Failed()
+	msgs := make([]string, 0, len(failed))
+	for _, i := range failed {
+		for _, apiErr := range e.Results[i].Errors {
+			msgs = append(msgs, fmt.Sprintf("record %d: %s %s", i, apiErr.StatusCode, apiErr.Message))
+		}
+	}
+	return fmt.Sprintf("salesforce rolled back all %d records, %d failed: %s", len(e.Results), len(failed), strings.Join(msgs, "; "))
+}
+
+// checkAllOrNone errors when an all or none write of n records would span several requests, and so transactions
+func (o requestOptions) checkAllOrNone(n int) error {
+	if o.allOrNone && n > collectionsMaxRecords {
+		return fmt.Errorf("all or none writes are limited to %d records, got %d", collectionsMaxRecords, n)
+	}
+	return nil
+}
+
+// allOrNoneError an AllOrNoneError when an all or none write had a failure, otherwise nil
+func (o requestOptions) allOrNoneError(results []CollectionResult) error {
+	if !o.allOrNone {
+		return nil
+	}
+	for _, r := range results {
+		if !r.Success {
+			return AllOrNoneError{Results: results}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"github.com/cenkalti/backoff/v4"
+	"strings"
+)
+
+// ApexJobStatus the status of an AsyncApexJob
+type ApexJobStatus string
+
+const (
+	ApexJobHolding    ApexJobStatus = "Holding"
+	ApexJobQueued     ApexJobStatus = "Queued"
+	ApexJobPreparing  ApexJobStatus = "Preparing"
+	ApexJobProcessing ApexJobStatus = "Processing"
+	ApexJobCompleted  ApexJobStatus = "Completed"
+	ApexJobFailed     ApexJobStatus = "Failed"
+	ApexJobAborted    ApexJobStatus = "Aborted"
+)
+
+// Done whether a job with the status has finished, successfully or not
+func (s ApexJobStatus) Done() bool {
+	return s == ApexJobCompleted || s == ApexJobFailed || s == ApexJobAborted
+}
+
+// AsyncApexJob a batch, queueable, future or scheduled Apex job
+type AsyncApexJob struct {
+	Id string `json:"Id"`
+	// JobType e.g. BatchApex, Queueable or Future
+	JobType   string        `json:"JobType"`
+	Status    ApexJobStatus `json:"Status"`
+	ApexClass *struct {
+		Name string `json:"Name"`
+	} `json:"ApexClass"`
+	MethodName string `json:"MethodName"`
+	// JobItemsProcessed and TotalJobItems the batches of a batch Apex job processed and in total
+	JobItemsProcessed int `json:"JobItemsProcessed"`
+	TotalJobItems     int `json:"TotalJobItems"`
+	// NumberOfErrors the batches which failed
+	NumberOfErrors int `json:"NumberOfErrors"`
+	// ExtendedStatus the first error of a job with errors
+	ExtendedStatus string `json:"ExtendedStatus"`
+	CreatedDate    string `json:"CreatedDate"`
+	CompletedDate  string `json:"CompletedDate"`
+}
+
+// ClassName the name of the job's Apex class, empty when it has none
+func (j AsyncApexJob) ClassName() string {
+	if j.ApexClass == nil {
+		return ""
+	}
+	return j.ApexClass.Name
+}
+
+// ApexJobError returned when an Apex job failed, was aborted or completed with failed batches
+type ApexJobError struct {
+	Job AsyncApexJob
+}
+
+func (e ApexJobError) Error() string {
+	msg := fmt.Sprintf("apex job %s %s", e.Job.Id, strings.ToLower(string(e.Job.Status)))
+	if e.Job.NumberOfErrors > 0 {
+		msg += fmt.Sprintf(", %d of %d batches failed", e.Job.NumberOfErrors, e.Job.TotalJobItems)
+	}
+	if len(e.Job.ExtendedStatus) > 0 {
+		msg += ": " + e.Job.ExtendedStatus
+	}
+	return msg
+}
+
+// GetApexJob gets the AsyncApexJob with the id returned by e.g. Database.executeBatch, ErrNoRows is returned when
+// there is none
+func GetApexJob(ctx context.Context, h *RequestHelper, id string) (*AsyncApexJob, error) {
+	if !ValidId(id) {
+		return nil, fmt.Errorf("invalid apex job id %q", id)
+	}
+	return FindOne[AsyncApexJob](ctx, h, SelectFor[AsyncApexJob]("AsyncApexJob")+" WHERE Id = "+QuoteString(id))
+}
+
+// ListActiveApexJobs lists the jobs of an Apex class which haven't finished, of every class when className is empty,
+// e.g. to check a batch isn't already running before starting it
+func ListActiveApexJobs(ctx context.Context, h *RequestHelper, className string) ([]AsyncApexJob, error) {
+	q := SelectFor[AsyncApexJob]("AsyncApexJob") + " WHERE Status IN " +
+		InList(ApexJobHolding, ApexJobQueued, ApexJobPreparing, ApexJobProcessing)
+	if len(className) > 0 {
+		q += " AND ApexClass.Name = " + QuoteString(className)
+	}
+	return queryAll[AsyncApexJob](ctx, h, q+" ORDER BY CreatedDate")
+}
+
+// WaitForApexJob polls an Apex job, waiting between polls as policy sets, until it finishes, returning the job
+// - an ApexJobError is returned with the job when it failed, was aborted or had failed batches
+// - an error is returned if policy's MaxElapsed, the Poll timeout of h or ctx is done first
+func WaitForApexJob(ctx context.Context, h *RequestHelper, id string, policy BackoffPolicy) (*AsyncApexJob, error) {
+	ctx, cancel := withTimeout(ctx, h.timeouts.Poll)
+	defer cancel()
+	b := policy.BackOff()
+	for {
+		job, err := GetApexJob(ctx, h, id)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status.Done() {
+			if job.Status != ApexJobCompleted || job.NumberOfErrors > 0 {
+				return job, ApexJobError{Job: *job}
+			}
+			return job, nil
+		}
+		wait := b.NextBackOff()
+		if wait == backoff.Stop {
+			return job, fmt.Errorf("apex job %s still %s after waiting", id, strings.ToLower(string(job.Status)))
+		}
+		if !sleepContext(ctx, wait) {
+			return job, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetApexJob(t *testing.T) {
+	var gotQuery string
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		gotQuery = req.URL.Query().Get("q")
+		return newResponse(http.StatusOK, `{"totalSize":1,"done":true,"records":[{"Id":"707xx0000000001","JobType":"BatchApex",`+
+			`"Status":"Processing","ApexClass":{"Name":"AccountBackfill"},"JobItemsProcessed":3,"TotalJobItems":10}]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := GetApexJob(context.Background(), h, "707xx0000000001")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT Id, JobType, Status, ApexClass.Name, MethodName, JobItemsProcessed, TotalJobItems, "+
+		"NumberOfErrors, ExtendedStatus, CreatedDate, CompletedDate FROM AsyncApexJob WHERE Id = '707xx0000000001'", gotQuery)
+	assert.Equal(t, ApexJobProcessing, got.Status)
+	assert.Equal(t, "AccountBackfill", got.ClassName())
+	assert.Equal(t, 3, got.JobItemsProcessed)
+}
+
+func TestWaitForApexJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []string
+		wantStatus ApexJobStatus
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "completes  job returned",
+			statuses:   []string{`"Status":"Queued"`, `"Status":"Processing"`, `"Status":"Completed"`},
+			wantStatus: ApexJobCompleted,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "completes with failed batches  ApexJobError returned",
+			statuses:   []string{`"Status":"Completed","NumberOfErrors":2,"TotalJobItems":10,"ExtendedStatus":"First error: bad value"`},
+			wantStatus: ApexJobCompleted,
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var jobErr ApexJobError
+				return assert.ErrorAs(t, err, &jobErr, i...) &&
+					assert.EqualError(t, err, "apex job 707xx0000000001 completed, 2 of 10 batches failed: First error: bad value", i...)
+			},
+		},
+		{
+			name:       "aborted  ApexJobError returned",
+			statuses:   []string{`"Status":"Aborted"`},
+			wantStatus: ApexJobAborted,
+			wantErr:    assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				calls++
+				return newResponse(http.StatusOK, `{"totalSize":1,"done":true,"records":[{"Id":"707xx0000000001",`+
+					tt.statuses[calls-1]+`}]}`), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := WaitForApexJob(context.Background(), h, "707xx0000000001", BackoffPolicy{Initial: time.Millisecond})
+
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, len(tt.statuses), calls)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Approval action types
+const (
+	ApprovalSubmit  = "Submit"
+	ApprovalApprove = "Approve"
+	ApprovalReject  = "Reject"
+)
+
+// ApprovalRequest a request to submit a record for approval, or to approve or reject a pending work item
+type ApprovalRequest struct {
+	// ActionType ApprovalSubmit, ApprovalApprove or ApprovalReject
+	ActionType string `json:"actionType"`
+	// ContextId the record to submit, or the work item to approve or reject
+	ContextId string `json:"contextId"`
+	Comments  string `json:"comments,omitempty"`
+	// NextApproverIds the approver when the process asks the submitter to choose one
+	NextApproverIds []string `json:"nextApproverIds,omitempty"`
+	// ContextActorId the user submitting, defaults to the current user
+	ContextActorId string `json:"contextActorId,omitempty"`
+	// ProcessDefinitionNameOrId the approval process to submit to, defaults to the first whose entry criteria match
+	ProcessDefinitionNameOrId string `json:"processDefinitionNameOrId,omitempty"`
+	SkipEntryCriteria         bool   `json:"skipEntryCriteria,omitempty"`
+}
+
+// ApprovalResult the outcome of an ApprovalRequest
+type ApprovalResult struct {
+	IsSuccess  bool   `json:"isSuccess"`
+	EntityId   string `json:"entityId"`
+	InstanceId string `json:"instanceId"`
+	// InstanceStatus e.g. Pending, Approved or Rejected
+	InstanceStatus string     `json:"instanceStatus"`
+	ActorIds       []string   `json:"actorIds"`
+	NewWorkitemIds []string   `json:"newWorkitemIds"`
+	Errors         []ApiError `json:"errors"`
+}
+
+// ApprovalProcess an approval process, listed by ListApprovalProcesses
+type ApprovalProcess struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Object      string `json:"object"`
+	Description string `json:"description"`
+	SortOrder   int    `json:"sortOrder"`
+}
+
+// ApprovalWorkItem a pending approval assigned to a user
+type ApprovalWorkItem struct {
+	Id              string `json:"Id"`
+	ActorId         string `json:"ActorId"`
+	CreatedDate     string `json:"CreatedDate"`
+	ProcessInstance struct {
+		TargetObjectId    string `json:"TargetObjectId"`
+		ProcessDefinition struct {
+			Name string `json:"Name"`
+		} `json:"ProcessDefinition"`
+	} `json:"ProcessInstance"`
+}
+
+// ProcessApprovals submits records for approval and approves or rejects work items, returning a result per request in
+// order. The results are returned along with an error when any request failed
+func ProcessApprovals(ctx context.Context, h *RequestHelper, requests ...ApprovalRequest) ([]ApprovalResult, error) {
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("requests need to be provided")
+	}
+	reqUrl, err := h.dataUrl(ctx, "/process/approvals")
+	if err != nil {
+		return nil, err
+	}
+	results, err := sendJson[[]ApprovalResult](ctx, h, http.MethodPost, reqUrl, map[string]any{"requests": requests})
+	if err != nil {
+		return nil, err
+	}
+	failed := 0
+	for _, r := range *results {
+		if !r.IsSuccess {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return *results, fmt.Errorf("salesforce approval failed for %d of %d requests", failed, len(requests))
+	}
+	return *results, nil
+}
+
+// SubmitForApproval submits a record for approval with comments
+func SubmitForApproval(ctx context.Context, h *RequestHelper, recordId, comments string) (*ApprovalResult, error) {
+	return processApproval(ctx, h, ApprovalRequest{ActionType: ApprovalSubmit, ContextId: recordId, Comments: comments})
+}
+
+// Approve approves a pending work item, see ListPendingApprovals
+func Approve(ctx context.Context, h *RequestHelper, workItemId, comments string) (*ApprovalResult, error) {
+	return processApproval(ctx, h, ApprovalRequest{ActionType: ApprovalApprove, ContextId: workItemId, Comments: comments})
+}
+
+// Reject rejects a pending work item, see ListPendingApprovals
+func Reject(ctx context.Context, h *RequestHelper, workItemId, comments string) (*ApprovalResult, error) {
+	return processApproval(ctx, h, ApprovalRequest{ActionType: ApprovalReject, ContextId: workItemId, Comments: comments})
+}
+
+func processApproval(ctx context.Context, h *RequestHelper, req ApprovalRequest) (*ApprovalResult, error) {
+	results, err := ProcessApprovals(ctx, h, req)
+	if len(results) == 0 {
+		return nil, err
+	}
+	return &results[0], err
+}
+
+// ListApprovalProcesses lists the approval processes of each object, by object name
+func ListApprovalProcesses(ctx context.Context, h *RequestHelper) (map[string][]ApprovalProcess, error) {
+	reqUrl, err := h.dataUrl(ctx, "/process/approvals")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := getJson[struct {
+		Approvals map[string][]ApprovalProcess `json:"approvals"`
+	}](ctx, h, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Approvals, nil
+}
+
+// ListPendingApprovals lists the work items pending approval by actorId, a user or a queue, oldest first
+func ListPendingApprovals(ctx context.Context, h *RequestHelper, actorId string) ([]ApprovalWorkItem, error) {
+	if !ValidId(actorId) {
+		return nil, fmt.Errorf("invalid actorId %q", actorId)
+	}
+	q := "SELECT Id, ActorId, CreatedDate, ProcessInstance.TargetObjectId, ProcessInstance.ProcessDefinition.Name " +
+		"FROM ProcessInstanceWorkitem WHERE ActorId = " + QuoteString(actorId) + " AND ProcessInstance.Status = 'Pending' " +
+		"ORDER BY CreatedDate"
+	return queryAll[ApprovalWorkItem](ctx, h, q)
+}
@@ -0,0 +1,78 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestApprove(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    string
+		want    *ApprovalResult
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "approved  result returned",
+			resp:    `[{"isSuccess":true,"entityId":"001xx0000000001","instanceId":"04gxx0000000001","instanceStatus":"Approved","errors":null}]`,
+			want:    &ApprovalResult{IsSuccess: true, EntityId: "001xx0000000001", InstanceId: "04gxx0000000001", InstanceStatus: "Approved"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "not approved  result and error returned",
+			resp:    `[{"isSuccess":false,"errors":[{"statusCode":"INVALID_CROSS_REFERENCE_KEY","message":"invalid"}]}]`,
+			want:    &ApprovalResult{Errors: []ApiError{{StatusCode: "INVALID_CROSS_REFERENCE_KEY", Message: "invalid"}}},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/process/approvals", req.URL.Path)
+				b, _ := io.ReadAll(req.Body)
+				assert.JSONEq(t, `{"requests":[{"actionType":"Approve","contextId":"04ixx0000000001","comments":"ok"}]}`, string(b))
+				return newResponse(http.StatusOK, tt.resp), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := Approve(context.Background(), h, "04ixx0000000001", "ok")
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListPendingApprovals(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Contains(t, req.URL.Query().Get("q"), "FROM ProcessInstanceWorkitem WHERE ActorId = '005xx0000000001'")
+		return newResponse(http.StatusOK, `{"totalSize":1,"done":true,"records":[{"Id":"04ixx0000000001","ActorId":"005xx0000000001",
+			"ProcessInstance":{"TargetObjectId":"001xx0000000001","ProcessDefinition":{"Name":"Discount"}}}]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := ListPendingApprovals(context.Background(), h, "005xx0000000001")
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, "04ixx0000000001", got[0].Id)
+		assert.Equal(t, "001xx0000000001", got[0].ProcessInstance.TargetObjectId)
+		assert.Equal(t, "Discount", got[0].ProcessInstance.ProcessDefinition.Name)
+	}
+
+	_, err = ListPendingApprovals(context.Background(), h, "' OR Id != '")
+	assert.Error(t, err)
+}
+
+func TestListApprovalProcesses(t *testing.T) {
+	client := newHttpClientMock(newResponse(http.StatusOK, `{"approvals":{"Opportunity":[{"id":"04axx0000000001","name":"Discount","object":"Opportunity","sortOrder":1}]}}`), nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := ListApprovalProcesses(context.Background(), h)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]ApprovalProcess{"Opportunity": {{Id: "04axx0000000001", Name: "Discount", Object: "Opportunity", SortOrder: 1}}}, got)
+}
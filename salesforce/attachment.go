@@ -0,0 +1,42 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// AttachmentMeta the fields of a legacy Attachment uploaded with UploadAttachment
+type AttachmentMeta struct {
+	// Name the file name, required
+	Name string `json:"Name"`
+	// ParentId the record the file is attached to, required
+	ParentId    string `json:"ParentId"`
+	ContentType string `json:"ContentType,omitempty"`
+	Description string `json:"Description,omitempty"`
+	IsPrivate   bool   `json:"IsPrivate,omitempty"`
+	OwnerId     string `json:"OwnerId,omitempty"`
+}
+
+// UploadAttachment creates a legacy Attachment from r, returning its id
+// - the Body is sent as multipart/form-data, streamed from r rather than base64 encoded in memory as Post would
+func UploadAttachment(ctx context.Context, h *RequestHelper, meta AttachmentMeta, r io.Reader) (string, error) {
+	if len(meta.Name) == 0 {
+		return "", fmt.Errorf("Name needs to be provided")
+	}
+	if !ValidId(meta.ParentId) {
+		return "", fmt.Errorf("invalid ParentId %q", meta.ParentId)
+	}
+	entity, err := json.Marshal(meta)
+	if err != nil {
+		return "", fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	return uploadBlob(ctx, h, "Attachment", "entity_attachment", "Body", meta.Name, entity, r)
+}
+
+// DownloadAttachment streams the Body of a legacy Attachment to w as binary, rather than base64, returning the number
+// of bytes written
+func DownloadAttachment(ctx context.Context, h *RequestHelper, id string, w io.Writer) (int64, error) {
+	return DownloadBlob(ctx, h, "Attachment", id, "Body", w)
+}
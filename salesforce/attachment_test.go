@@ -0,0 +1,83 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUploadAttachment(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    AttachmentMeta
+		resp    *http.Response
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "uploaded  id returned",
+			meta:    AttachmentMeta{Name: "photo.jpg", ParentId: "500xx0000000001", ContentType: "image/jpeg"},
+			resp:    newResponse(http.StatusCreated, `{"id":"00Pxx0000000001","success":true,"errors":[]}`),
+			want:    "00Pxx0000000001",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "no parent  error returned",
+			meta:    AttachmentMeta{Name: "photo.jpg"},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "failure result  error returned",
+			meta:    AttachmentMeta{Name: "photo.jpg", ParentId: "500xx0000000001", ContentType: "image/jpeg"},
+			resp:    newResponse(http.StatusCreated, `{"success":false,"errors":[{"statusCode":"STORAGE_LIMIT_EXCEEDED"}]}`),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/sobjects/Attachment", req.URL.Path)
+				_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+				assert.NoError(t, err)
+
+				parts := map[string]string{}
+				mr := multipart.NewReader(req.Body, params["boundary"])
+				for part, err := mr.NextPart(); err == nil; part, err = mr.NextPart() {
+					b, _ := io.ReadAll(part)
+					parts[part.FormName()] = string(b)
+				}
+				assert.JSONEq(t, `{"Name":"photo.jpg","ParentId":"500xx0000000001","ContentType":"image/jpeg"}`, parts["entity_attachment"])
+				assert.Equal(t, "\xff\xd8\xff", parts["Body"])
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := UploadAttachment(context.Background(), h, tt.meta, strings.NewReader("\xff\xd8\xff"))
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/services/data/v55.0/sobjects/Attachment/00Pxx0000000001/Body", req.URL.Path)
+		return newResponse(http.StatusOK, "\xff\xd8\xff"), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	var buf bytes.Buffer
+	n, err := DownloadAttachment(context.Background(), h, "00Pxx0000000001", &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.Equal(t, "\xff\xd8\xff", buf.String())
+}
@@ -0,0 +1,66 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AuthError returned when the token or introspect endpoint responds with an OAuth error, e.g. invalid_grant
+type AuthError struct {
+	// Endpoint the oauth endpoint that returned the error, token or introspect
+	Endpoint   string
+	StatusCode int
+	// Code the OAuth error code e.g. invalid_grant, invalid_client_id
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (a AuthError) Error() string {
+	msg := fmt.Sprintf("salesforce %s endpoint error - status code: %d", a.Endpoint, a.StatusCode)
+	if len(a.Code) > 0 {
+		msg += fmt.Sprintf(", error: %s", a.Code)
+	}
+	if len(a.Description) > 0 {
+		msg += fmt.Sprintf(", description: %s", a.Description)
+	}
+	if hint := a.Hint(); len(hint) > 0 {
+		msg += " - " + hint
+	}
+	return msg
+}
+
+// Hint a suggested fix for the common connected app misconfigurations, empty when there isn't one
+func (a AuthError) Hint() string {
+	desc := strings.ToLower(a.Description)
+	switch {
+	case strings.Contains(desc, "user hasn't approved this consumer"), strings.Contains(desc, "user is not admin approved"):
+		return "pre-authorise the user for the connected app, via a profile or permission set with Permitted Users set to admin approved"
+	case strings.Contains(desc, "audience is invalid"):
+		return "the JWT aud claim doesn't match the org, check the Environment token param or the hostname credential"
+	case strings.Contains(desc, "invalid assertion"), strings.Contains(desc, "invalid signature"):
+		return "the JWT couldn't be verified, check the private key matches the connected app certificate and the SigningMethod token param"
+	case strings.Contains(desc, "expired"):
+		return "the JWT was rejected as expired, check the host clock or set the ClockSkew token param"
+	case a.Code == "invalid_client_id", a.Code == "invalid_client", strings.Contains(desc, "client identifier invalid"):
+		return "check the clientId credential is the connected app consumer key, and the clientSecret for introspection"
+	case strings.Contains(desc, "user account is locked"), strings.Contains(desc, "inactive user"):
+		return "the integration user is locked or inactive"
+	}
+	return ""
+}
+
+// rejectsCredentials whether salesforce rejected the credentials, which may have been rotated since they were loaded
+func (a AuthError) rejectsCredentials() bool {
+	return a.StatusCode == http.StatusBadRequest || a.StatusCode == http.StatusUnauthorized || a.StatusCode == http.StatusForbidden
+}
+
+// newAuthError decodes the OAuth error/error_description from body, the status alone is kept when body isn't json
+func newAuthError(endpoint string, statusCode int, body []byte) AuthError {
+	a := AuthError{}
+	_ = json.Unmarshal(body, &a)
+	a.Endpoint = endpoint
+	a.StatusCode = statusCode
+	return a
+}
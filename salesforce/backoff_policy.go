@@ -0,0 +1,131 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"github.com/cenkalti/backoff/v4"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Jitter how the waits of a backoff are randomised, so clients which failed together, e.g. many Lambdas after a
+// salesforce blip, don't all retry together
+type Jitter string
+
+const (
+	// JitterNone waits exactly the backoff interval, the default of BackoffPolicy
+	JitterNone Jitter = ""
+	// JitterFull waits a random time between 0 and the backoff interval, spreading retries the most
+	JitterFull Jitter = "full"
+	// JitterEqual waits half the backoff interval plus a random time up to the other half, keeping a minimum wait
+	JitterEqual Jitter = "equal"
+)
+
+// apply randomises the wait d
+func (j Jitter) apply(d time.Duration) time.Duration {
+	switch j {
+	case JitterFull:
+		return time.Duration(rand.Int63n(int64(d) + 1))
+	case JitterEqual:
+		return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+	return d
+}
+
+// jitterBackOff a backoff whose waits are randomised by jitter
+type jitterBackOff struct {
+	backoff.BackOff
+	jitter Jitter
+}
+
+func (b jitterBackOff) NextBackOff() time.Duration {
+	d := b.BackOff.NextBackOff()
+	if d == backoff.Stop {
+		return d
+	}
+	return b.jitter.apply(d)
+}
+
+// WithJitter randomises the waits of b by j, b unchanged for JitterNone
+func WithJitter(b backoff.BackOff, j Jitter) backoff.BackOff {
+	if j == JitterNone {
+		return b
+	}
+	return jitterBackOff{BackOff: b, jitter: j}
+}
+
+// BackoffPolicy the exponential backoff between retries of a request, tunable per environment. A zero field takes the
+// default of TokenParams' default backoff: 500ms initially, growing 1.5 times per retry up to 1 minute, giving up
+// after 15 minutes. Event subscriptions reconnect with their own defaults, see ReconnectBackOff
+type BackoffPolicy struct {
+	// Initial the wait before the first retry
+	Initial time.Duration
+	// MaxInterval the longest wait between retries
+	MaxInterval time.Duration
+	// MaxElapsed the time after which no more retries are made, a negative value retries until the context is done
+	MaxElapsed time.Duration
+	// Multiplier the factor each wait grows by
+	Multiplier float64
+	// MaxRetries the retries after which no more are made, however long they took, a negative value leaves only
+	// MaxElapsed
+	MaxRetries int
+	// Jitter how each wait is randomised, none by default
+	Jitter Jitter
+}
+
+// BackOff an exponential backoff following the policy. The policy can also be set as TokenParams BackoffPolicy so token
+// fetches and requests retry alike
+func (p BackoffPolicy) BackOff() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	b.RandomizationFactor = 0
+	if p.Initial > 0 {
+		b.InitialInterval = p.Initial
+	}
+	if p.MaxInterval > 0 {
+		b.MaxInterval = p.MaxInterval
+	}
+	if p.MaxElapsed > 0 {
+		b.MaxElapsedTime = p.MaxElapsed
+	} else if p.MaxElapsed < 0 {
+		b.MaxElapsedTime = 0
+	}
+	if p.Multiplier > 0 {
+		b.Multiplier = p.Multiplier
+	}
+	b.Reset()
+	if p.MaxRetries > 0 {
+		return WithJitter(backoff.WithMaxRetries(b, uint64(p.MaxRetries)), p.Jitter)
+	}
+	return WithJitter(b, p.Jitter)
+}
+
+// SetBackoffPolicy retries requests which fail transiently, with a network error or a 429, 502, 503 or 504 response,
+// waiting between retries as p sets. Only requests which are safe to repeat are retried: those other than POST whose
+// body can be resent
+func (h *RequestHelper) SetBackoffPolicy(p BackoffPolicy) *RequestHelper {
+	h.backoffPolicy = &p
+	return h
+}
+
+// retryable whether req can be sent again, a POST may have created a record before failing
+func retryable(req *http.Request) bool {
+	if req.Method == http.MethodPost {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// transientFailure whether a request failing with resp or err may succeed if sent again
+func transientFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBackoffPolicy_BackOff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy BackoffPolicy
+		want   []time.Duration
+	}{
+		{
+			name:   "zero policy  defaults",
+			policy: BackoffPolicy{},
+			want:   []time.Duration{500 * time.Millisecond, 750 * time.Millisecond, 1125 * time.Millisecond},
+		},
+		{
+			name:   "configured policy  capped at max interval",
+			policy: BackoffPolicy{Initial: time.Second, MaxInterval: 3 * time.Second, Multiplier: 2},
+			want:   []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.policy.BackOff()
+			var got []time.Duration
+			for range tt.want {
+				got = append(got, b.NextBackOff())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBackoffPolicy_MaxElapsed(t *testing.T) {
+	b := BackoffPolicy{Initial: time.Millisecond, MaxElapsed: time.Millisecond}.BackOff()
+	time.Sleep(2 * time.Millisecond)
+	assert.Equal(t, backoff.Stop, b.NextBackOff())
+}
+
+func TestRequestHelper_SetBackoffPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		statuses  []int
+		want      int
+		wantCalls int
+	}{
+		{
+			name:      "get  unavailable then ok  retried",
+			method:    http.MethodGet,
+			statuses:  []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			want:      http.StatusOK,
+			wantCalls: 3,
+		},
+		{
+			name:      "patch  body resent",
+			method:    http.MethodPatch,
+			statuses:  []int{http.StatusBadGateway, http.StatusNoContent},
+			want:      http.StatusNoContent,
+			wantCalls: 2,
+		},
+		{
+			name:      "post  not retried",
+			method:    http.MethodPost,
+			statuses:  []int{http.StatusServiceUnavailable, http.StatusCreated},
+			want:      http.StatusServiceUnavailable,
+			wantCalls: 1,
+		},
+		{
+			name:      "bad request  not retried",
+			method:    http.MethodGet,
+			statuses:  []int{http.StatusBadRequest, http.StatusOK},
+			want:      http.StatusBadRequest,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				if req.Body != nil {
+					body, _ := io.ReadAll(req.Body)
+					assert.Equal(t, `{"Name":"Acme"}`, string(body))
+				}
+				calls++
+				return newResponse(tt.statuses[calls-1], ""), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+			h.SetBackoffPolicy(BackoffPolicy{Initial: time.Millisecond})
+
+			var body io.Reader
+			if tt.method != http.MethodGet {
+				body = bytes.NewReader([]byte(`{"Name":"Acme"}`))
+			}
+			req, err := h.newRequest(context.Background(), tt.method, "https://org/services/data/v55.0/sobjects/Account", body)
+			assert.NoError(t, err)
+
+			resp, err := h.do(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp.StatusCode)
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		jitter   Jitter
+		min, max time.Duration
+	}{
+		{name: "none  exact interval", jitter: JitterNone, min: time.Second, max: time.Second},
+		{name: "full  up to interval", jitter: JitterFull, min: 0, max: time.Second},
+		{name: "equal  half to full interval", jitter: JitterEqual, min: 500 * time.Millisecond, max: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := WithJitter(backoff.NewConstantBackOff(time.Second), tt.jitter)
+			for i := 0; i < 100; i++ {
+				d := b.NextBackOff()
+				assert.GreaterOrEqual(t, d, tt.min)
+				assert.LessOrEqual(t, d, tt.max)
+			}
+		})
+	}
+}
+
+func TestWithJitter_Stop(t *testing.T) {
+	b := WithJitter(&backoff.StopBackOff{}, JitterFull)
+	assert.Equal(t, backoff.Stop, b.NextBackOff())
+}
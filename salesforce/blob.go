@@ -0,0 +1,127 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// DownloadBlob streams the binary of a blob field e.g. ContentVersion VersionData or Attachment Body to w, returning the
+// number of bytes written
+// - the body is copied as it is read rather than buffered, so w can be a file or an upload to S3 e.g. through an
+// io.Pipe
+// - SetMaxResponseSize still applies, returning a ResponseTooLargeError once exceeded
+func DownloadBlob(ctx context.Context, h *RequestHelper, object, id, blobField string, w io.Writer) (int64, error) {
+	body, err := openBlob(ctx, h, object, id, blobField)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, fmt.Errorf("unable to download %s %s of %s: %w", object, blobField, id, err)
+	}
+	return n, nil
+}
+
+// openBlob sends a request for the binary of a blob field, returning the response body for the caller to read and close
+func openBlob(ctx context.Context, h *RequestHelper, object, id, blobField string) (io.ReadCloser, error) {
+	if !ValidId(id) {
+		return nil, fmt.Errorf("invalid id %q", id)
+	}
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/sobjects/%s/%s/%s", url.PathEscape(object), id, url.PathEscape(blobField)))
+	if err != nil {
+		return nil, err
+	}
+	req, err := h.newRequest(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Del("Content-Type")
+
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// uploadBlob creates a record of object with a blob field, e.g. ContentVersion VersionData, returning its id
+// - the record is sent as multipart/form-data, the entity json in the entityPart part and the binary streamed from r
+// in the blobField part, so the binary isn't base64 encoded in memory
+func uploadBlob(ctx context.Context, h *RequestHelper, object, entityPart, blobField, fileName string, entity []byte, r io.Reader) (string, error) {
+	reqUrl, err := h.dataUrl(ctx, "/sobjects/"+url.PathEscape(object))
+	if err != nil {
+		return "", err
+	}
+
+	pr, pw := io.Pipe()
+	// closed once the request is sent, so the writer stops if the body wasn't read in full
+	defer pr.Close()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeBlobParts(mw, entityPart, entity, blobField, fileName, r))
+	}()
+
+	req, err := h.newRequest(ctx, http.MethodPost, reqUrl, pr)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := h.do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+
+	var parsedResp *PostResponse
+	if err = json.NewDecoder(resp.Body).Decode(&parsedResp); err != nil {
+		return "", fmt.Errorf("unable to parse response body: %w", err)
+	}
+	if parsedResp == nil || !parsedResp.Success {
+		return "", fmt.Errorf("salesforce returns a failure result uploading %s", fileName)
+	}
+	return parsedResp.Id, nil
+}
+
+// writeBlobParts writes the entity json part and the binary part of a blob upload
+func writeBlobParts(mw *multipart.Writer, entityPart string, entity []byte, blobField, fileName string, r io.Reader) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="%s"`, entityPart)},
+		"Content-Type":        {"application/json"},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = part.Write(entity); err != nil {
+		return err
+	}
+
+	part, err = mw.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="%s"; filename="%s"`, blobField, strings.ReplaceAll(fileName, `"`, ""))},
+		"Content-Type":        {"application/octet-stream"},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(part, r); err != nil {
+		return fmt.Errorf("unable to read file: %w", err)
+	}
+	return mw.Close()
+}
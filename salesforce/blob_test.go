@@ -0,0 +1,68 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+)
+
+func TestDownloadBlob(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		resp    *http.Response
+		maxSize int64
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "ok  binary written",
+			id:      "068xx0000000001",
+			resp:    newResponse(http.StatusOK, "%PDF-1.4"),
+			want:    "%PDF-1.4",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid id  error returned",
+			id:      "../Account",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "not found  error returned",
+			id:      "068xx0000000001",
+			resp:    newResponse(http.StatusNotFound, `[{"errorCode":"NOT_FOUND"}]`),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "larger than max size  ResponseTooLargeError returned",
+			id:      "068xx0000000001",
+			resp:    newResponse(http.StatusOK, "%PDF-1.4"),
+			maxSize: 4,
+			want:    "%PDF",
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, errors.As(err, &ResponseTooLargeError{}), i...)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/sobjects/ContentVersion/068xx0000000001/VersionData", req.URL.Path)
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+			h.SetMaxResponseSize(tt.maxSize)
+
+			var buf bytes.Buffer
+			n, err := DownloadBlob(context.Background(), h, "ContentVersion", tt.id, "VersionData", &buf)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, buf.String())
+			assert.Equal(t, int64(len(tt.want)), n)
+		})
+	}
+}
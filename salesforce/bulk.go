@@ -0,0 +1,474 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Bulk API 2.0 ingest job operations
+const (
+	BulkInsert     = "insert"
+	BulkUpdate     = "update"
+	BulkUpsert     = "upsert"
+	BulkDelete     = "delete"
+	BulkHardDelete = "hardDelete"
+)
+
+// Bulk API 2.0 job states
+const (
+	BulkJobOpen           = "Open"
+	BulkJobUploadComplete = "UploadComplete"
+	BulkJobInProgress     = "InProgress"
+	BulkJobAborted        = "Aborted"
+	BulkJobComplete       = "JobComplete"
+	BulkJobFailed         = "Failed"
+)
+
+// Bulk API 2.0 ingest job results, see OpenBulkIngestResults
+const (
+	BulkSuccessfulResults  = "successfulResults"
+	BulkFailedResults      = "failedResults"
+	BulkUnprocessedRecords = "unprocessedrecords"
+)
+
+// defaultBulkThreshold the number of records above which UpsertMany and DeleteWhere use Bulk API 2.0 jobs, see
+// WithBulkThreshold
+const defaultBulkThreshold = 2000
+
+// defaultBulkPollInterval the wait between polls of a bulk job when no interval is given
+const defaultBulkPollInterval = 5 * time.Second
+
+// bulkMaxUploadBytes the most csv uploaded to a single ingest job, salesforce accepts 150MB once base64 encoded
+const bulkMaxUploadBytes = 100_000_000
+
+// bulkNull the csv value bulk jobs write as null, an empty value leaves the field unchanged
+const bulkNull = "#N/A"
+
+// bulkUnprocessedStatusCode the error reported against records a bulk job didn't process, e.g. as the job was aborted
+const bulkUnprocessedStatusCode = "UNPROCESSED"
+
+// BulkJob a Bulk API 2.0 ingest or query job
+type BulkJob struct {
+	Id                     string `json:"id"`
+	Object                 string `json:"object"`
+	Operation              string `json:"operation"`
+	ExternalIdFieldName    string `json:"externalIdFieldName"`
+	Query                  string `json:"query"`
+	State                  string `json:"state"`
+	ErrorMessage           string `json:"errorMessage"`
+	NumberRecordsProcessed int    `json:"numberRecordsProcessed"`
+	NumberRecordsFailed    int    `json:"numberRecordsFailed"`
+	CreatedDate            string `json:"createdDate"`
+	SystemModstamp         string `json:"systemModstamp"`
+}
+
+type bulkIngestJobRequest struct {
+	Object              string `json:"object"`
+	Operation           string `json:"operation"`
+	ExternalIdFieldName string `json:"externalIdFieldName,omitempty"`
+	ContentType         string `json:"contentType"`
+	LineEnding          string `json:"lineEnding"`
+}
+
+type bulkJobState struct {
+	State string `json:"state"`
+}
+
+// CreateBulkIngestJob creates a Bulk API 2.0 ingest job of operation, e.g. BulkUpsert, on object. extIdField is the
+// key of an upsert and empty otherwise. Upload its csv with UploadBulkIngestData, then close it with
+// CloseBulkIngestJob for salesforce to process it
+func CreateBulkIngestJob(ctx context.Context, h *RequestHelper, object, operation, extIdField string) (*BulkJob, error) {
+	reqUrl, err := h.dataUrl(ctx, "/jobs/ingest")
+	if err != nil {
+		return nil, err
+	}
+	return sendJson[BulkJob](ctx, h, http.MethodPost, reqUrl, bulkIngestJobRequest{
+		Object:              object,
+		Operation:           operation,
+		ExternalIdFieldName: extIdField,
+		ContentType:         "CSV",
+		LineEnding:          "LF",
+	})
+}
+
+// UploadBulkIngestData uploads the csv records of an open ingest job, a header row of field names then a row per
+// record. A job takes a single upload of up to 150MB once base64 encoded, ~100MB of csv
+func UploadBulkIngestData(ctx context.Context, h *RequestHelper, id string, r io.Reader) error {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/jobs/ingest/%s/batches", url.PathEscape(id)))
+	if err != nil {
+		return err
+	}
+	req, err := h.newRequest(ctx, http.MethodPut, reqUrl, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/csv")
+
+	resp, err := h.do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// CloseBulkIngestJob marks the upload of an ingest job complete, queueing it to be processed
+func CloseBulkIngestJob(ctx context.Context, h *RequestHelper, id string) (*BulkJob, error) {
+	return setBulkIngestJobState(ctx, h, id, BulkJobUploadComplete)
+}
+
+// AbortBulkIngestJob aborts an ingest job, records already processed aren't rolled back
+func AbortBulkIngestJob(ctx context.Context, h *RequestHelper, id string) (*BulkJob, error) {
+	return setBulkIngestJobState(ctx, h, id, BulkJobAborted)
+}
+
+func setBulkIngestJobState(ctx context.Context, h *RequestHelper, id, state string) (*BulkJob, error) {
+	reqUrl, err := h.dataUrl(ctx, "/jobs/ingest/"+url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	return sendJson[BulkJob](ctx, h, http.MethodPatch, reqUrl, bulkJobState{State: state})
+}
+
+// GetBulkIngestJob fetches the state and progress of an ingest job
+func GetBulkIngestJob(ctx context.Context, h *RequestHelper, id string) (*BulkJob, error) {
+	reqUrl, err := h.dataUrl(ctx, "/jobs/ingest/"+url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	return getJson[BulkJob](ctx, h, reqUrl)
+}
+
+// WaitForBulkIngestJob polls an ingest job every interval, 5 seconds when 0, until it completes, returning the job, or
+// an error if it failed, was aborted or ctx, or the Poll timeout of h, is done first. The results of the records a
+// failed job processed are still available
+func WaitForBulkIngestJob(ctx context.Context, h *RequestHelper, id string, interval time.Duration) (*BulkJob, error) {
+	return waitForBulkJob(ctx, h, interval, func(ctx context.Context) (*BulkJob, error) {
+		return GetBulkIngestJob(ctx, h, id)
+	})
+}
+
+// waitForBulkJob polls a bulk job with get until it completes, see WaitForBulkIngestJob
+func waitForBulkJob(ctx context.Context, h *RequestHelper, interval time.Duration, get func(context.Context) (*BulkJob, error)) (*BulkJob, error) {
+	if interval <= 0 {
+		interval = defaultBulkPollInterval
+	}
+	ctx, cancel := withTimeout(ctx, h.timeouts.Poll)
+	defer cancel()
+	for {
+		job, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch job.State {
+		case BulkJobComplete:
+			return job, nil
+		case BulkJobFailed, BulkJobAborted:
+			return job, fmt.Errorf("salesforce bulk job %s %s: %s", job.Id, strings.ToLower(job.State), job.ErrorMessage)
+		}
+		if !sleepContext(ctx, interval) {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// OpenBulkIngestResults sends a request for the results of an ingest job, BulkSuccessfulResults,
+// BulkFailedResults or BulkUnprocessedRecords, returning the csv body for the caller to read and close
+// - successful results have sf__Id and sf__Created columns, failed results sf__Id and sf__Error, before the uploaded
+// columns of each record
+func OpenBulkIngestResults(ctx context.Context, h *RequestHelper, id, results string) (io.ReadCloser, error) {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/jobs/ingest/%s/%s/", url.PathEscape(id), results))
+	if err != nil {
+		return nil, err
+	}
+	req, err := h.newRequest(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Del("Content-Type")
+	req.Header.Set("Accept", "text/csv")
+
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// bulkIngest loads rows through Bulk API 2.0 ingest jobs, starting a job whenever the csv reaches the upload limit,
+// and collects a CollectionResult per row, in the order they were added
+type bulkIngest struct {
+	h          *RequestHelper
+	object     string
+	operation  string
+	extIdField string
+	// key the column identifying a row in the job results
+	key      string
+	header   []string
+	interval time.Duration
+	// maxBytes the most csv uploaded to a job
+	maxBytes int
+
+	buf bytes.Buffer
+	// rows the indexes into results of the rows of the open job by their key, in the order they were added
+	rows    map[string][]int
+	results []CollectionResult
+}
+
+func newBulkIngest(h *RequestHelper, object, operation, extIdField, key string, header []string) *bulkIngest {
+	return &bulkIngest{
+		h:          h,
+		object:     object,
+		operation:  operation,
+		extIdField: extIdField,
+		key:        key,
+		header:     header,
+		maxBytes:   bulkMaxUploadBytes,
+		rows:       map[string][]int{},
+	}
+}
+
+// add adds a row of values in the order of the header, sending the rows already added first when it would take the
+// job over the upload limit
+func (b *bulkIngest) add(ctx context.Context, row []string) error {
+	line, err := csvLine(row)
+	if err != nil {
+		return fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	if len(b.rows) > 0 && b.buf.Len()+len(line) > b.maxBytes {
+		if err = b.flush(ctx); err != nil {
+			return err
+		}
+	}
+	if b.buf.Len() == 0 {
+		header, err := csvLine(b.header)
+		if err != nil {
+			return fmt.Errorf("unable to create salesforce payload: %w", err)
+		}
+		b.buf.Write(header)
+	}
+	b.buf.Write(line)
+
+	key := row[slices.Index(b.header, b.key)]
+	b.rows[key] = append(b.rows[key], len(b.results))
+	b.results = append(b.results, CollectionResult{Errors: []ApiError{{
+		StatusCode: bulkUnprocessedStatusCode,
+		Message:    "record not processed by salesforce bulk job",
+	}}})
+	return nil
+}
+
+// flush sends the rows added since the last flush as a job, waits for it and records the results of its rows
+func (b *bulkIngest) flush(ctx context.Context) error {
+	if len(b.rows) == 0 {
+		return nil
+	}
+	defer func() {
+		b.buf.Reset()
+		b.rows = map[string][]int{}
+	}()
+
+	job, err := CreateBulkIngestJob(ctx, b.h, b.object, b.operation, b.extIdField)
+	if err != nil {
+		return err
+	}
+	if err = UploadBulkIngestData(ctx, b.h, job.Id, bytes.NewReader(b.buf.Bytes())); err != nil {
+		// the job would otherwise stay open until salesforce times it out
+		_, _ = AbortBulkIngestJob(context.WithoutCancel(ctx), b.h, job.Id)
+		return err
+	}
+	if _, err = CloseBulkIngestJob(ctx, b.h, job.Id); err != nil {
+		return err
+	}
+	job, err = WaitForBulkIngestJob(ctx, b.h, job.Id, b.interval)
+	if job == nil {
+		return err
+	}
+	// a failed job still reports the records it processed
+	return errors.Join(err, b.readResults(ctx, job.Id, BulkSuccessfulResults), b.readResults(ctx, job.Id, BulkFailedResults))
+}
+
+// readResults records the results of a job, read a row at a time
+func (b *bulkIngest) readResults(ctx context.Context, id, results string) error {
+	body, err := OpenBulkIngestResults(ctx, b.h, id, results)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	r := csv.NewReader(body)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to parse response body: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(name)] = i
+	}
+	keyColumn, ok := columns[strings.ToLower(b.key)]
+	if !ok {
+		return fmt.Errorf("salesforce bulk job %s results are missing the %s column", id, b.key)
+	}
+
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to parse response body: %w", err)
+		}
+		key := row[keyColumn]
+		indexes := b.rows[key]
+		if len(indexes) == 0 {
+			continue
+		}
+		b.rows[key] = indexes[1:]
+
+		result := CollectionResult{Id: csvColumn(row, columns, "sf__Id")}
+		if errMsg := csvColumn(row, columns, "sf__Error"); len(errMsg) > 0 {
+			result.Errors = []ApiError{bulkApiError(errMsg)}
+		} else {
+			result.Success = true
+			result.Created = csvColumn(row, columns, "sf__Created") == "true"
+		}
+		b.results[indexes[0]] = result
+	}
+}
+
+// csvColumn the value of column name in row, empty if the column or value is missing
+func csvColumn(row []string, columns map[string]int, name string) string {
+	i, ok := columns[strings.ToLower(name)]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
+// bulkApiError parses the sf__Error of a failed record, e.g. "REQUIRED_FIELD_MISSING:Required fields are missing:
+// [Name]:Name --"
+func bulkApiError(s string) ApiError {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "--"))
+	statusCode, rest, ok := strings.Cut(s, ":")
+	if !ok {
+		return ApiError{Message: s}
+	}
+	apiErr := ApiError{StatusCode: statusCode, Message: rest}
+	if i := strings.LastIndex(rest, ":"); i >= 0 {
+		apiErr.Message = rest[:i]
+		for _, f := range strings.Split(rest[i+1:], ",") {
+			if f = strings.TrimSpace(f); len(f) > 0 {
+				apiErr.Fields = append(apiErr.Fields, f)
+			}
+		}
+	}
+	return apiErr
+}
+
+// csvLine formats values as a line of csv
+func csvLine(values []string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(values); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// bulkRecordFields flattens record into the csv values of a bulk job, by field name. Relationship fields are keyed by
+// their path, e.g. Account.External_Id__c, and null values are written as #N/A so they clear the field
+func bulkRecordFields(h *RequestHelper, name, extIdField string, record any) (map[string]string, error) {
+	b, err := collectionRecord(h, name, extIdField, record)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := decodeExportRecord(b)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	if err = flattenBulkFields("", withoutAttributes(fields).(map[string]any), values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func flattenBulkFields(prefix string, fields map[string]any, values map[string]string) error {
+	for k, v := range fields {
+		switch v := v.(type) {
+		case nil:
+			values[prefix+k] = bulkNull
+		case map[string]any:
+			if err := flattenBulkFields(prefix+k+".", v, values); err != nil {
+				return err
+			}
+		default:
+			value, err := csvValue(v)
+			if err != nil {
+				return err
+			}
+			values[prefix+k] = value
+		}
+	}
+	return nil
+}
+
+// bulkUpsert upserts records by extIdField through Bulk API 2.0 jobs, see UpsertMany
+func bulkUpsert[T any](ctx context.Context, h *RequestHelper, name, extIdField string, records []T) ([]CollectionResult, error) {
+	rows := make([]map[string]string, 0, len(records))
+	fields := map[string]bool{}
+	for _, record := range records {
+		values, err := bulkRecordFields(h, name, extIdField, record)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+		}
+		if _, ok := values[extIdField]; !ok {
+			return nil, fmt.Errorf("record is missing its external id %s", extIdField)
+		}
+		for field := range values {
+			fields[field] = true
+		}
+		rows = append(rows, values)
+	}
+	header := make([]string, 0, len(fields))
+	for field := range fields {
+		header = append(header, field)
+	}
+	slices.Sort(header)
+
+	b := newBulkIngest(h, name, BulkUpsert, extIdField, extIdField, header)
+	row := make([]string, len(header))
+	for _, values := range rows {
+		for i, field := range header {
+			// a field other records set is left unchanged
+			row[i] = values[field]
+		}
+		if err := b.add(ctx, row); err != nil {
+			return b.results, err
+		}
+	}
+	err := b.flush(ctx)
+	return b.results, err
+}
@@ -0,0 +1,256 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bulkLocatorDone the Sforce-Locator of the last page of a query job's results
+const bulkLocatorDone = "null"
+
+type bulkQueryJobRequest struct {
+	Operation   string `json:"operation"`
+	Query       string `json:"query"`
+	ContentType string `json:"contentType"`
+	LineEnding  string `json:"lineEnding"`
+}
+
+// CreateBulkQueryJob creates a Bulk API 2.0 query job for q, queryAll includes deleted and archived records. Wait for
+// it with WaitForBulkQueryJob, then page through its results with GetBulkQueryResults
+func CreateBulkQueryJob(ctx context.Context, h *RequestHelper, q string, queryAll bool) (*BulkJob, error) {
+	reqUrl, err := h.dataUrl(ctx, "/jobs/query")
+	if err != nil {
+		return nil, err
+	}
+	operation := "query"
+	if queryAll {
+		operation = "queryAll"
+	}
+	return sendJson[BulkJob](ctx, h, http.MethodPost, reqUrl, bulkQueryJobRequest{
+		Operation:   operation,
+		Query:       q,
+		ContentType: "CSV",
+		LineEnding:  "LF",
+	})
+}
+
+// GetBulkQueryJob fetches the state and progress of a query job
+func GetBulkQueryJob(ctx context.Context, h *RequestHelper, id string) (*BulkJob, error) {
+	reqUrl, err := h.dataUrl(ctx, "/jobs/query/"+url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	return getJson[BulkJob](ctx, h, reqUrl)
+}
+
+// AbortBulkQueryJob aborts a query job
+func AbortBulkQueryJob(ctx context.Context, h *RequestHelper, id string) (*BulkJob, error) {
+	reqUrl, err := h.dataUrl(ctx, "/jobs/query/"+url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	return sendJson[BulkJob](ctx, h, http.MethodPatch, reqUrl, bulkJobState{State: BulkJobAborted})
+}
+
+// WaitForBulkQueryJob polls a query job every interval, 5 seconds when 0, until it completes, returning the job, or an
+// error if it failed, was aborted or ctx, or the Poll timeout of h, is done first
+func WaitForBulkQueryJob(ctx context.Context, h *RequestHelper, id string, interval time.Duration) (*BulkJob, error) {
+	return waitForBulkJob(ctx, h, interval, func(ctx context.Context) (*BulkJob, error) {
+		return GetBulkQueryJob(ctx, h, id)
+	})
+}
+
+// OpenBulkQueryResults sends a request for a page of up to maxRecords of a completed query job's results, all that
+// fit in a response when 0, from locator, the first page when empty. It returns the csv body for the caller to read
+// and close, and the locator of the next page, empty after the last page
+func OpenBulkQueryResults(ctx context.Context, h *RequestHelper, id, locator string, maxRecords int) (io.ReadCloser, string, error) {
+	params := url.Values{}
+	if len(locator) > 0 {
+		params.Set("locator", locator)
+	}
+	if maxRecords > 0 {
+		params.Set("maxRecords", strconv.Itoa(maxRecords))
+	}
+	path := fmt.Sprintf("/jobs/query/%s/results", url.PathEscape(id))
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	reqUrl, err := h.dataUrl(ctx, path)
+	if err != nil {
+		return nil, "", err
+	}
+	req, err := h.newRequest(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Del("Content-Type")
+	req.Header.Set("Accept", "text/csv")
+
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+	next := resp.Header.Get("Sforce-Locator")
+	if next == bulkLocatorDone {
+		next = ""
+	}
+	return resp.Body, next, nil
+}
+
+// GetBulkQueryResults fetches a page of a completed query job's results, see OpenBulkQueryResults, decoding each row
+// as E
+// - columns are matched to E by json name, relationship columns such as Account.Name to the nested field
+// - empty values are null, and values are converted to the bool and number fields of E, other values are strings
+func GetBulkQueryResults[E any](ctx context.Context, h *RequestHelper, id, locator string, maxRecords int) ([]E, string, error) {
+	body, next, err := OpenBulkQueryResults(ctx, h, id, locator, maxRecords)
+	if err != nil {
+		return nil, "", err
+	}
+	defer body.Close()
+
+	records, err := decodeCsvRecords[E](h, body)
+	if err != nil {
+		return nil, "", err
+	}
+	return records, next, nil
+}
+
+// decodeCsvRecords decodes the rows of a csv with a header row as E
+func decodeCsvRecords[E any](h *RequestHelper, r io.Reader) ([]E, error) {
+	t := reflect.TypeOf((*E)(nil)).Elem()
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return []E{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse response body: %w", err)
+	}
+	paths := make([][]string, len(header))
+	types := make([]reflect.Type, len(header))
+	for i, column := range header {
+		paths[i] = strings.Split(column, ".")
+		types[i] = csvFieldType(t, paths[i])
+	}
+
+	records := make([]E, 0)
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse response body: %w", err)
+		}
+		fields := map[string]any{}
+		for i, value := range row {
+			setPath(fields, paths[i], csvJsonValue(types[i], value))
+		}
+		b, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse response body: %w", err)
+		}
+		var record E
+		if err = h.unmarshal(b, &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+// setPath sets value at path within fields, creating the nested objects of a relationship path
+func setPath(fields map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := fields[key].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			fields[key] = child
+		}
+		fields = child
+	}
+	fields[path[len(path)-1]] = value
+}
+
+// csvFieldType the type of the field of t at path, matched by json name case-insensitively, nil when t has no such
+// field. Pointers and Nullable are unwrapped to the type of their value
+func csvFieldType(t reflect.Type, path []string) reflect.Type {
+	t = csvValueType(t)
+	if len(path) == 0 {
+		return t
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+		if f.Anonymous && len(name) == 0 {
+			if ft := csvFieldType(f.Type, path); ft != nil {
+				return ft
+			}
+			continue
+		}
+		if len(name) == 0 {
+			name = f.Name
+		}
+		if f.IsExported() && strings.EqualFold(name, path[0]) {
+			return csvFieldType(f.Type, path[1:])
+		}
+	}
+	return nil
+}
+
+// csvValueType unwraps pointers and Nullable, a map keyed by bool, to the type of their value
+func csvValueType(t reflect.Type) reflect.Type {
+	for {
+		switch {
+		case t.Kind() == reflect.Pointer:
+			t = t.Elem()
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.Bool:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// csvJsonValue the json value of a csv value for a field of type t, null when empty, a bool or number for those
+// kinds of field when it parses as one, otherwise a string
+func csvJsonValue(t reflect.Type, s string) any {
+	if len(s) == 0 {
+		return nil
+	}
+	if t == nil {
+		return s
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return json.Number(s)
+		}
+	}
+	return s
+}
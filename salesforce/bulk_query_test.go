@@ -0,0 +1,48 @@
+package salesforce
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDecodeCsvRecords(t *testing.T) {
+	type owner struct {
+		Name string `json:"Name"`
+	}
+	type opportunity struct {
+		Id          string        `json:"Id"`
+		Amount      float64       `json:"Amount"`
+		Probability *int          `json:"Probability"`
+		IsWon       bool          `json:"IsWon"`
+		Description NullString    `json:"Description,omitempty"`
+		StageCode   string        `json:"Stage_Code__c"`
+		Owner       owner         `json:"Owner"`
+		Count       Nullable[int] `json:"Count__c,omitempty"`
+	}
+	h, _ := NewRequestHelper(new(HttpClientMock), newTokenGetterMock("token", nil), "https://org", 55)
+
+	body := "\"Id\",\"Amount\",\"Probability\",\"IsWon\",\"Description\",\"Stage_Code__c\",\"Owner.Name\",\"Count__c\"\n" +
+		"\"006xx0000000001\",\"1500.5\",\"90\",\"true\",\"\",\"10\",\"Ada\",\"3\"\n"
+	got, err := decodeCsvRecords[opportunity](h, strings.NewReader(body))
+	assert.NoError(t, err)
+	probability := 90
+	assert.Equal(t, []opportunity{{
+		Id:          "006xx0000000001",
+		Amount:      1500.5,
+		Probability: &probability,
+		IsWon:       true,
+		Description: NewNullNullable[string](),
+		StageCode:   "10",
+		Owner:       owner{Name: "Ada"},
+		Count:       NewNullableWithValue(3),
+	}}, got)
+
+	maps, err := decodeCsvRecords[map[string]any](h, strings.NewReader("\"Id\",\"Amount\"\n\"006xx0000000001\",\"10\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"Id": "006xx0000000001", "Amount": "10"}}, maps)
+
+	empty, err := decodeCsvRecords[opportunity](h, strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+}
@@ -0,0 +1,232 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// bulkIngestServer responds to the requests of bulk ingest jobs, failing uploaded rows with a Name of "bad" and
+// returning the results of each job in reverse order
+type bulkIngestServer struct {
+	mu      sync.Mutex
+	jobs    int
+	uploads [][][]string
+	state   string
+}
+
+func newBulkIngestServer() *bulkIngestServer {
+	return &bulkIngestServer{state: BulkJobComplete}
+}
+
+func (s *bulkIngestServer) Do(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := strings.TrimPrefix(req.URL.Path, "/services/data/v55.0/jobs/ingest")
+	switch {
+	case req.Method == http.MethodPost && path == "":
+		s.jobs++
+		return newResponse(200, fmt.Sprintf(`{"id":"750-%d","state":"Open"}`, s.jobs)), nil
+	case req.Method == http.MethodPut && strings.HasSuffix(path, "/batches"):
+		rows, err := csv.NewReader(req.Body).ReadAll()
+		if err != nil {
+			return newResponse(400, ""), nil
+		}
+		s.uploads = append(s.uploads, rows)
+		return newResponse(201, ""), nil
+	case req.Method == http.MethodPatch:
+		return newResponse(200, `{"state":"UploadComplete"}`), nil
+	case req.Method == http.MethodGet && strings.HasSuffix(path, "/"+BulkSuccessfulResults+"/"):
+		return newResponse(200, s.results(true)), nil
+	case req.Method == http.MethodGet && strings.HasSuffix(path, "/"+BulkFailedResults+"/"):
+		return newResponse(200, s.results(false)), nil
+	case req.Method == http.MethodGet:
+		return newResponse(200, fmt.Sprintf(`{"id":"750-%d","state":%q,"errorMessage":"boom"}`, s.jobs, s.state)), nil
+	}
+	return newResponse(404, ""), nil
+}
+
+// results the csv of the successful or failed rows of the last upload
+func (s *bulkIngestServer) results(successful bool) string {
+	rows := s.uploads[len(s.uploads)-1]
+	header, name := rows[0], slices.Index(rows[0], "Name")
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if successful {
+		_ = w.Write(append([]string{"sf__Id", "sf__Created"}, header...))
+	} else {
+		_ = w.Write(append([]string{"sf__Id", "sf__Error"}, header...))
+	}
+	for i := len(rows) - 1; i > 0; i-- {
+		bad := name >= 0 && rows[i][name] == "bad"
+		switch {
+		case successful && !bad:
+			_ = w.Write(append([]string{fmt.Sprintf("001-%d", i), "true"}, rows[i]...))
+		case !successful && bad:
+			_ = w.Write(append([]string{"", "REQUIRED_FIELD_MISSING:Required fields are missing: [Industry]:Industry --"}, rows[i]...))
+		}
+	}
+	w.Flush()
+	return b.String()
+}
+
+func TestUpsertMany_Bulk(t *testing.T) {
+	server := newBulkIngestServer()
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(server.Do)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	records := []upsertStub{
+		{ExternalId: "ext-0", Name: "ok"},
+		{ExternalId: "ext-1", Name: "bad"},
+		{ExternalId: "ext-2", Name: "ok, \"quoted\""},
+	}
+	got, err := UpsertMany(context.Background(), h, "Account", "External_Id__c", records, WithBulkThreshold(2))
+	assert.NoError(t, err)
+
+	// create, upload, close, poll, successful and failed results
+	client.AssertNumberOfCalls(t, "Do", 6)
+	assert.Equal(t, [][]string{
+		{"External_Id__c", "Name"},
+		{"ext-0", "ok"},
+		{"ext-1", "bad"},
+		{"ext-2", "ok, \"quoted\""},
+	}, server.uploads[0])
+	assert.Equal(t, []CollectionResult{
+		{Id: "001-1", Success: true, Created: true},
+		{Errors: []ApiError{{StatusCode: "REQUIRED_FIELD_MISSING", Message: "Required fields are missing: [Industry]", Fields: []string{"Industry"}}}},
+		{Id: "001-3", Success: true, Created: true},
+	}, got)
+}
+
+func TestUpsertMany_BulkThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []RequestOption
+		wantBulk bool
+	}{
+		{name: "over threshold  bulk job", opts: []RequestOption{WithBulkThreshold(2)}, wantBulk: true},
+		{name: "at threshold  collections", opts: []RequestOption{WithBulkThreshold(3)}, wantBulk: false},
+		{name: "disabled  collections", opts: []RequestOption{WithBulkThreshold(0)}, wantBulk: false},
+		{name: "all or none  collections", opts: []RequestOption{WithBulkThreshold(2), WithAllOrNone()}, wantBulk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newBulkIngestServer()
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				if strings.Contains(req.URL.Path, "/jobs/ingest") {
+					return server.Do(req)
+				}
+				return collectionResponder(req), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			records := []upsertStub{{ExternalId: "ext-0", Name: "ok"}, {ExternalId: "ext-1", Name: "ok"}, {ExternalId: "ext-2", Name: "ok"}}
+			got, err := UpsertMany(context.Background(), h, "Account", "External_Id__c", records, tt.opts...)
+			assert.NoError(t, err)
+			assert.Len(t, got, 3)
+			assert.Equal(t, tt.wantBulk, server.jobs > 0)
+		})
+	}
+}
+
+func TestUpsertMany_BulkJobFailed(t *testing.T) {
+	server := newBulkIngestServer()
+	server.state = BulkJobFailed
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(server.Do)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	records := []upsertStub{{ExternalId: "ext-0", Name: "ok"}, {ExternalId: "ext-1", Name: "bad"}}
+	got, err := UpsertMany(context.Background(), h, "Account", "External_Id__c", records, WithBulkThreshold(1))
+	assert.ErrorContains(t, err, "salesforce bulk job 750-1 failed: boom")
+	// the records the failed job processed are still reported
+	assert.Len(t, got, 2)
+	assert.True(t, got[0].Success)
+	assert.Equal(t, "REQUIRED_FIELD_MISSING", got[1].Errors[0].StatusCode)
+}
+
+func TestBulkRecordFields(t *testing.T) {
+	type contactStub struct {
+		LastName string            `json:"LastName"`
+		Email    *string           `json:"Email"`
+		Age      int               `json:"Age__c"`
+		Active   bool              `json:"Active__c"`
+		Account  map[string]string `json:"Account"`
+		Formula  string            `json:"Formula__c" sf:"readonly"`
+		Tags     []string          `json:"Tags__c,omitempty"`
+	}
+	h, _ := NewRequestHelper(new(HttpClientMock), newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := bulkRecordFields(h, "Contact", "External_Id__c", contactStub{
+		LastName: "Smith",
+		Age:      42,
+		Active:   true,
+		Account:  map[string]string{"External_Id__c": "acc-1"},
+		Formula:  "dropped",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"LastName":               "Smith",
+		"Email":                  bulkNull,
+		"Age__c":                 "42",
+		"Active__c":              "true",
+		"Account.External_Id__c": "acc-1",
+	}, got)
+}
+
+func TestBulkApiError(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want ApiError
+	}{
+		{
+			name: "with fields",
+			in:   "REQUIRED_FIELD_MISSING:Required fields are missing: [Name, Type]:Name,Type --",
+			want: ApiError{StatusCode: "REQUIRED_FIELD_MISSING", Message: "Required fields are missing: [Name, Type]", Fields: []string{"Name", "Type"}},
+		},
+		{
+			name: "without fields",
+			in:   "INVALID_CROSS_REFERENCE_KEY:invalid cross reference id:--",
+			want: ApiError{StatusCode: "INVALID_CROSS_REFERENCE_KEY", Message: "invalid cross reference id"},
+		},
+		{
+			name: "unstructured",
+			in:   "something went wrong",
+			want: ApiError{Message: "something went wrong"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, bulkApiError(tt.in))
+		})
+	}
+}
+
+func TestBulkIngest_SplitsJobs(t *testing.T) {
+	server := newBulkIngestServer()
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(server.Do)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	b := newBulkIngest(h, "Account", BulkDelete, "", "Id", []string{"Id"})
+	b.maxBytes = 45
+	for _, id := range []string{"001000000000001AAA", "001000000000002AAA", "001000000000003AAA"} {
+		assert.NoError(t, b.add(context.Background(), []string{id}))
+	}
+	assert.NoError(t, b.flush(context.Background()))
+	assert.Equal(t, 2, server.jobs)
+	assert.Len(t, b.results, 3)
+	for _, r := range b.results {
+		assert.True(t, r.Success)
+	}
+}
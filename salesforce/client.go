@@ -0,0 +1,208 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/ellogroup/ello-golang-cache/cache"
+	"github.com/ellogroup/ello-golang-cache/driver"
+	"go.uber.org/zap"
+	"io"
+	"time"
+)
+
+// Api the operations of a Client, for services to depend on, and mock, as a single dependency. The generic helpers,
+// e.g. Query and Get, take its Helper
+type Api interface {
+	Helper() *RequestHelper
+	Warm(ctx context.Context) error
+	Create(ctx context.Context, object string, record any, opts ...RequestOption) (string, error)
+	Update(ctx context.Context, object, id string, record any, opts ...RequestOption) error
+	Upsert(ctx context.Context, object, extIdField, extId string, record any, opts ...RequestOption) (*UpsertResponse, error)
+	Delete(ctx context.Context, object, id string) error
+	Exists(ctx context.Context, object, id string) (bool, error)
+	Describe(ctx context.Context, object string) (*ObjectDescribe, error)
+	GetRecords(ctx context.Context, refs []RecordRef, opts ...RequestOption) (map[string]map[string]any, error)
+	SendComposite(ctx context.Context, c *Composite) (*CompositeResponse, error)
+	CreateBulkIngestJob(ctx context.Context, object, operation, extIdField string) (*BulkJob, error)
+	UploadBulkIngestData(ctx context.Context, id string, r io.Reader) error
+	CloseBulkIngestJob(ctx context.Context, id string) (*BulkJob, error)
+	AbortBulkIngestJob(ctx context.Context, id string) (*BulkJob, error)
+	WaitForBulkIngestJob(ctx context.Context, id string, interval time.Duration) (*BulkJob, error)
+	OpenBulkIngestResults(ctx context.Context, id, results string) (io.ReadCloser, error)
+	CreateBulkQueryJob(ctx context.Context, q string, queryAll bool) (*BulkJob, error)
+	WaitForBulkQueryJob(ctx context.Context, id string, interval time.Duration) (*BulkJob, error)
+	OpenBulkQueryResults(ctx context.Context, id, locator string, maxRecords int) (io.ReadCloser, string, error)
+}
+
+// ClientParams the configuration of NewClient
+type ClientParams struct {
+	TokenParams
+	// BaseUrl optional, the token's instance url is used when empty
+	BaseUrl    string
+	ApiVersion int
+	// Driver optional, shares the token between instances, see NewTokenCacheWithDriver
+	Driver driver.Cache[int, cache.RecordCacheItem[Token]]
+	Logger *zap.Logger
+	// BackoffPolicy optional, retries transient request failures, see SetBackoffPolicy
+	BackoffPolicy *BackoffPolicy
+	// Timeouts optional, see SetTimeouts
+	Timeouts Timeouts
+	// RequestQueue optional, see SetRequestQueue
+	RequestQueue *RequestQueue
+	// MaxResponseSize optional, see SetMaxResponseSize
+	MaxResponseSize int64
+}
+
+// Client bundles the token cache and RequestHelper of an org behind one constructor, with the REST, composite and Bulk
+// API 2.0 operations sent with them
+type Client struct {
+	token  *TokenCache
+	helper *RequestHelper
+}
+
+var _ Api = (*Client)(nil)
+
+// NewClient creates a Client, its token cache and RequestHelper configured from p
+func NewClient(p ClientParams) (*Client, error) {
+	log := p.Logger
+	if log == nil {
+		log = zap.NewNop()
+	}
+	var tc *TokenCache
+	var err error
+	if p.Driver != nil {
+		tc, err = NewTokenCacheWithDriver(p.TokenParams, p.Driver, log)
+	} else {
+		tc, err = NewTokenCacheWithLogger(p.TokenParams, log)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var h *RequestHelper
+	if len(p.BaseUrl) > 0 {
+		h, err = NewRequestHelper(p.HttpClient, tc, p.BaseUrl, p.ApiVersion)
+	} else {
+		h, err = NewRequestHelperWithInstanceUrl(p.HttpClient, tc, p.ApiVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.BackoffPolicy != nil {
+		h.SetBackoffPolicy(*p.BackoffPolicy)
+	}
+	if p.RequestQueue != nil {
+		h.SetRequestQueue(p.RequestQueue)
+	}
+	if p.Metrics != nil {
+		h.SetMetrics(p.Metrics)
+	}
+	h.SetTimeouts(p.Timeouts).SetMaxResponseSize(p.MaxResponseSize)
+	return &Client{token: tc, helper: h}, nil
+}
+
+// Helper the RequestHelper of the client, for the generic helpers, e.g. salesforce.Query[Account](ctx, c.Helper(), q)
+func (c *Client) Helper() *RequestHelper {
+	return c.helper
+}
+
+// Token the token cache of the client
+func (c *Client) Token() *TokenCache {
+	return c.token
+}
+
+// Warm fetches a token if one isn't already cached, see TokenCache Warm
+func (c *Client) Warm(ctx context.Context) error {
+	return c.token.Warm(ctx)
+}
+
+// Close stops the background token refresh, see TokenCache Close
+func (c *Client) Close() {
+	c.token.Close()
+}
+
+// Create creates a record of object, returning its id, see Post
+func (c *Client) Create(ctx context.Context, object string, record any, opts ...RequestOption) (string, error) {
+	return Post(ctx, c.helper, object, record, opts...)
+}
+
+// Update updates the record of object with id, see Patch
+func (c *Client) Update(ctx context.Context, object, id string, record any, opts ...RequestOption) error {
+	_, err := Patch(ctx, c.helper, object, id, record, opts...)
+	return err
+}
+
+// Upsert creates or updates the record of object by an external id, see Upsert
+func (c *Client) Upsert(ctx context.Context, object, extIdField, extId string, record any, opts ...RequestOption) (*UpsertResponse, error) {
+	return Upsert(ctx, c.helper, object, extIdField, extId, record, opts...)
+}
+
+// Delete deletes the record of object with id
+func (c *Client) Delete(ctx context.Context, object, id string) error {
+	return Delete(ctx, c.helper, object, id)
+}
+
+// Exists whether the record of object with id exists
+func (c *Client) Exists(ctx context.Context, object, id string) (bool, error) {
+	return Exists(ctx, c.helper, object, id)
+}
+
+// Describe describes object, cached for the life of the client
+func (c *Client) Describe(ctx context.Context, object string) (*ObjectDescribe, error) {
+	return c.helper.cachedDescribe(ctx, object)
+}
+
+// GetRecords fetches records of any objects by id, see GetRecords
+func (c *Client) GetRecords(ctx context.Context, refs []RecordRef, opts ...RequestOption) (map[string]map[string]any, error) {
+	return GetRecords(ctx, c.helper, refs, opts...)
+}
+
+// SendComposite sends a composite request built with NewComposite
+func (c *Client) SendComposite(ctx context.Context, composite *Composite) (*CompositeResponse, error) {
+	return composite.Send(ctx, c.helper)
+}
+
+// CreateBulkIngestJob creates a Bulk API 2.0 ingest job, see CreateBulkIngestJob
+func (c *Client) CreateBulkIngestJob(ctx context.Context, object, operation, extIdField string) (*BulkJob, error) {
+	return CreateBulkIngestJob(ctx, c.helper, object, operation, extIdField)
+}
+
+// UploadBulkIngestData uploads the csv of an ingest job, see UploadBulkIngestData
+func (c *Client) UploadBulkIngestData(ctx context.Context, id string, r io.Reader) error {
+	return UploadBulkIngestData(ctx, c.helper, id, r)
+}
+
+// CloseBulkIngestJob queues an ingest job to be processed, see CloseBulkIngestJob
+func (c *Client) CloseBulkIngestJob(ctx context.Context, id string) (*BulkJob, error) {
+	return CloseBulkIngestJob(ctx, c.helper, id)
+}
+
+// AbortBulkIngestJob aborts an ingest job
+func (c *Client) AbortBulkIngestJob(ctx context.Context, id string) (*BulkJob, error) {
+	return AbortBulkIngestJob(ctx, c.helper, id)
+}
+
+// WaitForBulkIngestJob polls an ingest job until it completes, see WaitForBulkIngestJob
+func (c *Client) WaitForBulkIngestJob(ctx context.Context, id string, interval time.Duration) (*BulkJob, error) {
+	return WaitForBulkIngestJob(ctx, c.helper, id, interval)
+}
+
+// OpenBulkIngestResults opens the csv of an ingest job's results, see OpenBulkIngestResults
+func (c *Client) OpenBulkIngestResults(ctx context.Context, id, results string) (io.ReadCloser, error) {
+	return OpenBulkIngestResults(ctx, c.helper, id, results)
+}
+
+// CreateBulkQueryJob creates a Bulk API 2.0 query job, see CreateBulkQueryJob
+func (c *Client) CreateBulkQueryJob(ctx context.Context, q string, queryAll bool) (*BulkJob, error) {
+	return CreateBulkQueryJob(ctx, c.helper, q, queryAll)
+}
+
+// WaitForBulkQueryJob polls a query job until it completes, see WaitForBulkQueryJob
+func (c *Client) WaitForBulkQueryJob(ctx context.Context, id string, interval time.Duration) (*BulkJob, error) {
+	return WaitForBulkQueryJob(ctx, c.helper, id, interval)
+}
+
+// OpenBulkQueryResults opens the csv of a page of a query job's results, see OpenBulkQueryResults. The generic
+// GetBulkQueryResults decodes a page, passed Helper
+func (c *Client) OpenBulkQueryResults(ctx context.Context, id, locator string, maxRecords int) (io.ReadCloser, string, error) {
+	return OpenBulkQueryResults(ctx, c.helper, id, locator, maxRecords)
+}
@@ -0,0 +1,110 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/csv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       ClientParams
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "base url  created",
+			p: ClientParams{
+				TokenParams: TokenParams{HttpClient: new(HttpClientMock), Credentials: new(CredentialsProviderMock), Refresh: TokenRefreshOnDemand},
+				BaseUrl:     "https://org.my.salesforce.com",
+				ApiVersion:  59,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "no api version  error returned",
+			p: ClientParams{
+				TokenParams: TokenParams{HttpClient: new(HttpClientMock), Credentials: new(CredentialsProviderMock), Refresh: TokenRefreshOnDemand},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "invalid token params  error returned",
+			p:       ClientParams{ApiVersion: 59},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewClient(tt.p)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assert.NotNil(t, got.Helper())
+			assert.NotNil(t, got.Token())
+		})
+	}
+}
+
+func TestClient_Describe(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusOK, `{"name":"Account","fields":[{"name":"Name"}]}`), nil
+	}).Once()
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	c := &Client{helper: h}
+
+	for i := 0; i < 2; i++ {
+		got, err := c.Describe(context.Background(), "Account")
+		assert.NoError(t, err)
+		assert.Equal(t, "Account", got.Name)
+	}
+	client.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestClient_Upsert(t *testing.T) {
+	client := newHttpClientMock(newResponse(http.StatusOK, `{"id":"001xx0000000001","success":true,"created":true}`), nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	c := &Client{helper: h}
+
+	got, err := c.Upsert(context.Background(), "Account", "External_Id__c", "ext-1",
+		map[string]any{"Name": "Acme", "Industry": "Energy"}, WithFieldMask("Name"), WithAutoAssign(""))
+	assert.NoError(t, err)
+	assert.True(t, got.Created)
+
+	req := client.Calls[0].Arguments.Get(0).(*http.Request)
+	body, _ := io.ReadAll(req.Body)
+	assert.JSONEq(t, `{"Name":"Acme"}`, string(body))
+	assert.Equal(t, "TRUE", req.Header.Get("Sforce-Auto-Assign"))
+}
+
+func TestClient_BulkIngest(t *testing.T) {
+	server := newBulkIngestServer()
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(server.Do)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	c := &Client{helper: h}
+	ctx := context.Background()
+
+	job, err := c.CreateBulkIngestJob(ctx, "Account", BulkInsert, "")
+	assert.NoError(t, err)
+	assert.NoError(t, c.UploadBulkIngestData(ctx, job.Id, strings.NewReader("Name\nAcme\n")))
+	_, err = c.CloseBulkIngestJob(ctx, job.Id)
+	assert.NoError(t, err)
+	job, err = c.WaitForBulkIngestJob(ctx, job.Id, time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, BulkJobComplete, job.State)
+
+	results, err := c.OpenBulkIngestResults(ctx, job.Id, BulkSuccessfulResults)
+	assert.NoError(t, err)
+	defer results.Close()
+	rows, err := csv.NewReader(results).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"sf__Id", "sf__Created", "Name"}, {"001-1", "true", "Acme"}}, rows)
+}
@@ -0,0 +1,203 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// collectionsMaxRecords the most records salesforce accepts in a single sObject Collections request
+const collectionsMaxRecords = 200
+
+type collectionRequest struct {
+	AllOrNone bool              `json:"allOrNone"`
+	Records   []json.RawMessage `json:"records"`
+}
+
+// UpsertMany creates or updates records by an external id field, in sObject Collections requests of up to 200 records
+// or, above 2,000 records, Bulk API 2.0 upsert jobs, see WithBulkThreshold
+// - uses the baseUrl, tokenGetter and http client on RequestHelper
+// - returns a CollectionResult per record, in the same order as records, a record failing doesn't fail the others
+// - on a request error the results of the chunks or jobs already sent are returned along with the error
+// - WithAllOrNone rolls back every record when any fails, the results are returned along with an AllOrNoneError
+// - fields tagged sf:"readonly" and the system fields, other than extIdField, are stripped as by Patch and Upsert
+// - in a bulk job a field one record omits and others set is left unchanged on that record, and records are matched
+// to their results by extIdField, so each needs one
+func UpsertMany[T any](ctx context.Context, h *RequestHelper, name, extIdField string, records []T, opts ...RequestOption) ([]CollectionResult, error) {
+	o := newRequestOptions(opts)
+	if err := o.checkAllOrNone(len(records)); err != nil {
+		return nil, err
+	}
+	if o.useBulk(len(records)) {
+		return bulkUpsert(ctx, h, name, extIdField, records)
+	}
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/composite/sobjects/%s/%s", name, extIdField))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CollectionResult, 0, len(records))
+	for start := 0; start < len(records); start += collectionsMaxRecords {
+		end := min(start+collectionsMaxRecords, len(records))
+		chunk, err := sendCollection(ctx, h, http.MethodPatch, reqUrl, name, extIdField, records[start:end], o.allOrNone)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, chunk...)
+	}
+	return results, o.allOrNoneError(results)
+}
+
+// CreateMany creates records, in sObject Collections requests of up to 200 records
+// - records can be of several objects, each record's attributes.type is set from its sf:"type=" tag, or otherwise
+// name, unless it already has one
+// - returns a CollectionResult per record, in the same order as records, a record failing doesn't fail the others
+// - on a request error the results of the chunks already sent are returned along with the error
+// - WithAllOrNone rolls back every record when any fails, the results are returned along with an AllOrNoneError
+// - fields tagged sf:"readonly" are stripped as by Post
+func CreateMany[T any](ctx context.Context, h *RequestHelper, name string, records []T, opts ...RequestOption) ([]CollectionResult, error) {
+	o := newRequestOptions(opts)
+	if err := o.checkAllOrNone(len(records)); err != nil {
+		return nil, err
+	}
+	reqUrl, err := h.dataUrl(ctx, "/composite/sobjects")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CollectionResult, 0, len(records))
+	for start := 0; start < len(records); start += collectionsMaxRecords {
+		end := min(start+collectionsMaxRecords, len(records))
+		chunk, err := sendCollection(ctx, h, http.MethodPost, reqUrl, name, "", records[start:end], o.allOrNone)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, chunk...)
+	}
+	return results, o.allOrNoneError(results)
+}
+
+// sendCollection sends records as a single sObject Collections request, see collectionRecord, extIdField is the key of
+// an upsert and empty for a create
+func sendCollection[T any](ctx context.Context, h *RequestHelper, method, reqUrl, name, extIdField string, records []T, allOrNone bool) ([]CollectionResult, error) {
+	body := collectionRequest{AllOrNone: allOrNone, Records: make([]json.RawMessage, 0, len(records))}
+	for _, record := range records {
+		r, err := collectionRecord(h, name, extIdField, record)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+		}
+		body.Records = append(body.Records, r)
+	}
+	reqBody, err := h.marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+
+	req, err := h.newRequest(ctx, method, reqUrl, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	return collectionResults(h, req, len(records))
+}
+
+// collectionResults sends an sObject Collections request for n records, returning their results
+func collectionResults(h *RequestHelper, req *http.Request, n int) ([]CollectionResult, error) {
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse response body: %w", err)
+	}
+
+	var results []CollectionResult
+	if err = h.unmarshal(resBody, &results); err != nil {
+		return nil, err
+	}
+	if len(results) != n {
+		return nil, fmt.Errorf("salesforce returned %d results for %d records", len(results), n)
+	}
+	return results, nil
+}
+
+// collectionRecord marshals record for an sObject Collections request, stripping its fields tagged sf:"readonly" and,
+// when upserting by extIdField, the system fields salesforce rejects in an update, then adding the attributes type
+// salesforce needs
+func collectionRecord(h *RequestHelper, name, extIdField string, record any) (json.RawMessage, error) {
+	b, err := h.marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	strip := readOnlyFields(reflect.TypeOf(record))
+	if len(extIdField) > 0 {
+		for _, f := range systemFields {
+			// the attributes are kept for their type, and the external id is the key of the record
+			if f != "attributes" && f != extIdField {
+				strip = append(strip, f)
+			}
+		}
+	}
+	if b, err = stripFields(b, strip); err != nil {
+		return nil, err
+	}
+	return withAttributesType(name, record, b)
+}
+
+// withAttributesType sets attributes.type of b, the marshaled record, when the record doesn't already have one, to the
+// type of its sf:"type=" tag or otherwise name
+func withAttributesType(name string, record any, b []byte) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	var attrs Attributes
+	if raw, ok := fields["attributes"]; ok {
+		_ = json.Unmarshal(raw, &attrs)
+	}
+	if len(attrs.Type) > 0 {
+		return b, nil
+	}
+	if tagged := taggedObjectType(reflect.TypeOf(record)); len(tagged) > 0 {
+		name = tagged
+	}
+	if len(name) == 0 {
+		return nil, fmt.Errorf("object name, attributes.type or an sf:\"type=\" tag needs to be provided")
+	}
+	fields["attributes"], _ = json.Marshal(map[string]string{"type": name})
+	return json.Marshal(fields)
+}
+
+// taggedObjectType the object named by an sf:"type=" tag on a field of struct t, e.g.
+//
+//	Attributes salesforce.Attributes `json:"attributes" sf:"type=Account"`
+func taggedObjectType(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < t.NumField(); i++ {
+		for _, opt := range sfTagOptions(t.Field(i)) {
+			if name, ok := strings.CutPrefix(opt, "type="); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,274 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type upsertStub struct {
+	ExternalId string `json:"External_Id__c"`
+	Name       string `json:"Name"`
+}
+
+type taggedContactStub struct {
+	Attributes Attributes `json:"attributes" sf:"type=Contact"`
+	LastName   string     `json:"LastName"`
+}
+
+// collectionResponder responds to a collections request with a result per record, failing records named "bad". When
+// allOrNone is set and a record fails the others are rolled back
+func collectionResponder(req *http.Request) *http.Response {
+	var body collectionRequest
+	_ = json.NewDecoder(req.Body).Decode(&body)
+	results := make([]CollectionResult, 0, len(body.Records))
+	failed := false
+	for i, r := range body.Records {
+		if strings.Contains(string(r), `"Name":"bad"`) {
+			results = append(results, CollectionResult{Errors: []ApiError{{StatusCode: "REQUIRED_FIELD_MISSING", Message: "missing"}}})
+			failed = true
+			continue
+		}
+		results = append(results, CollectionResult{Id: fmt.Sprintf("id-%d", i), Success: true, Created: true})
+	}
+	for i := range results {
+		if body.AllOrNone && failed && results[i].Success {
+			results[i] = CollectionResult{Errors: []ApiError{{StatusCode: rolledBackStatusCode}}}
+		}
+	}
+	b, _ := json.Marshal(results)
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(b))}
+}
+
+func TestUpsertMany(t *testing.T) {
+	tests := []struct {
+		name         string
+		records      int
+		wantRequests int
+	}{
+		{name: "under chunk size  one request", records: 3, wantRequests: 1},
+		{name: "exactly chunk size  one request", records: 200, wantRequests: 1},
+		{name: "over chunk size  chunked", records: 450, wantRequests: 3},
+		{name: "no records  no requests", records: 0, wantRequests: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				return collectionResponder(req), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			records := make([]upsertStub, tt.records)
+			for i := range records {
+				records[i] = upsertStub{ExternalId: fmt.Sprintf("ext-%d", i), Name: "ok"}
+			}
+			if tt.records > 1 {
+				records[1].Name = "bad"
+			}
+
+			got, err := UpsertMany(context.Background(), h, "Account", "External_Id__c", records)
+			assert.NoError(t, err)
+			assert.Len(t, got, tt.records)
+			client.AssertNumberOfCalls(t, "Do", tt.wantRequests)
+			if tt.records > 1 {
+				assert.True(t, got[0].Success)
+				assert.False(t, got[1].Success)
+				assert.Equal(t, "REQUIRED_FIELD_MISSING", got[1].Errors[0].StatusCode)
+			}
+			if tt.wantRequests > 0 {
+				req := client.Calls[0].Arguments.Get(0).(*http.Request)
+				assert.Equal(t, http.MethodPatch, req.Method)
+				assert.Equal(t, "https://org/services/data/v55.0/composite/sobjects/Account/External_Id__c", req.URL.String())
+			}
+		})
+	}
+}
+
+type readOnlyUpsertStub struct {
+	Id          string `json:"Id,omitempty"`
+	ExternalId  string `json:"External_Id__c"`
+	Name        string `json:"Name"`
+	CreatedDate string `json:"CreatedDate,omitempty"`
+	Formula     string `json:"Formula__c,omitempty" sf:"readonly"`
+}
+
+func TestUpsertMany_StripsReadOnly(t *testing.T) {
+	tests := []struct {
+		name       string
+		extIdField string
+		want       string
+	}{
+		{
+			name:       "external id  read only and system fields stripped",
+			extIdField: "External_Id__c",
+			want:       `{"attributes":{"type":"Account"},"External_Id__c":"ext-1","Name":"Acme"}`,
+		},
+		{
+			name:       "by id  id kept",
+			extIdField: "Id",
+			want:       `{"attributes":{"type":"Account"},"Id":"001xx0000000001","External_Id__c":"ext-1","Name":"Acme"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent collectionRequest
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				b, _ := io.ReadAll(req.Body)
+				_ = json.Unmarshal(b, &sent)
+				return collectionResponder(&http.Request{Body: io.NopCloser(bytes.NewReader(b))}), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+			record := readOnlyUpsertStub{Id: "001xx0000000001", ExternalId: "ext-1", Name: "Acme", CreatedDate: "2024-01-01T00:00:00.000+0000", Formula: "x"}
+
+			_, err := UpsertMany(context.Background(), h, "Account", tt.extIdField, []readOnlyUpsertStub{record})
+
+			assert.NoError(t, err)
+			if assert.Len(t, sent.Records, 1) {
+				assert.JSONEq(t, tt.want, string(sent.Records[0]))
+			}
+		})
+	}
+}
+
+func TestUpsertMany_WithAllOrNone(t *testing.T) {
+	tests := []struct {
+		name         string
+		records      []upsertStub
+		wantFailed   []int
+		wantRequests int
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name:         "all records valid  written",
+			records:      []upsertStub{{Name: "ok"}, {Name: "ok"}},
+			wantRequests: 1,
+			wantErr:      assert.NoError,
+		},
+		{
+			name:         "a record fails  AllOrNoneError returned",
+			records:      []upsertStub{{Name: "ok"}, {Name: "bad"}, {Name: "ok"}},
+			wantFailed:   []int{1},
+			wantRequests: 1,
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorAs(t, err, &AllOrNoneError{}, i...)
+			},
+		},
+		{
+			name:         "over chunk size  error returned without requests",
+			records:      make([]upsertStub, 201),
+			wantRequests: 0,
+			wantErr:      assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				return collectionResponder(req), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := UpsertMany(context.Background(), h, "Account", "External_Id__c", tt.records, WithAllOrNone())
+			tt.wantErr(t, err)
+			client.AssertNumberOfCalls(t, "Do", tt.wantRequests)
+
+			var allOrNoneErr AllOrNoneError
+			if errors.As(err, &allOrNoneErr) {
+				assert.Equal(t, tt.wantFailed, allOrNoneErr.Failed())
+				assert.Equal(t, got, allOrNoneErr.Results)
+				assert.True(t, got[0].RolledBack())
+				assert.False(t, got[1].RolledBack())
+			}
+		})
+	}
+}
+
+func TestUpsertMany_RequestError(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		return collectionResponder(req), nil
+	}).Once()
+	client.On("Do", mock.Anything).Return(&http.Response{StatusCode: 500, Body: io.NopCloser(nil)}, nil).Once()
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := UpsertMany(context.Background(), h, "Account", "External_Id__c", make([]upsertStub, 250))
+	assert.Error(t, err)
+	assert.Len(t, got, 200)
+}
+
+func TestWithAttributesType(t *testing.T) {
+	tests := []struct {
+		name   string
+		record any
+		want   string
+	}{
+		{
+			name:   "no attributes  type added",
+			record: upsertStub{Name: "a"},
+			want:   `{"External_Id__c":"","Name":"a","attributes":{"type":"Account"}}`,
+		},
+		{
+			name:   "empty attributes  type added",
+			record: recordStub{Foo: "a"},
+			want:   `{"attributes":{"type":"Account"},"foo":"a"}`,
+		},
+		{
+			name:   "attributes type set  kept",
+			record: recordStub{Attributes: Attributes{Type: "Contact"}, Foo: "a"},
+			want:   `{"attributes":{"type":"Contact","url":""},"foo":"a"}`,
+		},
+		{
+			name:   "type tag  tagged type added",
+			record: taggedContactStub{LastName: "a"},
+			want:   `{"attributes":{"type":"Contact"},"LastName":"a"}`,
+		},
+		{
+			name:   "pointer with type tag  tagged type added",
+			record: &taggedContactStub{LastName: "a"},
+			want:   `{"attributes":{"type":"Contact"},"LastName":"a"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := json.Marshal(tt.record)
+			got, err := withAttributesType("Account", tt.record, b)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestCreateMany(t *testing.T) {
+	var body collectionRequest
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/services/data/v55.0/composite/sobjects", req.URL.Path)
+		b, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(b, &body)
+		return collectionResponder(&http.Request{Body: io.NopCloser(bytes.NewReader(b))}), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	records := []any{upsertStub{Name: "Acme"}, taggedContactStub{LastName: "Smith"}}
+	got, err := CreateMany(context.Background(), h, "Account", records)
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	if assert.Len(t, body.Records, 2) {
+		assert.JSONEq(t, `{"attributes":{"type":"Account"},"External_Id__c":"","Name":"Acme"}`, string(body.Records[0]))
+		assert.JSONEq(t, `{"attributes":{"type":"Contact"},"LastName":"Smith"}`, string(body.Records[1]))
+	}
+
+	_, err = CreateMany(context.Background(), h, "", []upsertStub{{Name: "Acme"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,294 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// compositeMaxSubrequests the most subrequests salesforce accepts in a composite request
+const compositeMaxSubrequests = 25
+
+// validReferenceId a composite reference id, alphanumeric or underscore, starting with a letter
+var validReferenceId = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// CompositeOp a subrequest of a Composite, created by CompositePost, CompositePatch etc.
+type CompositeOp struct {
+	method string
+	path   string
+	record any
+	fields map[string]any
+}
+
+// CompositePost creates a record of object
+func CompositePost(object string, record any) *CompositeOp {
+	return &CompositeOp{method: http.MethodPost, path: "/sobjects/" + object, record: record}
+}
+
+// CompositePatch updates the record of object with id, which can be a Ref to a record created earlier
+func CompositePatch(object, id string, record any) *CompositeOp {
+	return &CompositeOp{method: http.MethodPatch, path: "/sobjects/" + object + "/" + id, record: record}
+}
+
+// CompositeDelete deletes the record of object with id, which can be a Ref
+func CompositeDelete(object, id string) *CompositeOp {
+	return &CompositeOp{method: http.MethodDelete, path: "/sobjects/" + object + "/" + id}
+}
+
+// CompositeGet gets fields of the record of object with id, which can be a Ref, every field when none are given
+func CompositeGet(object, id string, fields ...string) *CompositeOp {
+	path := "/sobjects/" + object + "/" + id
+	if len(fields) > 0 {
+		path += "?fields=" + strings.Join(fields, ",")
+	}
+	return &CompositeOp{method: http.MethodGet, path: path}
+}
+
+// CompositeQuery runs a query, which can filter on a Ref
+func CompositeQuery(q string) *CompositeOp {
+	return &CompositeOp{method: http.MethodGet, path: "/query?q=" + url.QueryEscape(q)}
+}
+
+// WithField sets field of the record sent, over the record's own value, e.g. to a Ref to a record created earlier
+func (op *CompositeOp) WithField(field string, value any) *CompositeOp {
+	if op.fields == nil {
+		op.fields = map[string]any{}
+	}
+	op.fields[field] = value
+	return op
+}
+
+// body the json body of the subrequest, the record encoded by the JsonCodec of h with the fields set by WithField
+func (op *CompositeOp) body(h *RequestHelper) (any, error) {
+	if op.record == nil && op.fields == nil {
+		return nil, nil
+	}
+	body := map[string]any{}
+	if op.record != nil {
+		b, err := h.marshal(op.record)
+		if err != nil {
+			return nil, err
+		}
+		var fields map[string]json.RawMessage
+		if err = h.unmarshal(b, &fields); err != nil {
+			return nil, err
+		}
+		for field, value := range fields {
+			body[field] = value
+		}
+	}
+	for field, value := range op.fields {
+		body[field] = value
+	}
+	return body, nil
+}
+
+// Ref a reference to a value of an earlier subrequest's result, e.g. Ref("refAccount", "id") for the id of the
+// record it created or Ref("refQuery", "records[0].Id")
+func Ref(referenceId, path string) string {
+	return "@{" + referenceId + "." + path + "}"
+}
+
+// Composite builds a composite request, up to 25 subrequests sent in a single API call, later subrequests can use the
+// results of earlier ones with Ref
+type Composite struct {
+	allOrNone bool
+	refs      []string
+	ops       map[string]*CompositeOp
+	err       error
+}
+
+// NewComposite creates a Composite, with allOrNone every subrequest is rolled back when one fails
+func NewComposite(allOrNone bool) *Composite {
+	return &Composite{allOrNone: allOrNone, ops: map[string]*CompositeOp{}}
+}
+
+// Add adds op to the request as referenceId, which its result is mapped to and Refs to it use
+func (c *Composite) Add(referenceId string, op *CompositeOp) *Composite {
+	switch {
+	case c.err != nil:
+	case !validReferenceId.MatchString(referenceId):
+		c.err = fmt.Errorf("invalid composite reference id %q", referenceId)
+	case c.ops[referenceId] != nil:
+		c.err = fmt.Errorf("duplicate composite reference id %q", referenceId)
+	default:
+		c.refs = append(c.refs, referenceId)
+		c.ops[referenceId] = op
+	}
+	return c
+}
+
+type compositeSubrequest struct {
+	Method      string `json:"method"`
+	Url         string `json:"url"`
+	ReferenceId string `json:"referenceId"`
+	Body        any    `json:"body,omitempty"`
+}
+
+type compositeRequest struct {
+	AllOrNone        bool                  `json:"allOrNone"`
+	CompositeRequest []compositeSubrequest `json:"compositeRequest"`
+}
+
+// CompositeResult the result of a subrequest of a Composite
+type CompositeResult struct {
+	ReferenceId string            `json:"referenceId"`
+	StatusCode  int               `json:"httpStatusCode"`
+	Headers     map[string]string `json:"httpHeaders"`
+	Body        json.RawMessage   `json:"body"`
+	// h the RequestHelper which sent the request, whose JsonCodec decodes Body
+	h *RequestHelper
+}
+
+// Ok whether the subrequest succeeded
+func (r CompositeResult) Ok() bool {
+	return r.StatusCode >= 200 && r.StatusCode <= 299
+}
+
+// Id the id of the record a CompositePost created
+func (r CompositeResult) Id() string {
+	var created PostResponse
+	if r.unmarshal(&created) != nil {
+		return ""
+	}
+	return created.Id
+}
+
+// Decode decodes the body of the subrequest's response into v, e.g. the record of a CompositeGet
+func (r CompositeResult) Decode(v any) error {
+	if err := r.unmarshal(v); err != nil {
+		return fmt.Errorf("unable to parse response body: %w", err)
+	}
+	return nil
+}
+
+// unmarshal decodes Body into v with the JsonCodec of the RequestHelper which sent the request
+func (r CompositeResult) unmarshal(v any) error {
+	if r.h == nil {
+		return json.Unmarshal(r.Body, v)
+	}
+	return r.h.unmarshal(r.Body, v)
+}
+
+// Err a CompositeError when the subrequest failed
+func (r CompositeResult) Err() error {
+	if r.Ok() {
+		return nil
+	}
+	e := CompositeError{ReferenceId: r.ReferenceId, StatusCode: r.StatusCode}
+	var restErrs []restError
+	if json.Unmarshal(r.Body, &restErrs) == nil {
+		for _, re := range restErrs {
+			e.Errors = append(e.Errors, ApiError{StatusCode: re.ErrorCode, Message: re.Message, Fields: re.Fields})
+		}
+	}
+	return e
+}
+
+// CompositeError a failed subrequest of a Composite, with the errors salesforce reported
+type CompositeError struct {
+	ReferenceId string
+	StatusCode  int
+	Errors      []ApiError
+}
+
+// halted whether the subrequest failed only because an earlier one did
+func (e CompositeError) halted() bool {
+	for _, apiErr := range e.Errors {
+		if apiErr.StatusCode != processingHalted {
+			return false
+		}
+	}
+	return len(e.Errors) > 0
+}
+
+func (e CompositeError) Error() string {
+	msg := fmt.Sprintf("composite subrequest %s failed - status code: %d", e.ReferenceId, e.StatusCode)
+	for _, apiErr := range e.Errors {
+		msg += fmt.Sprintf(", %s: %s", apiErr.StatusCode, apiErr.Message)
+	}
+	return msg
+}
+
+// processingHalted the error code of the subrequests rolled back, or not run, as an earlier one failed
+const processingHalted = "PROCESSING_HALTED"
+
+// CompositeResponse the results of a Composite, in the order the subrequests were added
+type CompositeResponse struct {
+	Results []CompositeResult `json:"compositeResponse"`
+}
+
+// Result the result of the subrequest added as referenceId
+func (r CompositeResponse) Result(referenceId string) (CompositeResult, bool) {
+	for _, result := range r.Results {
+		if result.ReferenceId == referenceId {
+			return result, true
+		}
+	}
+	return CompositeResult{}, false
+}
+
+// Err the CompositeError of the subrequest which failed, rather than those halted by its failure, nil when all
+// succeeded
+func (r CompositeResponse) Err() error {
+	var halted error
+	for _, result := range r.Results {
+		err := result.Err()
+		if err == nil {
+			continue
+		}
+		if !err.(CompositeError).halted() {
+			return err
+		}
+		if halted == nil {
+			halted = err
+		}
+	}
+	return halted
+}
+
+// Send sends the composite request, returning the result of each subrequest. An error is returned when the request
+// as a whole fails, check CompositeResponse Err or each CompositeResult for the subrequests which failed
+func (c *Composite) Send(ctx context.Context, h *RequestHelper) (*CompositeResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if len(c.refs) == 0 {
+		return nil, fmt.Errorf("composite request has no subrequests")
+	}
+	if len(c.refs) > compositeMaxSubrequests {
+		return nil, fmt.Errorf("composite request has %d subrequests, salesforce allows %d", len(c.refs), compositeMaxSubrequests)
+	}
+
+	reqBody := compositeRequest{AllOrNone: c.allOrNone}
+	for _, ref := range c.refs {
+		op := c.ops[ref]
+		body, err := op.body(h)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+		}
+		reqBody.CompositeRequest = append(reqBody.CompositeRequest, compositeSubrequest{
+			Method:      op.method,
+			Url:         fmt.Sprintf("%s/v%d.0%s", defaultDataPath, h.apiVersion, op.path),
+			ReferenceId: ref,
+			Body:        body,
+		})
+	}
+
+	reqUrl, err := h.dataUrl(ctx, "/composite")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := sendJson[CompositeResponse](ctx, h, http.MethodPost, reqUrl, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	for i := range resp.Results {
+		resp.Results[i].h = h
+	}
+	return resp, nil
+}
@@ -0,0 +1,168 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/sync/errgroup"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// compositeBatchMaxSubrequests the most subrequests salesforce accepts in a composite batch request
+const compositeBatchMaxSubrequests = 25
+
+// RecordRef a record to fetch with GetRecords, of any object
+type RecordRef struct {
+	Object string
+	Id     string
+	// Fields the fields fetched, every field when empty
+	Fields []string
+}
+
+type compositeBatchSubrequest struct {
+	Method string `json:"method"`
+	Url    string `json:"url"`
+}
+
+type compositeBatchRequest struct {
+	BatchRequests []compositeBatchSubrequest `json:"batchRequests"`
+}
+
+type compositeBatchResponse struct {
+	HasErrors bool `json:"hasErrors"`
+	Results   []struct {
+		StatusCode int             `json:"statusCode"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"results"`
+}
+
+// recordsFetch a subrequest of GetRecords, a query of the records of object with ids, or a GET of a single record
+// when it has no fields
+type recordsFetch struct {
+	object string
+	fields []string
+	ids    []string
+}
+
+// url the url of the subrequest, relative to the REST API root
+func (f recordsFetch) url(apiVersion int) string {
+	if len(f.fields) == 0 {
+		return fmt.Sprintf("v%d.0/sobjects/%s/%s", apiVersion, f.object, f.ids[0])
+	}
+	fields := f.fields
+	if !slices.Contains(fields, "Id") {
+		fields = append([]string{"Id"}, fields...)
+	}
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE Id IN %s", strings.Join(fields, ", "), f.object, InList(f.ids...))
+	return fmt.Sprintf("v%d.0/query?q=%s", apiVersion, url.QueryEscape(q))
+}
+
+// GetRecords fetches records of any objects, e.g. those a page renders, in as few API calls as possible, returning
+// them by the id they were requested with. Records of the same object and fields are queried together and the
+// subrequests sent in composite batches of 25, in parallel WithConcurrency. Ids that aren't found are skipped
+func GetRecords(ctx context.Context, h *RequestHelper, refs []RecordRef, opts ...RequestOption) (map[string]map[string]any, error) {
+	fetches, err := recordsFetches(refs)
+	if err != nil {
+		return nil, err
+	}
+	o := newRequestOptions(opts)
+
+	batches := make([]map[string]map[string]any, (len(fetches)+compositeBatchMaxSubrequests-1)/compositeBatchMaxSubrequests)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(o.concurrency, 1))
+	for i := range batches {
+		batch := fetches[i*compositeBatchMaxSubrequests : min((i+1)*compositeBatchMaxSubrequests, len(fetches))]
+		g.Go(func() error {
+			records, err := sendRecordsBatch(gctx, h, batch)
+			batches[i] = records
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	records := map[string]map[string]any{}
+	for _, batch := range batches {
+		for id, record := range batch {
+			records[id] = record
+		}
+	}
+	return records, nil
+}
+
+// recordsFetches the subrequests fetching refs, a query per object and fields, chunked as GetByIds is, or a GET per
+// record when no fields are given
+func recordsFetches(refs []RecordRef) ([]recordsFetch, error) {
+	var fetches []recordsFetch
+	queries := map[string]int{}
+	for _, ref := range refs {
+		if len(ref.Object) == 0 || !ValidId(ref.Id) {
+			return nil, fmt.Errorf("invalid record %s %q", ref.Object, ref.Id)
+		}
+		key := ref.Object + " " + strings.Join(ref.Fields, ",")
+		if i, ok := queries[key]; ok && len(ref.Fields) > 0 && len(fetches[i].ids) < getByIdsChunkSize {
+			if !slices.Contains(fetches[i].ids, ref.Id) {
+				fetches[i].ids = append(fetches[i].ids, ref.Id)
+			}
+			continue
+		}
+		queries[key] = len(fetches)
+		fetches = append(fetches, recordsFetch{object: ref.Object, fields: ref.Fields, ids: []string{ref.Id}})
+	}
+	return fetches, nil
+}
+
+// sendRecordsBatch sends fetches as a composite batch request, returning the records by requested id
+func sendRecordsBatch(ctx context.Context, h *RequestHelper, fetches []recordsFetch) (map[string]map[string]any, error) {
+	reqBody := compositeBatchRequest{}
+	for _, f := range fetches {
+		reqBody.BatchRequests = append(reqBody.BatchRequests, compositeBatchSubrequest{Method: http.MethodGet, Url: f.url(h.apiVersion)})
+	}
+	reqUrl, err := h.dataUrl(ctx, "/composite/batch")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := sendJson[compositeBatchResponse](ctx, h, http.MethodPost, reqUrl, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results) != len(fetches) {
+		return nil, fmt.Errorf("unexpected salesforce composite batch results: %d for %d requests", len(resp.Results), len(fetches))
+	}
+
+	records := map[string]map[string]any{}
+	for i, result := range resp.Results {
+		f := fetches[i]
+		switch {
+		case result.StatusCode == http.StatusNotFound:
+			continue
+		case result.StatusCode != http.StatusOK:
+			return nil, fmt.Errorf("unexpected salesforce response code: %d, fetching %s", result.StatusCode, strings.Join(f.ids, ", "))
+		case len(f.fields) == 0:
+			var record map[string]any
+			if err = h.unmarshal(result.Result, &record); err != nil {
+				return nil, fmt.Errorf("unable to parse response body: %w", err)
+			}
+			records[f.ids[0]] = record
+			continue
+		}
+		var page QueryResponse[map[string]any]
+		if err = h.unmarshal(result.Result, &page); err != nil {
+			return nil, fmt.Errorf("unable to parse response body: %w", err)
+		}
+		for _, record := range page.Records {
+			id, _ := record["Id"].(string)
+			for _, requested := range f.ids {
+				// a 15 character requested id matches the 18 character id returned
+				if len(id) >= 15 && id[:15] == requested[:15] {
+					records[requested] = record
+				}
+			}
+		}
+	}
+	return records, nil
+}
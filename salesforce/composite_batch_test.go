@@ -0,0 +1,55 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestGetRecords(t *testing.T) {
+	var gotUrl string
+	var gotBody compositeBatchRequest
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		gotUrl = req.URL.String()
+		b, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(b, &gotBody)
+		return newResponse(http.StatusOK, `{"hasErrors":true,"results":[`+
+			`{"statusCode":200,"result":{"totalSize":2,"done":true,"records":[{"Id":"001xx0000000001AAA","Name":"Acme"},{"Id":"001xx0000000002AAA","Name":"Globex"}]}},`+
+			`{"statusCode":200,"result":{"Id":"003xx0000000001AAA","LastName":"Smith"}},`+
+			`{"statusCode":404,"result":[{"errorCode":"NOT_FOUND","message":"The requested resource does not exist"}]}]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := GetRecords(context.Background(), h, []RecordRef{
+		{Object: "Account", Id: "001xx0000000001", Fields: []string{"Name"}},
+		{Object: "Contact", Id: "003xx0000000001AAA"},
+		{Object: "Account", Id: "001xx0000000002AAA", Fields: []string{"Name"}},
+		{Object: "Case", Id: "500xx0000000001AAA"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://org/services/data/v55.0/composite/batch", gotUrl)
+	assert.Equal(t, compositeBatchRequest{BatchRequests: []compositeBatchSubrequest{
+		{Method: "GET", Url: "v55.0/query?q=SELECT+Id%2C+Name+FROM+Account+WHERE+Id+IN+%28%27001xx0000000001%27%2C+%27001xx0000000002AAA%27%29"},
+		{Method: "GET", Url: "v55.0/sobjects/Contact/003xx0000000001AAA"},
+		{Method: "GET", Url: "v55.0/sobjects/Case/500xx0000000001AAA"},
+	}}, gotBody)
+	assert.Equal(t, map[string]map[string]any{
+		"001xx0000000001":    {"Id": "001xx0000000001AAA", "Name": "Acme"},
+		"001xx0000000002AAA": {"Id": "001xx0000000002AAA", "Name": "Globex"},
+		"003xx0000000001AAA": {"Id": "003xx0000000001AAA", "LastName": "Smith"},
+	}, got)
+}
+
+func TestGetRecords_InvalidId(t *testing.T) {
+	h, _ := NewRequestHelper(new(HttpClientMock), newTokenGetterMock("token", nil), "https://org", 55)
+
+	_, err := GetRecords(context.Background(), h, []RecordRef{{Object: "Account", Id: "bad"}})
+
+	assert.EqualError(t, err, `invalid record Account "bad"`)
+}
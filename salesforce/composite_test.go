@@ -0,0 +1,110 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestComposite_Send(t *testing.T) {
+	type contact struct {
+		LastName  string `json:"LastName"`
+		AccountId string `json:"AccountId,omitempty"`
+	}
+	var gotUrl string
+	var gotBody map[string]any
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		gotUrl = req.URL.String()
+		b, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(b, &gotBody)
+		return newResponse(http.StatusOK, `{"compositeResponse":[`+
+			`{"referenceId":"refAccount","httpStatusCode":201,"httpHeaders":{},"body":{"id":"001xx0000000001","success":true,"errors":[]}},`+
+			`{"referenceId":"refContact","httpStatusCode":201,"httpHeaders":{},"body":{"id":"003xx0000000001","success":true,"errors":[]}},`+
+			`{"referenceId":"refGet","httpStatusCode":200,"httpHeaders":{},"body":{"Id":"001xx0000000001","Name":"Acme"}}]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	resp, err := NewComposite(true).
+		Add("refAccount", CompositePost("Account", map[string]any{"Name": "Acme"})).
+		Add("refContact", CompositePost("Contact", contact{LastName: "Smith"}).WithField("AccountId", Ref("refAccount", "id"))).
+		Add("refGet", CompositeGet("Account", Ref("refAccount", "id"), "Id", "Name")).
+		Send(context.Background(), h)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://org/services/data/v55.0/composite", gotUrl)
+	assert.Equal(t, map[string]any{
+		"allOrNone": true,
+		"compositeRequest": []any{
+			map[string]any{"method": "POST", "url": "/services/data/v55.0/sobjects/Account", "referenceId": "refAccount",
+				"body": map[string]any{"Name": "Acme"}},
+			map[string]any{"method": "POST", "url": "/services/data/v55.0/sobjects/Contact", "referenceId": "refContact",
+				"body": map[string]any{"LastName": "Smith", "AccountId": "@{refAccount.id}"}},
+			map[string]any{"method": "GET", "url": "/services/data/v55.0/sobjects/Account/@{refAccount.id}?fields=Id,Name",
+				"referenceId": "refGet"},
+		},
+	}, gotBody)
+
+	assert.NoError(t, resp.Err())
+	contactResult, ok := resp.Result("refContact")
+	assert.True(t, ok)
+	assert.Equal(t, "003xx0000000001", contactResult.Id())
+	getResult, _ := resp.Result("refGet")
+	var account struct{ Name string }
+	assert.NoError(t, getResult.Decode(&account))
+	assert.Equal(t, "Acme", account.Name)
+}
+
+func TestComposite_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   func() *Composite
+		wantErr string
+	}{
+		{
+			name: "invalid reference id  error returned",
+			build: func() *Composite {
+				return NewComposite(false).Add("ref-account", CompositeDelete("Account", "001xx0000000001"))
+			},
+			wantErr: `invalid composite reference id "ref-account"`,
+		},
+		{
+			name: "duplicate reference id  error returned",
+			build: func() *Composite {
+				return NewComposite(false).Add("ref", CompositeDelete("Account", "001xx0000000001")).
+					Add("ref", CompositeDelete("Account", "001xx0000000002"))
+			},
+			wantErr: `duplicate composite reference id "ref"`,
+		},
+		{
+			name:    "no subrequests  error returned",
+			build:   func() *Composite { return NewComposite(false) },
+			wantErr: "composite request has no subrequests",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewRequestHelper(new(HttpClientMock), newTokenGetterMock("token", nil), "https://org", 55)
+			_, err := tt.build().Send(context.Background(), h)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCompositeResponse_Err(t *testing.T) {
+	var resp CompositeResponse
+	_ = json.Unmarshal([]byte(`{"compositeResponse":[`+
+		`{"referenceId":"refAccount","httpStatusCode":400,"body":[{"errorCode":"PROCESSING_HALTED","message":"halted"}]},`+
+		`{"referenceId":"refContact","httpStatusCode":400,"body":[{"errorCode":"REQUIRED_FIELD_MISSING","message":"Required fields are missing: [LastName]","fields":["LastName"]}]}]}`), &resp)
+
+	err := resp.Err()
+
+	var compositeErr CompositeError
+	assert.ErrorAs(t, err, &compositeErr)
+	assert.Equal(t, "refContact", compositeErr.ReferenceId)
+	assert.EqualError(t, err, "composite subrequest refContact failed - status code: 400, REQUIRED_FIELD_MISSING: Required fields are missing: [LastName]")
+}
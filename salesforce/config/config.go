@@ -0,0 +1,244 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/ellogroup/ello-golang-cache/cache"
+	"github.com/ellogroup/ello-golang-salesforce/salesforce"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var validate = validator.New()
+
+const (
+	// CacheMemory caches the token in memory, the default
+	CacheMemory = "memory"
+	// CacheDynamoDB caches the token in a DynamoDB table, shared across instances
+	CacheDynamoDB = "dynamodb"
+)
+
+// Config the settings of a salesforce integration, loaded from a file with Load
+type Config struct {
+	// BaseUrl the org's instance url, e.g. https://org.my.salesforce.com
+	BaseUrl    string    `json:"baseUrl" yaml:"baseUrl" validate:"required,url"`
+	ApiVersion int       `json:"apiVersion" yaml:"apiVersion" validate:"required,gt=0"`
+	Auth       Auth      `json:"auth" yaml:"auth"`
+	Retry      Retry     `json:"retry" yaml:"retry"`
+	Timeouts   Timeouts  `json:"timeouts" yaml:"timeouts"`
+	RateLimit  RateLimit `json:"rateLimit" yaml:"rateLimit"`
+	Cache      Cache     `json:"cache" yaml:"cache"`
+	// MaxResponseSize the largest response body read in bytes, unlimited when 0
+	MaxResponseSize int64 `json:"maxResponseSize" yaml:"maxResponseSize" validate:"gte=0"`
+}
+
+// Auth the settings of the token fetches, see salesforce.TokenParams
+type Auth struct {
+	// SecretKey the secrets manager key of the credentials
+	SecretKey      string   `json:"secretKey" yaml:"secretKey" validate:"required"`
+	Environment    string   `json:"environment" yaml:"environment" validate:"omitempty,oneof=production sandbox"`
+	Introspect     string   `json:"introspect" yaml:"introspect" validate:"omitempty,oneof=first never"`
+	SigningMethod  string   `json:"signingMethod" yaml:"signingMethod" validate:"omitempty,oneof=RS256 ES256"`
+	RequiredScopes []string `json:"requiredScopes" yaml:"requiredScopes"`
+	JwtTtl         Duration `json:"jwtTtl" yaml:"jwtTtl"`
+	ClockSkew      Duration `json:"clockSkew" yaml:"clockSkew"`
+}
+
+// Retry the backoff between retries of token fetches and requests, see salesforce.BackoffPolicy. Requests are only
+// retried when Enabled
+type Retry struct {
+	Enabled     bool     `json:"enabled" yaml:"enabled"`
+	Initial     Duration `json:"initial" yaml:"initial"`
+	MaxInterval Duration `json:"maxInterval" yaml:"maxInterval"`
+	MaxElapsed  Duration `json:"maxElapsed" yaml:"maxElapsed"`
+	Multiplier  float64  `json:"multiplier" yaml:"multiplier" validate:"gte=0"`
+	Jitter      string   `json:"jitter" yaml:"jitter" validate:"omitempty,oneof=full equal"`
+}
+
+// Timeouts the time allowed for each kind of operation, see salesforce.Timeouts
+type Timeouts struct {
+	Query Duration `json:"query" yaml:"query"`
+	Write Duration `json:"write" yaml:"write"`
+	Poll  Duration `json:"poll" yaml:"poll"`
+	Token Duration `json:"token" yaml:"token"`
+}
+
+// RateLimit the workers requests are sent through, see salesforce.RequestQueue. Requests aren't queued when Workers
+// is 0
+type RateLimit struct {
+	Workers      int `json:"workers" yaml:"workers" validate:"gte=0"`
+	BatchWorkers int `json:"batchWorkers" yaml:"batchWorkers" validate:"gte=0"`
+	MaxQueued    int `json:"maxQueued" yaml:"maxQueued" validate:"gte=0"`
+}
+
+// Cache where the token is cached
+type Cache struct {
+	// Driver memory, the default, or dynamodb
+	Driver string `json:"driver" yaml:"driver" validate:"omitempty,oneof=memory dynamodb"`
+	// Table and Name the DynamoDB table and the name of the token's item in it
+	Table        string   `json:"table" yaml:"table" validate:"required_if=Driver dynamodb"`
+	Name         string   `json:"name" yaml:"name"`
+	Ttl          Duration `json:"ttl" yaml:"ttl"`
+	RefreshAhead Duration `json:"refreshAhead" yaml:"refreshAhead"`
+}
+
+// Duration a time.Duration written as a string, e.g. 30s or 5m
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("invalid duration %s, must be a string e.g. \"30s\"", b)
+	}
+	return d.parse(s)
+}
+
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	return d.parse(value.Value)
+}
+
+func (d *Duration) parse(s string) error {
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Load reads the Config of a YAML or JSON file, by its extension, then applies the environment variable overrides:
+// SALESFORCE_BASE_URL, SALESFORCE_API_VERSION, SALESFORCE_SECRET_KEY, SALESFORCE_ENVIRONMENT,
+// SALESFORCE_REQUIRED_SCOPES, SALESFORCE_RETRY_ENABLED, SALESFORCE_RETRY_MAX_ELAPSED, SALESFORCE_RATE_LIMIT_WORKERS,
+// SALESFORCE_CACHE_DRIVER and SALESFORCE_CACHE_TABLE
+func Load(path string) (*Config, error) {
+	return load(path, os.LookupEnv)
+}
+
+func load(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read salesforce config: %w", err)
+	}
+	var c Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &c)
+	case ".json":
+		err = json.Unmarshal(b, &c)
+	default:
+		return nil, fmt.Errorf("unsupported salesforce config file %s, must be .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse salesforce config %s: %w", path, err)
+	}
+	if err = c.applyEnv(lookupEnv); err != nil {
+		return nil, err
+	}
+	if err = validate.Struct(c); err != nil {
+		return nil, fmt.Errorf("invalid salesforce config %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Clients the clients the settings are applied to
+type Clients struct {
+	HttpClient salesforce.HttpClient
+	// SMClient reads the credentials from secrets manager
+	SMClient *secretsmanager.Client
+	// DynamoDB required for the dynamodb cache driver
+	DynamoDB salesforce.DynamoDBClient
+	Logger   *zap.Logger
+	Metrics  salesforce.Metrics
+}
+
+// TokenParams the salesforce.TokenParams of the config
+func (c Config) TokenParams(cl Clients) salesforce.TokenParams {
+	p := salesforce.TokenParams{
+		HttpClient:     cl.HttpClient,
+		SMClient:       cl.SMClient,
+		SMKey:          c.Auth.SecretKey,
+		Environment:    salesforce.LoginEnvironment(c.Auth.Environment),
+		Introspect:     salesforce.IntrospectMode(c.Auth.Introspect),
+		SigningMethod:  c.Auth.SigningMethod,
+		RequiredScopes: c.Auth.RequiredScopes,
+		JwtTtl:         time.Duration(c.Auth.JwtTtl),
+		ClockSkew:      time.Duration(c.Auth.ClockSkew),
+		CacheTtl:       time.Duration(c.Cache.Ttl),
+		RefreshAhead:   time.Duration(c.Cache.RefreshAhead),
+		Metrics:        cl.Metrics,
+	}
+	if c.Retry != (Retry{}) {
+		policy := c.backoffPolicy()
+		p.BackoffPolicy = &policy
+	}
+	return p
+}
+
+// TokenCache a salesforce.TokenCache stored in the configured cache driver
+func (c Config) TokenCache(cl Clients) (*salesforce.TokenCache, error) {
+	p := c.TokenParams(cl)
+	switch c.Cache.Driver {
+	case CacheDynamoDB:
+		if cl.DynamoDB == nil {
+			return nil, fmt.Errorf("DynamoDB client needs to be provided for the dynamodb cache driver")
+		}
+		name := c.Cache.Name
+		if len(name) == 0 {
+			name = "salesforce-token"
+		}
+		d := salesforce.NewDynamoDBCacheDriver[int, cache.RecordCacheItem[salesforce.Token]](cl.DynamoDB, c.Cache.Table, name)
+		return salesforce.NewTokenCacheWithDriver(p, d, cl.Logger)
+	default:
+		if cl.Logger != nil {
+			return salesforce.NewTokenCacheWithLogger(p, cl.Logger)
+		}
+		return salesforce.NewTokenCache(p)
+	}
+}
+
+// RequestHelper a salesforce.RequestHelper with the config's retries, timeouts, rate limit and response size limit
+func (c Config) RequestHelper(cl Clients, tg salesforce.TokenGetter) (*salesforce.RequestHelper, error) {
+	h, err := salesforce.NewRequestHelper(cl.HttpClient, tg, c.BaseUrl, c.ApiVersion)
+	if err != nil {
+		return nil, err
+	}
+	if c.Retry.Enabled {
+		h.SetBackoffPolicy(c.backoffPolicy())
+	}
+	if c.RateLimit.Workers > 0 {
+		q, err := salesforce.NewRequestQueue(salesforce.RequestQueueParams{
+			Workers:      c.RateLimit.Workers,
+			BatchWorkers: c.RateLimit.BatchWorkers,
+			MaxQueued:    c.RateLimit.MaxQueued,
+		})
+		if err != nil {
+			return nil, err
+		}
+		h.SetRequestQueue(q)
+	}
+	if cl.Metrics != nil {
+		h.SetMetrics(cl.Metrics)
+	}
+	return h.SetTimeouts(salesforce.Timeouts{
+		Query: time.Duration(c.Timeouts.Query),
+		Write: time.Duration(c.Timeouts.Write),
+		Poll:  time.Duration(c.Timeouts.Poll),
+		Token: time.Duration(c.Timeouts.Token),
+	}).SetMaxResponseSize(c.MaxResponseSize), nil
+}
+
+func (c Config) backoffPolicy() salesforce.BackoffPolicy {
+	return salesforce.BackoffPolicy{
+		Initial:     time.Duration(c.Retry.Initial),
+		MaxInterval: time.Duration(c.Retry.MaxInterval),
+		MaxElapsed:  time.Duration(c.Retry.MaxElapsed),
+		Multiplier:  c.Retry.Multiplier,
+		Jitter:      salesforce.Jitter(c.Retry.Jitter),
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"context"
+	"github.com/ellogroup/ello-golang-salesforce/salesforce"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const yamlConfig = `
+baseUrl: https://org.my.salesforce.com
+apiVersion: 59
+auth:
+  secretKey: SALESFORCE_AUTH_CREDS
+  environment: sandbox
+retry:
+  enabled: true
+  initial: 1s
+  maxElapsed: 2m
+  jitter: full
+timeouts:
+  query: 2m
+rateLimit:
+  workers: 10
+cache:
+  driver: dynamodb
+  table: cache
+`
+
+const jsonConfig = `{
+  "baseUrl": "https://org.my.salesforce.com",
+  "apiVersion": 59,
+  "auth": {"secretKey": "SALESFORCE_AUTH_CREDS", "environment": "sandbox"},
+  "retry": {"enabled": true, "initial": "1s", "maxElapsed": "2m", "jitter": "full"},
+  "timeouts": {"query": "2m"},
+  "rateLimit": {"workers": 10},
+  "cache": {"driver": "dynamodb", "table": "cache"}
+}`
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	want := &Config{
+		BaseUrl:    "https://org.my.salesforce.com",
+		ApiVersion: 59,
+		Auth:       Auth{SecretKey: "SALESFORCE_AUTH_CREDS", Environment: "sandbox"},
+		Retry:      Retry{Enabled: true, Initial: Duration(time.Second), MaxElapsed: Duration(2 * time.Minute), Jitter: "full"},
+		Timeouts:   Timeouts{Query: Duration(2 * time.Minute)},
+		RateLimit:  RateLimit{Workers: 10},
+		Cache:      Cache{Driver: CacheDynamoDB, Table: "cache"},
+	}
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		env     map[string]string
+		want    *Config
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "yaml  loaded", file: "salesforce.yaml", content: yamlConfig, want: want, wantErr: assert.NoError},
+		{name: "json  loaded", file: "salesforce.json", content: jsonConfig, want: want, wantErr: assert.NoError},
+		{
+			name:    "env overrides  applied",
+			file:    "salesforce.yml",
+			content: yamlConfig,
+			env:     map[string]string{"SALESFORCE_BASE_URL": "https://other.my.salesforce.com", "SALESFORCE_API_VERSION": "60"},
+			want: func() *Config {
+				c := *want
+				c.BaseUrl, c.ApiVersion = "https://other.my.salesforce.com", 60
+				return &c
+			}(),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid env override  error returned",
+			file:    "salesforce.yaml",
+			content: yamlConfig,
+			env:     map[string]string{"SALESFORCE_API_VERSION": "latest"},
+			wantErr: assert.Error,
+		},
+		{name: "missing base url  error returned", file: "salesforce.json", content: `{"apiVersion": 59, "auth": {"secretKey": "k"}}`, wantErr: assert.Error},
+		{name: "invalid duration  error returned", file: "salesforce.json", content: `{"timeouts": {"query": 30}}`, wantErr: assert.Error},
+		{name: "unsupported extension  error returned", file: "salesforce.toml", content: ``, wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv := func(name string) (string, bool) {
+				v, ok := tt.env[name]
+				return v, ok
+			}
+			got, err := load(writeConfig(t, tt.file, tt.content), lookupEnv)
+			if !tt.wantErr(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type tokenGetter struct{}
+
+func (tokenGetter) Get(context.Context) (string, error) {
+	return "token", nil
+}
+
+func TestConfig_TokenParams(t *testing.T) {
+	c, err := load(writeConfig(t, "salesforce.yaml", yamlConfig), func(string) (string, bool) { return "", false })
+	assert.NoError(t, err)
+
+	p := c.TokenParams(Clients{})
+
+	assert.Nil(t, p.Backoff)
+	assert.Equal(t, &salesforce.BackoffPolicy{Initial: time.Second, MaxElapsed: 2 * time.Minute, Jitter: salesforce.JitterFull}, p.BackoffPolicy)
+}
+
+func TestConfig_RequestHelper(t *testing.T) {
+	c, err := load(writeConfig(t, "salesforce.yaml", yamlConfig), func(string) (string, bool) { return "", false })
+	assert.NoError(t, err)
+
+	h, err := c.RequestHelper(Clients{}, tokenGetter{})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestConfig_TokenCache(t *testing.T) {
+	c := Config{Auth: Auth{SecretKey: "SALESFORCE_AUTH_CREDS"}, Cache: Cache{Driver: CacheDynamoDB, Table: "cache"}}
+
+	_, err := c.TokenCache(Clients{})
+
+	assert.EqualError(t, err, "DynamoDB client needs to be provided for the dynamodb cache driver")
+}
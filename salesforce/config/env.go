@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// overrides the environment variables which override a Config's file settings, by name
+var overrides = map[string]func(c *Config, v string) error{
+	"SALESFORCE_BASE_URL": func(c *Config, v string) error {
+		c.BaseUrl = v
+		return nil
+	},
+	"SALESFORCE_API_VERSION": func(c *Config, v string) error {
+		return parseInt(v, &c.ApiVersion)
+	},
+	"SALESFORCE_SECRET_KEY": func(c *Config, v string) error {
+		c.Auth.SecretKey = v
+		return nil
+	},
+	"SALESFORCE_ENVIRONMENT": func(c *Config, v string) error {
+		c.Auth.Environment = v
+		return nil
+	},
+	"SALESFORCE_REQUIRED_SCOPES": func(c *Config, v string) error {
+		c.Auth.RequiredScopes = strings.Fields(strings.ReplaceAll(v, ",", " "))
+		return nil
+	},
+	"SALESFORCE_RETRY_ENABLED": func(c *Config, v string) error {
+		enabled, err := strconv.ParseBool(v)
+		c.Retry.Enabled = enabled
+		return err
+	},
+	"SALESFORCE_RETRY_MAX_ELAPSED": func(c *Config, v string) error {
+		return parseDuration(v, &c.Retry.MaxElapsed)
+	},
+	"SALESFORCE_RATE_LIMIT_WORKERS": func(c *Config, v string) error {
+		return parseInt(v, &c.RateLimit.Workers)
+	},
+	"SALESFORCE_CACHE_DRIVER": func(c *Config, v string) error {
+		c.Cache.Driver = v
+		return nil
+	},
+	"SALESFORCE_CACHE_TABLE": func(c *Config, v string) error {
+		c.Cache.Table = v
+		return nil
+	},
+}
+
+// applyEnv applies the overrides set in the environment
+func (c *Config) applyEnv(lookupEnv func(string) (string, bool)) error {
+	for name, override := range overrides {
+		v, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := override(c, v); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func parseInt(v string, i *int) error {
+	parsed, err := strconv.Atoi(v)
+	*i = parsed
+	return err
+}
+
+func parseDuration(v string, d *Duration) error {
+	parsed, err := time.ParseDuration(v)
+	*d = Duration(parsed)
+	return err
+}
@@ -0,0 +1,96 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ShareType the permission a ContentDocumentLink grants on a file
+type ShareType string
+
+const (
+	ShareTypeViewer       ShareType = "V"
+	ShareTypeCollaborator ShareType = "C"
+	// ShareTypeInferred the permission is inferred from the linked record's sharing
+	ShareTypeInferred ShareType = "I"
+)
+
+// Visibility the users a ContentDocumentLink makes a file available to
+type Visibility string
+
+const (
+	VisibilityAllUsers      Visibility = "AllUsers"
+	VisibilityInternalUsers Visibility = "InternalUsers"
+	VisibilitySharedUsers   Visibility = "SharedUsers"
+)
+
+// ContentDocumentLink links a file to a record, user or library
+type ContentDocumentLink struct {
+	Id                string     `json:"Id,omitempty"`
+	ContentDocumentId string     `json:"ContentDocumentId"`
+	LinkedEntityId    string     `json:"LinkedEntityId"`
+	ShareType         ShareType  `json:"ShareType,omitempty"`
+	Visibility        Visibility `json:"Visibility,omitempty"`
+}
+
+// LinkDocument links a file to each of recordIds, in sObject Collections requests of up to 200 links
+// - returns a CollectionResult per record, in the same order as recordIds, a link failing doesn't fail the others
+// - on a request error the results of the chunks already sent are returned along with the error
+func LinkDocument(ctx context.Context, h *RequestHelper, documentId string, share ShareType, visibility Visibility, recordIds ...string) ([]CollectionResult, error) {
+	if !ValidId(documentId) {
+		return nil, fmt.Errorf("invalid documentId %q", documentId)
+	}
+	links := make([]ContentDocumentLink, 0, len(recordIds))
+	for _, recordId := range recordIds {
+		if !ValidId(recordId) {
+			return nil, fmt.Errorf("invalid recordId %q", recordId)
+		}
+		links = append(links, ContentDocumentLink{
+			ContentDocumentId: documentId,
+			LinkedEntityId:    recordId,
+			ShareType:         share,
+			Visibility:        visibility,
+		})
+	}
+	reqUrl, err := h.dataUrl(ctx, "/composite/sobjects")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CollectionResult, 0, len(links))
+	for start := 0; start < len(links); start += collectionsMaxRecords {
+		end := min(start+collectionsMaxRecords, len(links))
+		chunk, err := sendCollection(ctx, h, http.MethodPost, reqUrl, "ContentDocumentLink", "", links[start:end], false)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, chunk...)
+	}
+	return results, nil
+}
+
+// UploadAndLinkFile uploads a file with UploadFile then links it to each of recordIds with LinkDocument, returning the
+// id of its ContentDocument and the result of each link
+// - the ContentDocument id is returned along with any error linking it, as the file has been uploaded
+func UploadAndLinkFile(ctx context.Context, h *RequestHelper, meta ContentVersionMeta, r io.Reader, share ShareType, visibility Visibility, recordIds ...string) (string, []CollectionResult, error) {
+	documentId, err := UploadFile(ctx, h, meta, r)
+	if err != nil {
+		return "", nil, err
+	}
+	results, err := LinkDocument(ctx, h, documentId, share, visibility, recordIds...)
+	if err != nil {
+		return documentId, results, fmt.Errorf("unable to link content document %s: %w", documentId, err)
+	}
+	return documentId, results, nil
+}
+
+// ListDocumentLinks lists the links of the files linked to a record
+func ListDocumentLinks(ctx context.Context, h *RequestHelper, recordId string) ([]ContentDocumentLink, error) {
+	if !ValidId(recordId) {
+		return nil, fmt.Errorf("invalid recordId %q", recordId)
+	}
+	return queryAll[ContentDocumentLink](ctx, h, "SELECT Id, ContentDocumentId, LinkedEntityId, ShareType, Visibility "+
+		"FROM ContentDocumentLink WHERE LinkedEntityId = "+QuoteString(recordId))
+}
@@ -0,0 +1,95 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLinkDocument(t *testing.T) {
+	tests := []struct {
+		name       string
+		documentId string
+		recordIds  []string
+		resp       string
+		wantBody   string
+		want       []CollectionResult
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "linked  results returned",
+			documentId: "069xx0000000001",
+			recordIds:  []string{"001xx0000000001", "500xx0000000001"},
+			resp:       `[{"id":"06Axx0000000001","success":true,"errors":[]},{"id":"06Axx0000000002","success":true,"errors":[]}]`,
+			wantBody: `{"allOrNone":false,"records":[
+				{"attributes":{"type":"ContentDocumentLink"},"ContentDocumentId":"069xx0000000001","LinkedEntityId":"001xx0000000001","ShareType":"V","Visibility":"AllUsers"},
+				{"attributes":{"type":"ContentDocumentLink"},"ContentDocumentId":"069xx0000000001","LinkedEntityId":"500xx0000000001","ShareType":"V","Visibility":"AllUsers"}]}`,
+			want:    []CollectionResult{{Id: "06Axx0000000001", Success: true, Errors: []ApiError{}}, {Id: "06Axx0000000002", Success: true, Errors: []ApiError{}}},
+			wantErr: assert.NoError,
+		},
+		{
+			name:       "invalid record id  error returned",
+			documentId: "069xx0000000001",
+			recordIds:  []string{"001xx0000000001", "' OR Id != '"},
+			wantErr:    assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, http.MethodPost, req.Method)
+				assert.Equal(t, "/services/data/v55.0/composite/sobjects", req.URL.Path)
+				b, _ := io.ReadAll(req.Body)
+				assert.JSONEq(t, tt.wantBody, string(b))
+				return newResponse(http.StatusOK, tt.resp), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := LinkDocument(context.Background(), h, tt.documentId, ShareTypeViewer, VisibilityAllUsers, tt.recordIds...)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUploadAndLinkFile(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case req.URL.Path == "/services/data/v55.0/sobjects/ContentVersion":
+			_, _ = io.Copy(io.Discard, req.Body)
+			return newResponse(http.StatusCreated, `{"id":"068xx0000000001","success":true,"errors":[]}`), nil
+		case req.Method == http.MethodGet:
+			return newResponse(http.StatusOK, `{"ContentDocumentId":"069xx0000000001"}`), nil
+		default:
+			return newResponse(http.StatusInternalServerError, ""), nil
+		}
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, results, err := UploadAndLinkFile(context.Background(), h, ContentVersionMeta{PathOnClient: "invoice.pdf"},
+		strings.NewReader("%PDF-1.4"), ShareTypeInferred, VisibilityAllUsers, "001xx0000000001")
+	assert.Error(t, err)
+	assert.Equal(t, "069xx0000000001", got)
+	assert.Empty(t, results)
+}
+
+func TestListDocumentLinks(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Contains(t, req.URL.Query().Get("q"), "WHERE LinkedEntityId = '001xx0000000001'")
+		return newResponse(http.StatusOK, `{"totalSize":1,"done":true,"records":[{"Id":"06Axx0000000001",
+			"ContentDocumentId":"069xx0000000001","LinkedEntityId":"001xx0000000001","ShareType":"I","Visibility":"AllUsers"}]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := ListDocumentLinks(context.Background(), h, "001xx0000000001")
+	assert.NoError(t, err)
+	assert.Equal(t, []ContentDocumentLink{{Id: "06Axx0000000001", ContentDocumentId: "069xx0000000001",
+		LinkedEntityId: "001xx0000000001", ShareType: ShareTypeInferred, Visibility: VisibilityAllUsers}}, got)
+}
@@ -0,0 +1,45 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+)
+
+// ContentVersionMeta the fields of a ContentVersion uploaded with UploadFile
+type ContentVersionMeta struct {
+	Title string `json:"Title,omitempty"`
+	// PathOnClient the file name, its extension sets the file type, required
+	PathOnClient string `json:"PathOnClient"`
+	Description  string `json:"Description,omitempty"`
+	// FirstPublishLocationId the record, user or library the file is shared with on upload
+	FirstPublishLocationId string `json:"FirstPublishLocationId,omitempty"`
+	// ContentDocumentId uploads a new version of an existing file, rather than a new file
+	ContentDocumentId string `json:"ContentDocumentId,omitempty"`
+	ReasonForChange   string `json:"ReasonForChange,omitempty"`
+}
+
+// UploadFile creates a ContentVersion from r, returning the id of its ContentDocument
+// - the file is sent as multipart/form-data, streamed from r rather than base64 encoded in memory, so isn't limited
+// to the 37.5MB of a json upload
+func UploadFile(ctx context.Context, h *RequestHelper, meta ContentVersionMeta, r io.Reader) (string, error) {
+	if len(meta.PathOnClient) == 0 {
+		return "", fmt.Errorf("PathOnClient needs to be provided")
+	}
+	entity, err := json.Marshal(meta)
+	if err != nil {
+		return "", fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	id, err := uploadBlob(ctx, h, "ContentVersion", "entity_content", "VersionData", path.Base(meta.PathOnClient), entity, r)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := Get[struct{ ContentDocumentId string }](ctx, h, "ContentVersion", id, "ContentDocumentId")
+	if err != nil {
+		return "", fmt.Errorf("unable to get content document of version %s: %w", id, err)
+	}
+	return version.ContentDocumentId, nil
+}
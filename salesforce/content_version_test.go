@@ -0,0 +1,106 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUploadFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     ContentVersionMeta
+		file     io.Reader
+		postResp *http.Response
+		want     string
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "uploaded  content document id returned",
+			meta:     ContentVersionMeta{Title: "Invoice", PathOnClient: "/tmp/invoice.pdf", FirstPublishLocationId: "001xx0000000001"},
+			file:     strings.NewReader("%PDF-1.4"),
+			postResp: newResponse(http.StatusCreated, `{"id":"068xx0000000001","success":true,"errors":[]}`),
+			want:     "069xx0000000001",
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "no path on client  error returned",
+			meta:     ContentVersionMeta{Title: "Invoice"},
+			file:     strings.NewReader("%PDF-1.4"),
+			postResp: newResponse(http.StatusCreated, `{"id":"068xx0000000001","success":true,"errors":[]}`),
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "bad request  error returned",
+			meta:     ContentVersionMeta{PathOnClient: "invoice.pdf"},
+			file:     strings.NewReader("%PDF-1.4"),
+			postResp: newResponse(http.StatusBadRequest, `[{"errorCode":"INVALID_FIELD"}]`),
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "file read fails  error returned",
+			meta:     ContentVersionMeta{PathOnClient: "invoice.pdf"},
+			file:     io.MultiReader(strings.NewReader("%PDF"), errorReader{errors.New("disk error")}),
+			postResp: newResponse(http.StatusCreated, `{"id":"068xx0000000001","success":true,"errors":[]}`),
+			wantErr:  assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodGet {
+					assert.Equal(t, "/services/data/v55.0/sobjects/ContentVersion/068xx0000000001", req.URL.Path)
+					return newResponse(http.StatusOK, `{"ContentDocumentId":"069xx0000000001"}`), nil
+				}
+				assert.Equal(t, "/services/data/v55.0/sobjects/ContentVersion", req.URL.Path)
+				mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+				assert.NoError(t, err)
+				assert.Equal(t, "multipart/form-data", mediaType)
+
+				parts := map[string]string{}
+				mr := multipart.NewReader(req.Body, params["boundary"])
+				for {
+					part, err := mr.NextPart()
+					if err != nil {
+						if err != io.EOF {
+							return nil, err
+						}
+						break
+					}
+					b, _ := io.ReadAll(part)
+					parts[part.FormName()] = string(b)
+					if part.FormName() == "VersionData" {
+						assert.Equal(t, "invoice.pdf", part.FileName())
+					}
+				}
+				if len(tt.want) == 0 {
+					return tt.postResp, nil
+				}
+				assert.JSONEq(t, `{"Title":"Invoice","PathOnClient":"/tmp/invoice.pdf","FirstPublishLocationId":"001xx0000000001"}`, parts["entity_content"])
+				assert.Equal(t, "%PDF-1.4", parts["VersionData"])
+				return tt.postResp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := UploadFile(context.Background(), h, tt.meta, tt.file)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,60 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CreateError returned by Post when salesforce doesn't create the record, with the errors it reported
+type CreateError struct {
+	Object     string
+	StatusCode int
+	Errors     []ApiError
+}
+
+func (e CreateError) Error() string {
+	msg := fmt.Sprintf("unable to create %s - status code: %d", e.Object, e.StatusCode)
+	for _, apiErr := range e.Errors {
+		msg += fmt.Sprintf(", %s: %s", apiErr.StatusCode, apiErr.Message)
+		if len(apiErr.Fields) > 0 {
+			msg += " [" + strings.Join(apiErr.Fields, ", ") + "]"
+		}
+	}
+	return msg
+}
+
+// HasCode whether salesforce reported an error with the given code, e.g. REQUIRED_FIELD_MISSING
+func (e CreateError) HasCode(code string) bool {
+	for _, apiErr := range e.Errors {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// restError an error of a failed REST API request, which names the code errorCode rather than statusCode
+type restError struct {
+	ErrorCode string   `json:"errorCode"`
+	Message   string   `json:"message"`
+	Fields    []string `json:"fields"`
+}
+
+// newCreateError decodes the errors of a failed create from body, the status alone is kept when body isn't the
+// expected json
+func newCreateError(object string, statusCode int, body []byte) CreateError {
+	e := CreateError{Object: object, StatusCode: statusCode}
+	var restErrs []restError
+	if json.Unmarshal(body, &restErrs) == nil {
+		for _, re := range restErrs {
+			e.Errors = append(e.Errors, ApiError{StatusCode: re.ErrorCode, Message: re.Message, Fields: re.Fields})
+		}
+		return e
+	}
+	var resp PostResponse
+	if json.Unmarshal(body, &resp) == nil {
+		e.Errors = resp.Errors
+	}
+	return e
+}
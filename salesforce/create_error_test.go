@@ -0,0 +1,59 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestPost_CreateError(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		want    CreateError
+		wantMsg string
+	}{
+		{
+			name: "bad request  errors decoded",
+			resp: newResponse(http.StatusBadRequest, `[{"message":"Required fields are missing: [Name]","errorCode":"REQUIRED_FIELD_MISSING","fields":["Name"]}]`),
+			want: CreateError{Object: "Account", StatusCode: http.StatusBadRequest, Errors: []ApiError{
+				{StatusCode: "REQUIRED_FIELD_MISSING", Message: "Required fields are missing: [Name]", Fields: []string{"Name"}},
+			}},
+			wantMsg: "unable to create Account - status code: 400, REQUIRED_FIELD_MISSING: Required fields are missing: [Name] [Name]",
+		},
+		{
+			name: "failure result  errors decoded",
+			resp: newResponse(http.StatusCreated, `{"id":"","success":false,"errors":[{"statusCode":"FIELD_CUSTOM_VALIDATION_EXCEPTION","message":"Invalid VAT number","fields":[]}]}`),
+			want: CreateError{Object: "Account", StatusCode: http.StatusCreated, Errors: []ApiError{
+				{StatusCode: "FIELD_CUSTOM_VALIDATION_EXCEPTION", Message: "Invalid VAT number", Fields: []string{}},
+			}},
+			wantMsg: "unable to create Account - status code: 201, FIELD_CUSTOM_VALIDATION_EXCEPTION: Invalid VAT number",
+		},
+		{
+			name:    "not json  status kept",
+			resp:    newResponse(http.StatusBadGateway, `<html>Bad Gateway</html>`),
+			want:    CreateError{Object: "Account", StatusCode: http.StatusBadGateway},
+			wantMsg: "unable to create Account - status code: 502",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewRequestHelper(newHttpClientMock(tt.resp, nil), newTokenGetterMock("token", nil), "https://org", 55)
+
+			_, err := Post(context.Background(), h, "Account", map[string]string{"Name": ""})
+			var got CreateError
+			if assert.True(t, errors.As(err, &got)) {
+				assert.Equal(t, tt.want, got)
+				assert.EqualError(t, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestCreateError_HasCode(t *testing.T) {
+	err := CreateError{Errors: []ApiError{{StatusCode: "DUPLICATE_VALUE"}}}
+	assert.True(t, err.HasCode("DUPLICATE_VALUE"))
+	assert.False(t, err.HasCode("REQUIRED_FIELD_MISSING"))
+}
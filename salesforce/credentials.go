@@ -0,0 +1,165 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Credentials the connected app details required to build and exchange a salesforce auth token
+type Credentials struct {
+	BaseUrl          string `json:"baseUrl"`
+	Hostname         string `json:"hostname"`
+	Username         string `json:"username"`
+	ClientId         string `json:"clientId"`
+	ClientSecret     string `json:"clientSecret"`
+	PrivateKeyBase64 string `json:"privateKeyBase64"`
+	// PrivateKeyPassphrase optional, required when the private key is an encrypted PKCS#8 key
+	PrivateKeyPassphrase string `json:"privateKeyPassphrase"`
+}
+
+// CredentialsProvider supplies the Credentials used by TokenFetcher, allowing the secret store to be swapped out
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// SecretsManagerCredentials reads Credentials stored as a json secret in AWS secrets manager
+type SecretsManagerCredentials struct {
+	client *secretsmanager.Client
+	key    string
+}
+
+func NewSecretsManagerCredentials(client *secretsmanager.Client, key string) *SecretsManagerCredentials {
+	return &SecretsManagerCredentials{
+		client: client,
+		key:    key,
+	}
+}
+
+func (s SecretsManagerCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	raw, err := s.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(s.key),
+	})
+	if err != nil {
+		return Credentials{}, fmt.Errorf("unable to fetch credentials from secrets manager: %w", err)
+	}
+
+	creds := Credentials{}
+	if err := json.Unmarshal([]byte(aws.ToString(raw.SecretString)), &creds); err != nil {
+		return Credentials{}, fmt.Errorf("unable to parse credentials from secrets manager: %w", err)
+	}
+	return creds, nil
+}
+
+type VaultParams struct {
+	HttpClient HttpClient `validate:"required"`
+	// Address of the vault server e.g. https://vault.internal:8200
+	Address string `validate:"required"`
+	Token   string `validate:"required"`
+	// Mount the KV secrets engine is mounted at e.g. secret
+	Mount string `validate:"required"`
+	// Path of the secret within the mount
+	Path string `validate:"required"`
+	// Namespace optional, vault enterprise only
+	Namespace string
+	// KVVersion of the secrets engine, 1 or 2. Defaults to 2
+	KVVersion int `validate:"omitempty,oneof=1 2"`
+}
+
+// VaultCredentials reads Credentials from a HashiCorp Vault KV secrets engine
+// for environments without access to AWS secrets manager
+type VaultCredentials struct {
+	httpClient HttpClient
+	secretUrl  string
+	token      string
+	namespace  string
+	kvVersion  int
+}
+
+func NewVaultCredentials(p VaultParams) (*VaultCredentials, error) {
+	if err := validate.Struct(p); err != nil {
+		return nil, err
+	}
+
+	kvVersion := p.KVVersion
+	if kvVersion == 0 {
+		kvVersion = 2
+	}
+
+	address := strings.TrimSuffix(p.Address, "/")
+	mount := strings.Trim(p.Mount, "/")
+	path := strings.Trim(p.Path, "/")
+	secretUrl := fmt.Sprintf("%s/v1/%s/%s", address, mount, path)
+	if kvVersion == 2 {
+		secretUrl = fmt.Sprintf("%s/v1/%s/data/%s", address, mount, path)
+	}
+
+	return &VaultCredentials{
+		httpClient: p.HttpClient,
+		secretUrl:  secretUrl,
+		token:      p.Token,
+		namespace:  p.Namespace,
+		kvVersion:  kvVersion,
+	}, nil
+}
+
+type vaultResponse struct {
+	Data json.RawMessage `json:"data"`
+}
+
+type vaultKV2Data struct {
+	Data json.RawMessage `json:"data"`
+}
+
+func (v VaultCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.secretUrl, nil)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("unable to create vault request: %w", err)
+	}
+	req.Header = http.Header{
+		"X-Vault-Token": {v.token},
+	}
+	if len(v.namespace) > 0 {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("unable to fetch credentials from vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Credentials{}, fmt.Errorf("unexpected vault response code: %d", resp.StatusCode)
+	}
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("unable to read vault response: %w", err)
+	}
+
+	var parsedResp vaultResponse
+	if err = json.Unmarshal(resBody, &parsedResp); err != nil {
+		return Credentials{}, fmt.Errorf("unable to parse vault response: %w", err)
+	}
+
+	data := parsedResp.Data
+	if v.kvVersion == 2 {
+		var kv2 vaultKV2Data
+		if err = json.Unmarshal(data, &kv2); err != nil {
+			return Credentials{}, fmt.Errorf("unable to parse vault response: %w", err)
+		}
+		data = kv2.Data
+	}
+
+	creds := Credentials{}
+	if err = json.Unmarshal(data, &creds); err != nil {
+		return Credentials{}, fmt.Errorf("unable to parse credentials from vault: %w", err)
+	}
+	return creds, nil
+}
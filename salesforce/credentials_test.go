@@ -0,0 +1,190 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewVaultCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       VaultParams
+		wantUrl string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "kv version not set, defaults to kv2 url",
+			p: VaultParams{
+				HttpClient: new(HttpClientMock),
+				Address:    "https://vault:8200/",
+				Token:      "token",
+				Mount:      "secret",
+				Path:       "/salesforce/creds",
+			},
+			wantUrl: "https://vault:8200/v1/secret/data/salesforce/creds",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "kv version 1, kv1 url",
+			p: VaultParams{
+				HttpClient: new(HttpClientMock),
+				Address:    "https://vault:8200",
+				Token:      "token",
+				Mount:      "kv",
+				Path:       "salesforce",
+				KVVersion:  1,
+			},
+			wantUrl: "https://vault:8200/v1/kv/salesforce",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "invalid kv version, returns error",
+			p: VaultParams{
+				HttpClient: new(HttpClientMock),
+				Address:    "https://vault:8200",
+				Token:      "token",
+				Mount:      "kv",
+				Path:       "salesforce",
+				KVVersion:  3,
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "token missing, returns error",
+			p: VaultParams{
+				HttpClient: new(HttpClientMock),
+				Address:    "https://vault:8200",
+				Mount:      "kv",
+				Path:       "salesforce",
+			},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewVaultCredentials(tt.p)
+			if !tt.wantErr(t, err, fmt.Sprintf("NewVaultCredentials(%v)", tt.p)) || err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantUrl, got.secretUrl)
+		})
+	}
+}
+
+func TestVaultCredentials_Credentials(t *testing.T) {
+	tests := []struct {
+		name      string
+		kvVersion int
+		resp      *http.Response
+		respErr   error
+		want      Credentials
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "kv2 response, returns credentials",
+			kvVersion: 2,
+			resp: &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(`{"data":{"data":{"baseUrl":"https://sf","username":"user","clientId":"client"},"metadata":{}}}`)),
+			},
+			want:    Credentials{BaseUrl: "https://sf", Username: "user", ClientId: "client"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:      "kv1 response, returns credentials",
+			kvVersion: 1,
+			resp: &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(`{"data":{"baseUrl":"https://sf","username":"user"}}`)),
+			},
+			want:    Credentials{BaseUrl: "https://sf", Username: "user"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:      "403 response, returns error",
+			kvVersion: 2,
+			resp: &http.Response{
+				StatusCode: 403,
+				Body:       io.NopCloser(strings.NewReader(`{"errors":["permission denied"]}`)),
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name:      "invalid json, returns error",
+			kvVersion: 2,
+			resp: &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(`{invalid`)),
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name:      "client error, returns error",
+			kvVersion: 2,
+			respErr:   errors.New("http error"),
+			wantErr:   assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.Header.Get("X-Vault-Token") == "token"
+			})).Return(tt.resp, tt.respErr)
+
+			v, err := NewVaultCredentials(VaultParams{
+				HttpClient: client,
+				Address:    "https://vault:8200",
+				Token:      "token",
+				Mount:      "secret",
+				Path:       "salesforce",
+				KVVersion:  tt.kvVersion,
+			})
+			assert.NoError(t, err)
+
+			got, err := v.Credentials(context.Background())
+			if !tt.wantErr(t, err, "Credentials(<context>)") {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTokenParams_Validation(t *testing.T) {
+	vault, _ := NewVaultCredentials(VaultParams{
+		HttpClient: new(HttpClientMock),
+		Address:    "https://vault:8200",
+		Token:      "token",
+		Mount:      "secret",
+		Path:       "salesforce",
+	})
+	tests := []struct {
+		name    string
+		p       TokenParams
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "credentials provider set without secrets manager, no error",
+			p:       TokenParams{HttpClient: new(HttpClientMock), Credentials: vault},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "neither credentials provider nor secrets manager set, returns error",
+			p:       TokenParams{HttpClient: new(HttpClientMock)},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wantErr(t, validate.Struct(tt.p))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CursorKind how a Cursor pages through records
+type CursorKind string
+
+const (
+	// CursorQueryLocator pages with a query's nextRecordsUrl, see QueryMore
+	CursorQueryLocator CursorKind = "locator"
+	// CursorBulkLocator pages the results of a Bulk API 2.0 query job with its Sforce-Locator, see GetBulkQueryResults
+	CursorBulkLocator CursorKind = "bulk"
+	// CursorOffset pages with LIMIT and OFFSET, see QueryPage
+	CursorOffset CursorKind = "offset"
+)
+
+// Cursor the position of a paged read of a query's records, whichever kind of paging it uses, which can be encoded as
+// an opaque page token e.g. to pass through our own HTTP APIs
+// - a Cursor never holds the query, the caller supplies it for each page, so a token can't be used to run another query
+type Cursor struct {
+	Kind CursorKind `json:"k"`
+	// NextRecordsUrl the next page of a CursorQueryLocator, the first page when empty
+	NextRecordsUrl string `json:"n,omitempty"`
+	// JobId and Locator the job and next page of a CursorBulkLocator, a job is created for the first page when JobId
+	// is empty
+	JobId   string `json:"j,omitempty"`
+	Locator string `json:"l,omitempty"`
+	// Page the page of a CursorOffset, numbered from 1
+	Page int `json:"p,omitempty"`
+	// PageSize the records per page of a CursorOffset, and the most per page of a CursorBulkLocator, all that fit in a
+	// response when 0
+	PageSize int `json:"s,omitempty"`
+}
+
+// QueryLocatorCursor a cursor at a query's nextRecordsUrl, or its first page when nextRecordsUrl is empty
+func QueryLocatorCursor(nextRecordsUrl string) Cursor {
+	return Cursor{Kind: CursorQueryLocator, NextRecordsUrl: nextRecordsUrl}
+}
+
+// BulkLocatorCursor a cursor at the locator of a Bulk API 2.0 query job's results, of up to pageSize records, or at
+// the first page of a new job when jobId is empty
+func BulkLocatorCursor(jobId, locator string, pageSize int) Cursor {
+	return Cursor{Kind: CursorBulkLocator, JobId: jobId, Locator: locator, PageSize: pageSize}
+}
+
+// OffsetCursor a cursor at page, numbered from 1, of pageSize records
+func OffsetCursor(page, pageSize int) Cursor {
+	return Cursor{Kind: CursorOffset, Page: page, PageSize: pageSize}
+}
+
+// Encode the cursor as an opaque url safe page token. Tokens aren't signed, DecodeCursor checks they are well formed
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decodes a page token made by Cursor.Encode, returning an error for a malformed or tampered token
+func DecodeCursor(token string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid page token: %w", err)
+	}
+	if err = json.Unmarshal(b, &c); err != nil {
+		return Cursor{}, fmt.Errorf("invalid page token: %w", err)
+	}
+	if err = c.validate(); err != nil {
+		return Cursor{}, fmt.Errorf("invalid page token: %w", err)
+	}
+	return c, nil
+}
+
+// validate checks the fields of the cursor's kind are well formed, e.g. a locator is a query url
+func (c Cursor) validate() error {
+	switch c.Kind {
+	case CursorQueryLocator:
+		if len(c.NextRecordsUrl) > 0 && (!strings.HasPrefix(c.NextRecordsUrl, defaultDataPath+"/") || !strings.Contains(c.NextRecordsUrl, "/query/")) {
+			return fmt.Errorf("next records url %q is not a query locator", c.NextRecordsUrl)
+		}
+	case CursorBulkLocator:
+		if len(c.JobId) > 0 && !ValidId(c.JobId) {
+			return fmt.Errorf("invalid job id %q", c.JobId)
+		}
+		if len(c.JobId) == 0 && len(c.Locator) > 0 {
+			return fmt.Errorf("locator %q needs a job id", c.Locator)
+		}
+		if c.PageSize < 0 {
+			return fmt.Errorf("page size must not be negative")
+		}
+	case CursorOffset:
+		if c.Page < 1 || c.PageSize < 1 {
+			return fmt.Errorf("page and page size must be at least 1")
+		}
+	default:
+		return fmt.Errorf("unknown cursor kind %q", c.Kind)
+	}
+	return nil
+}
+
+// FetchPage fetches the page of query q at cursor c, returning its records decoded as E and the cursor of the next
+// page, nil after the last page
+// - q is used for the first page of a CursorQueryLocator and every page of a CursorOffset, and must be the same query
+// for every page of a read
+// - the first page of a CursorBulkLocator without a job id creates a Bulk API 2.0 query job for q and waits for it to
+// complete, within the Poll timeout of h, later pages read the job's results from their locator
+func FetchPage[E any](ctx context.Context, h *RequestHelper, q string, c Cursor) ([]E, *Cursor, error) {
+	if err := c.validate(); err != nil {
+		return nil, nil, err
+	}
+	switch c.Kind {
+	case CursorQueryLocator:
+		var resp *QueryResponse[E]
+		var err error
+		if len(c.NextRecordsUrl) == 0 {
+			resp, err = Query[E](ctx, h, q)
+		} else {
+			resp, err = QueryMore[E](ctx, h, c.NextRecordsUrl)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.Done || len(resp.NextRecordsUrl) == 0 {
+			return resp.Records, nil, nil
+		}
+		next := QueryLocatorCursor(resp.NextRecordsUrl)
+		return resp.Records, &next, nil
+	case CursorOffset:
+		page, err := QueryPage[E](ctx, h, q, c.Page, c.PageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !page.HasMore {
+			return page.Records, nil, nil
+		}
+		next := OffsetCursor(c.Page+1, c.PageSize)
+		return page.Records, &next, nil
+	default:
+		if len(c.JobId) == 0 {
+			job, err := CreateBulkQueryJob(ctx, h, q, false)
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, err = WaitForBulkQueryJob(ctx, h, job.Id, 0); err != nil {
+				return nil, nil, err
+			}
+			c.JobId = job.Id
+		}
+		records, locator, err := GetBulkQueryResults[E](ctx, h, c.JobId, c.Locator, c.PageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(locator) == 0 {
+			return records, nil, nil
+		}
+		next := BulkLocatorCursor(c.JobId, locator, c.PageSize)
+		return records, &next, nil
+	}
+}
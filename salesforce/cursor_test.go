@@ -0,0 +1,148 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestCursor_Encode(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		want    Cursor
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "query locator  round trips",
+			token:   QueryLocatorCursor("/services/data/v55.0/query/01gxx0000000001-2000").Encode(),
+			want:    QueryLocatorCursor("/services/data/v55.0/query/01gxx0000000001-2000"),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "bulk locator  round trips",
+			token:   BulkLocatorCursor("750xx0000000001", "MTAwMDA", 500).Encode(),
+			want:    BulkLocatorCursor("750xx0000000001", "MTAwMDA", 500),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "bulk locator without job  error returned",
+			token:   BulkLocatorCursor("", "MTAwMDA", 0).Encode(),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "offset  round trips",
+			token:   OffsetCursor(3, 25).Encode(),
+			want:    OffsetCursor(3, 25),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "locator to another resource  error returned",
+			token:   QueryLocatorCursor("/services/data/v55.0/sobjects/User/005xx0000000001").Encode(),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "unknown kind  error returned",
+			token:   base64.RawURLEncoding.EncodeToString([]byte(`{"k":"sosl"}`)),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "not base64  error returned",
+			token:   "not a token!",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.token)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFetchPage(t *testing.T) {
+	type account struct {
+		Id string
+	}
+	const q = "SELECT Id FROM Account ORDER BY Name"
+
+	t.Run("query locator  pages followed", func(t *testing.T) {
+		client := new(HttpClientMock)
+		client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/services/data/v55.0/query/01gxx0000000001-1" {
+				return newResponse(http.StatusOK, `{"totalSize":2,"done":true,"records":[{"Id":"001xx0000000002"}]}`), nil
+			}
+			assert.Equal(t, q, req.URL.Query().Get("q"))
+			return newResponse(http.StatusOK, `{"totalSize":2,"done":false,"nextRecordsUrl":"/services/data/v55.0/query/01gxx0000000001-1",
+				"records":[{"Id":"001xx0000000001"}]}`), nil
+		})
+		h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+		got, next, err := FetchPage[account](context.Background(), h, q, QueryLocatorCursor(""))
+		assert.NoError(t, err)
+		assert.Equal(t, []account{{Id: "001xx0000000001"}}, got)
+		if assert.NotNil(t, next) {
+			c, err := DecodeCursor(next.Encode())
+			assert.NoError(t, err)
+			got, next, err = FetchPage[account](context.Background(), h, q, c)
+			assert.NoError(t, err)
+			assert.Equal(t, []account{{Id: "001xx0000000002"}}, got)
+			assert.Nil(t, next)
+		}
+	})
+
+	t.Run("offset  next page cursor returned", func(t *testing.T) {
+		client := new(HttpClientMock)
+		client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, q+" LIMIT 2 OFFSET 1", req.URL.Query().Get("q"))
+			return newResponse(http.StatusOK, `{"totalSize":2,"done":true,"records":[{"Id":"001xx0000000002"},{"Id":"001xx0000000003"}]}`), nil
+		})
+		h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+		got, next, err := FetchPage[account](context.Background(), h, q, OffsetCursor(2, 1))
+		assert.NoError(t, err)
+		assert.Equal(t, []account{{Id: "001xx0000000002"}}, got)
+		assert.Equal(t, &Cursor{Kind: CursorOffset, Page: 3, PageSize: 1}, next)
+	})
+
+	t.Run("bulk locator  job created and pages followed", func(t *testing.T) {
+		client := new(HttpClientMock)
+		client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+			switch {
+			case req.Method == http.MethodPost:
+				body, _ := io.ReadAll(req.Body)
+				assert.JSONEq(t, `{"operation":"query","query":"`+q+`","contentType":"CSV","lineEnding":"LF"}`, string(body))
+				return newResponse(http.StatusOK, `{"id":"750xx0000000001","state":"UploadComplete"}`), nil
+			case req.URL.Path == "/services/data/v55.0/jobs/query/750xx0000000001":
+				return newResponse(http.StatusOK, `{"id":"750xx0000000001","state":"JobComplete"}`), nil
+			}
+			assert.Equal(t, "/services/data/v55.0/jobs/query/750xx0000000001/results", req.URL.Path)
+			assert.Equal(t, "1", req.URL.Query().Get("maxRecords"))
+			resp := newResponse(http.StatusOK, "\"Id\"\n\"001xx0000000002\"\n")
+			resp.Header = http.Header{"Sforce-Locator": {"null"}}
+			if req.URL.Query().Get("locator") == "" {
+				resp = newResponse(http.StatusOK, "\"Id\"\n\"001xx0000000001\"\n")
+				resp.Header = http.Header{"Sforce-Locator": {"MQ"}}
+			}
+			return resp, nil
+		})
+		h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+		got, next, err := FetchPage[account](context.Background(), h, q, BulkLocatorCursor("", "", 1))
+		assert.NoError(t, err)
+		assert.Equal(t, []account{{Id: "001xx0000000001"}}, got)
+		if assert.Equal(t, &Cursor{Kind: CursorBulkLocator, JobId: "750xx0000000001", Locator: "MQ", PageSize: 1}, next) {
+			got, next, err = FetchPage[account](context.Background(), h, q, *next)
+			assert.NoError(t, err)
+			assert.Equal(t, []account{{Id: "001xx0000000002"}}, got)
+			assert.Nil(t, next)
+		}
+		// create, poll and two pages
+		client.AssertNumberOfCalls(t, "Do", 4)
+	})
+}
@@ -0,0 +1,117 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DashboardRefreshRunning the refresh status of a component being refreshed, IDLE otherwise
+const DashboardRefreshRunning = "RUNNING"
+
+// DashboardComponent a component of a dashboard, e.g. a chart of a report
+type DashboardComponent struct {
+	Id       string `json:"id"`
+	Header   string `json:"header"`
+	Footer   string `json:"footer"`
+	Type     string `json:"type"`
+	ReportId string `json:"reportId"`
+}
+
+// DashboardMetadata the definition of a dashboard
+type DashboardMetadata struct {
+	Id         string               `json:"id"`
+	Name       string               `json:"name"`
+	Components []DashboardComponent `json:"components"`
+}
+
+// DashboardComponentStatus the data and refresh status of a dashboard component
+type DashboardComponentStatus struct {
+	// DataStatus DATA, NODATA or ERROR
+	DataStatus    string `json:"dataStatus"`
+	ErrorCode     string `json:"errorCode"`
+	ErrorMessage  string `json:"errorMessage"`
+	RefreshDate   string `json:"refreshDate"`
+	RefreshStatus string `json:"refreshStatus"`
+}
+
+// DashboardComponentData the results of a dashboard component, as of its last refresh
+type DashboardComponentData struct {
+	ComponentId  string                   `json:"componentId"`
+	Status       DashboardComponentStatus `json:"status"`
+	ReportResult *ReportResult            `json:"reportResult"`
+}
+
+// Dashboard a dashboard with the results of its components
+type Dashboard struct {
+	DashboardMetadata DashboardMetadata        `json:"dashboardMetadata"`
+	ComponentData     []DashboardComponentData `json:"componentData"`
+}
+
+// Component the results of the component with id, nil when the dashboard has no such component
+func (d Dashboard) Component(id string) *DashboardComponentData {
+	for i := range d.ComponentData {
+		if d.ComponentData[i].ComponentId == id {
+			return &d.ComponentData[i]
+		}
+	}
+	return nil
+}
+
+// DashboardRefreshStatus the refresh status of a dashboard component
+type DashboardRefreshStatus struct {
+	ComponentId   string `json:"componentId"`
+	RefreshDate   string `json:"refreshDate"`
+	RefreshStatus string `json:"refreshStatus"`
+}
+
+// DashboardStatus the refresh status of each component of a dashboard
+type DashboardStatus struct {
+	ComponentStatus []DashboardRefreshStatus `json:"componentStatus"`
+}
+
+// Refreshing whether any component is being refreshed
+func (s DashboardStatus) Refreshing() bool {
+	for _, c := range s.ComponentStatus {
+		if c.RefreshStatus == DashboardRefreshRunning {
+			return true
+		}
+	}
+	return false
+}
+
+func dashboardPath(id string) string {
+	return "/analytics/dashboards/" + url.PathEscape(id)
+}
+
+// GetDashboard fetches a dashboard and the results of its components, as of its last refresh
+func GetDashboard(ctx context.Context, h *RequestHelper, id string) (*Dashboard, error) {
+	reqUrl, err := h.dataUrl(ctx, dashboardPath(id))
+	if err != nil {
+		return nil, err
+	}
+	return getJson[Dashboard](ctx, h, reqUrl)
+}
+
+// RefreshDashboard triggers a refresh of a dashboard, poll GetDashboardStatus until it is no longer Refreshing.
+// Salesforce limits how often a dashboard can be refreshed
+func RefreshDashboard(ctx context.Context, h *RequestHelper, id string) error {
+	reqUrl, err := h.dataUrl(ctx, dashboardPath(id))
+	if err != nil {
+		return err
+	}
+	if _, err = sendJson[struct{}](ctx, h, http.MethodPut, reqUrl, nil); err != nil {
+		return fmt.Errorf("unable to refresh salesforce dashboard %s: %w", id, err)
+	}
+	return nil
+}
+
+// GetDashboardStatus fetches the refresh status of each component of a dashboard
+func GetDashboardStatus(ctx context.Context, h *RequestHelper, id string) (*DashboardStatus, error) {
+	reqUrl, err := h.dataUrl(ctx, dashboardPath(id)+"/status")
+	if err != nil {
+		return nil, err
+	}
+	return getJson[DashboardStatus](ctx, h, reqUrl)
+}
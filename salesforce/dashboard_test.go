@@ -0,0 +1,71 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+)
+
+func TestGetDashboard(t *testing.T) {
+	client := newHttpClientMock(newResponse(http.StatusOK, `{
+		"dashboardMetadata": {"id": "01Zxx0000000001", "name": "Ops", "components": [{"id": "01axx0000000001", "header": "Open Cases", "reportId": "00Oxx0000000001"}]},
+		"componentData": [{"componentId": "01axx0000000001", "status": {"dataStatus": "DATA", "refreshStatus": "IDLE"},
+			"reportResult": {"factMap": {"T!T": {"aggregates": [{"label": "12", "value": 12}]}}}}]
+	}`), nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := GetDashboard(context.Background(), h, "01Zxx0000000001")
+	assert.NoError(t, err)
+	assert.Equal(t, "Open Cases", got.DashboardMetadata.Components[0].Header)
+	c := got.Component("01axx0000000001")
+	if assert.NotNil(t, c) {
+		assert.Equal(t, "DATA", c.Status.DataStatus)
+		assert.Equal(t, "12", c.ReportResult.FactMap["T!T"].Aggregates[0].Label)
+	}
+	assert.Nil(t, got.Component("01axx0000000002"))
+}
+
+func TestRefreshDashboard(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "refresh accepted  no error",
+			resp:    newResponse(http.StatusCreated, `{"id":"01Zxx0000000001","statusUrl":"/services/data/v55.0/analytics/dashboards/01Zxx0000000001/status"}`),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "refreshed too recently  error",
+			resp:    newResponse(http.StatusForbidden, `[{"errorCode":"FORBIDDEN"}]`),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.Method == http.MethodPut && req.URL.Path == "/services/data/v55.0/analytics/dashboards/01Zxx0000000001"
+			})).Return(tt.resp, nil)
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			tt.wantErr(t, RefreshDashboard(context.Background(), h, "01Zxx0000000001"))
+		})
+	}
+}
+
+func TestGetDashboardStatus(t *testing.T) {
+	client := newHttpClientMock(newResponse(http.StatusOK, `{"componentStatus":[
+		{"componentId":"01axx0000000001","refreshStatus":"IDLE"},
+		{"componentId":"01axx0000000002","refreshStatus":"RUNNING"}
+	]}`), nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := GetDashboardStatus(context.Background(), h, "01Zxx0000000001")
+	assert.NoError(t, err)
+	assert.True(t, got.Refreshing())
+	assert.False(t, DashboardStatus{ComponentStatus: got.ComponentStatus[:1]}.Refreshing())
+}
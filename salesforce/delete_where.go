@@ -0,0 +1,121 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DeleteWhereResult the outcome of DeleteWhere
+type DeleteWhereResult struct {
+	// Matched the number of records the where clause matched
+	Matched int
+	// Results a CollectionResult per matched record, empty for a dry run
+	Results []CollectionResult
+}
+
+// Deleted the number of records successfully deleted
+func (r DeleteWhereResult) Deleted() int {
+	deleted := 0
+	for _, result := range r.Results {
+		if result.Success {
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// DeleteWhere deletes the records of object matching where, e.g. "CreatedDate < LAST_N_DAYS:90", in sObject Collections
+// requests of up to 200 records or, above 2,000 matches, Bulk API 2.0 delete jobs, see WithBulkThreshold. WithDryRun
+// only counts the matching records
+// - the ids are deleted as the query returns them, at most the bulk threshold, or a bulk job's upload, is held at once
+// - WithAllOrNone deletes none of the records if any fails, returning an AllOrNoneError
+// - a record failing to delete doesn't fail the others, see the Results
+// - on a request error the results of the chunks or jobs already sent are returned along with the error
+func DeleteWhere(ctx context.Context, h *RequestHelper, object, where string, opts ...RequestOption) (*DeleteWhereResult, error) {
+	if len(strings.TrimSpace(where)) == 0 {
+		return nil, fmt.Errorf("where clause needs to be provided, use \"Id != null\" to delete every record")
+	}
+	o := newRequestOptions(opts)
+	result := &DeleteWhereResult{}
+
+	// ids matched but not yet deleted, sent in chunks of 200, or collected up to the bulk threshold to see whether a
+	// bulk job is needed, after which they are added to the job as they arrive
+	var pending []string
+	var bulk *bulkIngest
+	err := QueryEach(ctx, h, fmt.Sprintf("SELECT Id FROM %s WHERE %s", object, where), func(r struct{ Id string }) error {
+		result.Matched++
+		switch {
+		case o.dryRun:
+			return nil
+		case bulk != nil:
+			return bulk.add(ctx, []string{r.Id})
+		case o.allOrNone:
+			// a single request, over 200 records is an error once they're counted
+			if len(pending) < collectionsMaxRecords+1 {
+				pending = append(pending, r.Id)
+			}
+			return nil
+		}
+		pending = append(pending, r.Id)
+		if o.bulkThreshold <= 0 {
+			if len(pending) < collectionsMaxRecords {
+				return nil
+			}
+			err := deleteIds(ctx, h, pending, false, result)
+			pending = pending[:0]
+			return err
+		}
+		if !o.useBulk(len(pending)) {
+			return nil
+		}
+		bulk = newBulkIngest(h, object, BulkDelete, "", "Id", []string{"Id"})
+		for _, id := range pending {
+			if err := bulk.add(ctx, []string{id}); err != nil {
+				return err
+			}
+		}
+		pending = nil
+		return nil
+	})
+	if bulk != nil {
+		if err == nil {
+			err = bulk.flush(ctx)
+		}
+		result.Results = append(result.Results, bulk.results...)
+		return result, err
+	}
+	if err != nil || o.dryRun {
+		return result, err
+	}
+	if err = o.checkAllOrNone(result.Matched); err != nil {
+		return result, err
+	}
+
+	for start := 0; start < len(pending); start += collectionsMaxRecords {
+		if err = deleteIds(ctx, h, pending[start:min(start+collectionsMaxRecords, len(pending))], o.allOrNone, result); err != nil {
+			return result, err
+		}
+	}
+	return result, o.allOrNoneError(result.Results)
+}
+
+// deleteIds deletes up to 200 records by id in a single sObject Collections request, adding their results to result
+func deleteIds(ctx context.Context, h *RequestHelper, ids []string, allOrNone bool, result *DeleteWhereResult) error {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/composite/sobjects?allOrNone=%t&ids=", allOrNone)+url.QueryEscape(strings.Join(ids, ",")))
+	if err != nil {
+		return err
+	}
+	req, err := h.newRequest(ctx, http.MethodDelete, reqUrl, nil)
+	if err != nil {
+		return err
+	}
+	results, err := collectionResults(h, req, len(ids))
+	if err != nil {
+		return err
+	}
+	result.Results = append(result.Results, results...)
+	return nil
+}
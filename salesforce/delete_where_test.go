@@ -0,0 +1,135 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// deleteWhereResponder responds to the id query with n records, and to deletes with a result per id, failing the
+// first
+func deleteWhereResponder(n int) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			records := make([]string, n)
+			for i := range records {
+				records[i] = fmt.Sprintf(`{"Id":"001xx%013d"}`, i)
+			}
+			return newResponse(200, fmt.Sprintf(`{"totalSize":%d,"done":true,"records":[%s]}`, n, strings.Join(records, ","))), nil
+		}
+		ids := strings.Split(req.URL.Query().Get("ids"), ",")
+		results := make([]string, len(ids))
+		for i, id := range ids {
+			results[i] = fmt.Sprintf(`{"id":%q,"success":%t}`, id, id != "001xx0000000000000")
+		}
+		return newResponse(200, "["+strings.Join(results, ",")+"]"), nil
+	}
+}
+
+func TestDeleteWhere(t *testing.T) {
+	tests := []struct {
+		name        string
+		matched     int
+		opts        []RequestOption
+		wantDeleted int
+		wantCalls   int
+	}{
+		{
+			name:        "matches over collection size  deleted in chunks",
+			matched:     250,
+			wantDeleted: 249,
+			wantCalls:   3,
+		},
+		{
+			name:        "dry run  matches counted only",
+			matched:     250,
+			opts:        []RequestOption{WithDryRun()},
+			wantDeleted: 0,
+			wantCalls:   1,
+		},
+		{
+			name:        "no matches  nothing deleted",
+			matched:     0,
+			wantDeleted: 0,
+			wantCalls:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(deleteWhereResponder(tt.matched))
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := DeleteWhere(context.Background(), h, "Account", "CreatedDate < LAST_N_DAYS:90", tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.matched, got.Matched)
+			assert.Equal(t, tt.wantDeleted, got.Deleted())
+			client.AssertNumberOfCalls(t, "Do", tt.wantCalls)
+		})
+	}
+}
+
+func TestDeleteWhere_NoWhereClause(t *testing.T) {
+	client := newHttpClientMock(nil, nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	_, err := DeleteWhere(context.Background(), h, "Account", " ")
+	assert.Error(t, err)
+	client.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestDeleteWhere_Bulk(t *testing.T) {
+	server := newBulkIngestServer()
+	responder := deleteWhereResponder(250)
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.Path, "/jobs/ingest") {
+			return server.Do(req)
+		}
+		return responder(req)
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := DeleteWhere(context.Background(), h, "Account", "CreatedDate < LAST_N_DAYS:90", WithBulkThreshold(100))
+	assert.NoError(t, err)
+	assert.Equal(t, 250, got.Matched)
+	assert.Equal(t, 250, got.Deleted())
+	assert.Equal(t, 1, server.jobs)
+	assert.Len(t, server.uploads[0], 251)
+	assert.Equal(t, []string{"Id"}, server.uploads[0][0])
+	// query, then create, upload, close, poll, successful and failed results
+	client.AssertNumberOfCalls(t, "Do", 7)
+}
+
+func TestDeleteWhere_StreamsDeletes(t *testing.T) {
+	var calls []string
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			// two pages of 200 ids
+			page, next := 0, `,"nextRecordsUrl":"/services/data/v55.0/query/01g-200"`
+			if strings.HasSuffix(req.URL.Path, "01g-200") {
+				page, next = 1, ""
+			}
+			calls = append(calls, fmt.Sprintf("query %d", page))
+			records := make([]string, collectionsMaxRecords)
+			for i := range records {
+				records[i] = fmt.Sprintf(`{"Id":"001xx%013d"}`, page*collectionsMaxRecords+i)
+			}
+			return newResponse(200, fmt.Sprintf(`{"totalSize":400,"done":%t%s,"records":[%s]}`, page == 1, next, strings.Join(records, ","))), nil
+		}
+		calls = append(calls, "delete")
+		return deleteWhereResponder(0)(req)
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := DeleteWhere(context.Background(), h, "Account", "CreatedDate < LAST_N_DAYS:90", WithBulkThreshold(0))
+	assert.NoError(t, err)
+	assert.Equal(t, 400, got.Matched)
+	assert.Len(t, got.Results, 400)
+	assert.Equal(t, []string{"query 0", "delete", "query 1", "delete"}, calls)
+}
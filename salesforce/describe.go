@@ -0,0 +1,55 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Describe fetches the describe of an object, its fields and their permissions for the running user
+func Describe(ctx context.Context, h *RequestHelper, name string) (*ObjectDescribe, error) {
+	if len(name) == 0 {
+		return nil, fmt.Errorf("object name needs to be provided")
+	}
+	reqUrl, err := h.dataUrl(ctx, "/sobjects/"+url.PathEscape(name)+"/describe")
+	if err != nil {
+		return nil, err
+	}
+	return getJson[ObjectDescribe](ctx, h, reqUrl)
+}
+
+// cachedDescribe returns the describe of an object, fetching it once per RequestHelper as metadata rarely changes
+// while a service runs
+func (h *RequestHelper) cachedDescribe(ctx context.Context, name string) (*ObjectDescribe, error) {
+	key := strings.ToLower(name)
+	if d, ok := h.describes.Load(key); ok {
+		return d.(*ObjectDescribe), nil
+	}
+	d, err := Describe(ctx, h, name)
+	if err != nil {
+		return nil, err
+	}
+	h.describes.Store(key, d)
+	return d, nil
+}
+
+// FieldError a problem with a single field of a payload
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError returned when a payload doesn't match the describe of its object, before it is sent
+type ValidationError struct {
+	Object string
+	Errors []FieldError
+}
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return fmt.Sprintf("invalid %s payload: %s", e.Object, strings.Join(msgs, "; "))
+}
@@ -0,0 +1,41 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	client := newHttpClientMock(newResponse(200, `{"name":"Account","label":"Account","keyPrefix":"001","createable":true,"fields":[{"name":"Name","type":"string","length":255,"nillable":false,"createable":true,"updateable":true}]}`), nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := Describe(context.Background(), h, "Account")
+	assert.NoError(t, err)
+	assert.Equal(t, "001", got.KeyPrefix)
+	if assert.NotNil(t, got.Field("name")) {
+		assert.Equal(t, 255, got.Field("name").Length)
+		assert.True(t, got.Field("name").Required())
+	}
+	assert.Nil(t, got.Field("Missing__c"))
+	client.AssertCalled(t, "Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://org/services/data/v55.0/sobjects/Account/describe"
+	}))
+}
+
+func TestRequestHelper_cachedDescribe(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(*http.Request) (*http.Response, error) {
+		return newResponse(200, `{"name":"Account","fields":[]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	for _, name := range []string{"Account", "account"} {
+		got, err := h.cachedDescribe(context.Background(), name)
+		assert.NoError(t, err)
+		assert.Equal(t, "Account", got.Name)
+	}
+	client.AssertNumberOfCalls(t, "Do", 1)
+}
@@ -0,0 +1,53 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Diff returns the fields of modified whose json differs from original, as a minimal body for Patch so untouched
+// fields aren't sent and can't trip validation rules. original and modified are structs of the same type, or maps.
+// Fields missing from modified, e.g. dropped by omitempty, are not treated as changes, use Nullable to clear a field
+func Diff(original, modified any) (map[string]json.RawMessage, error) {
+	before, err := jsonFields(original)
+	if err != nil {
+		return nil, fmt.Errorf("unable to diff original record: %w", err)
+	}
+	after, err := jsonFields(modified)
+	if err != nil {
+		return nil, fmt.Errorf("unable to diff modified record: %w", err)
+	}
+
+	changed := map[string]json.RawMessage{}
+	for name, value := range after {
+		if name == "attributes" {
+			continue
+		}
+		if prev, ok := before[name]; ok && bytes.Equal(prev, value) {
+			continue
+		}
+		changed[name] = value
+	}
+	return changed, nil
+}
+
+// jsonFields marshals record into its top level json fields
+func jsonFields(record any) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		var compact bytes.Buffer
+		if err = json.Compact(&compact, value); err != nil {
+			return nil, err
+		}
+		fields[name] = compact.Bytes()
+	}
+	return fields, nil
+}
@@ -0,0 +1,86 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type diffStub struct {
+	Attributes  *Attributes    `json:"attributes,omitempty"`
+	Name        string         `json:"Name"`
+	Description string         `json:"Description,omitempty"`
+	Amount      float64        `json:"Amount"`
+	Address     map[string]any `json:"Address,omitempty"`
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		original any
+		modified any
+		want     map[string]string
+	}{
+		{
+			name:     "no changes  empty diff",
+			original: diffStub{Name: "a", Amount: 1},
+			modified: diffStub{Name: "a", Amount: 1},
+			want:     map[string]string{},
+		},
+		{
+			name:     "one field changed  only that field",
+			original: diffStub{Name: "a", Amount: 1},
+			modified: diffStub{Name: "a", Amount: 2},
+			want:     map[string]string{"Amount": `2`},
+		},
+		{
+			name:     "omitempty field set  field included",
+			original: diffStub{Name: "a"},
+			modified: diffStub{Name: "a", Description: "new"},
+			want:     map[string]string{"Description": `"new"`},
+		},
+		{
+			name:     "omitempty field emptied  not treated as change",
+			original: diffStub{Name: "a", Description: "old"},
+			modified: diffStub{Name: "a"},
+			want:     map[string]string{},
+		},
+		{
+			name:     "nested value changed  whole value included",
+			original: diffStub{Name: "a", Address: map[string]any{"City": "London", "Street": "High St"}},
+			modified: diffStub{Name: "a", Address: map[string]any{"City": "Leeds", "Street": "High St"}},
+			want:     map[string]string{"Address": `{"City":"Leeds","Street":"High St"}`},
+		},
+		{
+			name:     "attributes  never included",
+			original: diffStub{Name: "a"},
+			modified: diffStub{Name: "a", Attributes: &Attributes{Type: "Account"}},
+			want:     map[string]string{},
+		},
+		{
+			name:     "maps  changed keys included",
+			original: map[string]any{"Name": "a", "Amount": 1},
+			modified: map[string]any{"Name": "b", "Amount": 1},
+			want:     map[string]string{"Name": `"b"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(tt.original, tt.modified)
+			assert.NoError(t, err)
+			gotStrings := map[string]string{}
+			for k, v := range got {
+				gotStrings[k] = string(v)
+			}
+			assert.Equal(t, tt.want, gotStrings)
+
+			_, err = json.Marshal(got)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDiff_NotAnObject(t *testing.T) {
+	_, err := Diff("a", "b")
+	assert.Error(t, err)
+}
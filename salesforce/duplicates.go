@@ -0,0 +1,115 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// duplicateRuleHeader blocks saving a record matched by an active duplicate rule, returning the matches instead
+const duplicateRuleHeader = "allowSave=false, includeRecordDetails=true, runAsCurrentUser=true"
+
+// DuplicateResult the records an active duplicate rule matched against a record being saved
+type DuplicateResult struct {
+	AllowSave               bool                   `json:"allowSave"`
+	DuplicateRule           string                 `json:"duplicateRule"`
+	DuplicateRuleEntityType string                 `json:"duplicateRuleEntityType"`
+	ErrorMessage            string                 `json:"errorMessage"`
+	MatchResults            []DuplicateMatchResult `json:"matchResults"`
+}
+
+// DuplicateMatchResult the records matched by one matching rule of a duplicate rule
+type DuplicateMatchResult struct {
+	EntityType   string                 `json:"entityType"`
+	MatchEngine  string                 `json:"matchEngine"`
+	Rule         string                 `json:"rule"`
+	Size         int                    `json:"size"`
+	Success      bool                   `json:"success"`
+	MatchRecords []DuplicateMatchRecord `json:"matchRecords"`
+}
+
+// DuplicateMatchRecord a matched record, with the fields of the matching rule
+type DuplicateMatchRecord struct {
+	MatchConfidence float64        `json:"matchConfidence"`
+	Record          map[string]any `json:"record"`
+}
+
+// Ids the ids of the matched records, in match order without repeats
+func (r DuplicateResult) Ids() []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, result := range r.MatchResults {
+		for _, match := range result.MatchRecords {
+			id, _ := match.Record["Id"].(string)
+			if len(id) > 0 && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// duplicateError an error of a save blocked by a duplicate rule
+type duplicateError struct {
+	ErrorCode       string           `json:"errorCode"`
+	DuplicateResult *DuplicateResult `json:"duplicateResult"`
+}
+
+// CreateUnlessDuplicate creates a record unless an active duplicate rule of the object matches it to existing records
+// - salesforce has no read only duplicates endpoint for REST, the matching rules run as the record is saved, so the
+// record is sent with a duplicate rule header which blocks its save when any match
+// - returns the id of the created record, or, when duplicates were found, the DuplicateResult with nothing created
+func CreateUnlessDuplicate(ctx context.Context, h *RequestHelper, name string, record any) (string, *DuplicateResult, error) {
+	reqUrl, err := h.dataUrl(ctx, "/sobjects/"+url.PathEscape(name))
+	if err != nil {
+		return "", nil, err
+	}
+	reqBody, err := h.marshal(record)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	req, err := h.newRequest(ctx, http.MethodPost, reqUrl, bytes.NewReader(reqBody))
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Sforce-Duplicate-Rule-Header", duplicateRuleHeader)
+
+	resp, err := h.do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to parse response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusBadRequest {
+		var errs []duplicateError
+		if json.Unmarshal(resBody, &errs) == nil {
+			for _, e := range errs {
+				if e.ErrorCode == "DUPLICATES_DETECTED" && e.DuplicateResult != nil {
+					return "", e.DuplicateResult, nil
+				}
+			}
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", nil, fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+
+	var parsedResp *PostResponse
+	if err = h.unmarshal(resBody, &parsedResp); err != nil {
+		return "", nil, fmt.Errorf("unable to parse response body: %w", err)
+	}
+	if !parsedResp.Success {
+		return "", nil, fmt.Errorf("salesforce returns a failure result: %s", resBody)
+	}
+	return parsedResp.Id, nil, nil
+}
@@ -0,0 +1,64 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+)
+
+func TestCreateUnlessDuplicate(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		want     string
+		wantDups []string
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "no duplicates  created id returned",
+			resp:    newResponse(http.StatusCreated, `{"id":"001xx0000000003","success":true,"errors":[]}`),
+			want:    "001xx0000000003",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "duplicates detected  matches returned",
+			resp: newResponse(http.StatusBadRequest, `[{"duplicateResult":{"allowSave":false,"duplicateRule":"Standard_Account_Duplicate_Rule",
+				"duplicateRuleEntityType":"Account","errorMessage":"Use one of these records?","matchResults":[{"entityType":"Account",
+				"errors":[],"matchEngine":"FuzzyMatchEngine","matchRecords":[
+				{"additionalInformation":[],"fieldDiffs":[],"matchConfidence":100.0,"record":{"attributes":{"type":"Account"},"Id":"001xx0000000001","Name":"Acme"}},
+				{"additionalInformation":[],"fieldDiffs":[],"matchConfidence":87.5,"record":{"attributes":{"type":"Account"},"Id":"001xx0000000002","Name":"Acme Ltd"}}],
+				"rule":"Standard_Account_Match_Rule_v1_0","size":2,"success":true}]},
+				"errorCode":"DUPLICATES_DETECTED","message":"Use one of these records?","fields":[]}]`),
+			wantDups: []string{"001xx0000000001", "001xx0000000002"},
+			wantErr:  assert.NoError,
+		},
+		{
+			name:    "other bad request  error returned",
+			resp:    newResponse(http.StatusBadRequest, `[{"errorCode":"REQUIRED_FIELD_MISSING","message":"Name","fields":["Name"]}]`),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/sobjects/Account", req.URL.Path)
+				assert.Equal(t, duplicateRuleHeader, req.Header.Get("Sforce-Duplicate-Rule-Header"))
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, dups, err := CreateUnlessDuplicate(context.Background(), h, "Account", map[string]string{"Name": "Acme"})
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+			if tt.wantDups == nil {
+				assert.Nil(t, dups)
+			} else if assert.NotNil(t, dups) {
+				assert.Equal(t, tt.wantDups, dups.Ids())
+				assert.Equal(t, "Standard_Account_Duplicate_Rule", dups.DuplicateRule)
+			}
+		})
+	}
+}
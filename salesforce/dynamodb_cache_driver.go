@@ -0,0 +1,198 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/gob"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
+	"time"
+)
+
+// DynamoDBClient the subset of *dynamodb.Client used by DynamoDBCache
+type DynamoDBClient interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+const (
+	dynamoPartitionKey = "pk"
+	dynamoSortKey      = "sk"
+	dynamoValue        = "v"
+	dynamoTtl          = "ttl"
+)
+
+// DynamoDBCache an implementation of driver.Cache storing gob encoded values in a DynamoDB table, so a cache can be
+// shared across instances e.g. Lambdas. The table needs a string partition key "pk" and a string sort key "sk",
+// each cache uses its name as the partition key so one table can hold many caches
+type DynamoDBCache[K comparable, V any] struct {
+	client DynamoDBClient
+	table  string
+	name   string
+	ttl    time.Duration
+}
+
+func NewDynamoDBCacheDriver[K comparable, V any](client DynamoDBClient, table, name string) *DynamoDBCache[K, V] {
+	return &DynamoDBCache[K, V]{
+		client: client,
+		table:  table,
+		name:   name,
+	}
+}
+
+// SetTtl writes the item expiry, as epoch seconds, to the "ttl" attribute so DynamoDB TTL can remove stale items
+func (d *DynamoDBCache[K, V]) SetTtl(ttl time.Duration) *DynamoDBCache[K, V] {
+	d.ttl = ttl
+	return d
+}
+
+func (d *DynamoDBCache[K, V]) itemKey(key K) (map[string]types.AttributeValue, bool) {
+	var k bytes.Buffer
+	if err := gob.NewEncoder(&k).Encode(key); err != nil {
+		return nil, false
+	}
+	return map[string]types.AttributeValue{
+		dynamoPartitionKey: &types.AttributeValueMemberS{Value: d.name},
+		dynamoSortKey:      &types.AttributeValueMemberS{Value: base64.StdEncoding.EncodeToString(k.Bytes())},
+	}, true
+}
+
+func (d *DynamoDBCache[K, V]) Has(ctx context.Context, key K) bool {
+	_, ok := d.Get(ctx, key)
+	return ok
+}
+
+func (d *DynamoDBCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
+	itemKey, ok := d.itemKey(key)
+	if !ok {
+		return *new(V), false
+	}
+	out, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(d.table),
+		Key:            itemKey,
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil || out.Item == nil || dynamoItemExpired(out.Item) {
+		return *new(V), false
+	}
+	return decodeDynamoValue[V](out.Item)
+}
+
+func (d *DynamoDBCache[K, V]) All(ctx context.Context) map[K]V {
+	m := map[K]V{}
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := d.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(d.table),
+			KeyConditionExpression: aws.String("#pk = :pk"),
+			ExpressionAttributeNames: map[string]string{
+				"#pk": dynamoPartitionKey,
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk": &types.AttributeValueMemberS{Value: d.name},
+			},
+			ConsistentRead:    aws.Bool(true),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return m
+		}
+		for _, item := range out.Items {
+			if dynamoItemExpired(item) {
+				continue
+			}
+			k, ok := decodeDynamoKey[K](item)
+			if !ok {
+				continue
+			}
+			v, ok := decodeDynamoValue[V](item)
+			if !ok {
+				continue
+			}
+			m[k] = v
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return m
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
+
+func (d *DynamoDBCache[K, V]) Set(ctx context.Context, key K, value V) bool {
+	item, ok := d.itemKey(key)
+	if !ok {
+		return false
+	}
+	var v bytes.Buffer
+	if err := gob.NewEncoder(&v).Encode(value); err != nil {
+		return false
+	}
+	item[dynamoValue] = &types.AttributeValueMemberB{Value: v.Bytes()}
+	if d.ttl > 0 {
+		item[dynamoTtl] = &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Add(d.ttl).Unix(), 10)}
+	}
+	_, err := d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(d.table),
+		Item:      item,
+	})
+	return err == nil
+}
+
+func (d *DynamoDBCache[K, V]) Delete(ctx context.Context, key K) bool {
+	itemKey, ok := d.itemKey(key)
+	if !ok {
+		return false
+	}
+	_, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(d.table),
+		Key:       itemKey,
+	})
+	return err == nil
+}
+
+func (d *DynamoDBCache[K, V]) Clear(ctx context.Context) bool {
+	ok := true
+	for k := range d.All(ctx) {
+		ok = d.Delete(ctx, k) && ok
+	}
+	return ok
+}
+
+// dynamoItemExpired DynamoDB TTL deletes lazily, so items past their ttl can still be read
+func dynamoItemExpired(item map[string]types.AttributeValue) bool {
+	ttl, ok := item[dynamoTtl].(*types.AttributeValueMemberN)
+	if !ok {
+		return false
+	}
+	expiry, err := strconv.ParseInt(ttl.Value, 10, 64)
+	return err == nil && time.Now().Unix() > expiry
+}
+
+func decodeDynamoKey[K any](item map[string]types.AttributeValue) (K, bool) {
+	sk, ok := item[dynamoSortKey].(*types.AttributeValueMemberS)
+	if !ok {
+		return *new(K), false
+	}
+	b, err := base64.StdEncoding.DecodeString(sk.Value)
+	if err != nil {
+		return *new(K), false
+	}
+	var k K
+	err = gob.NewDecoder(bytes.NewReader(b)).Decode(&k)
+	return k, err == nil
+}
+
+func decodeDynamoValue[V any](item map[string]types.AttributeValue) (V, bool) {
+	b, ok := item[dynamoValue].(*types.AttributeValueMemberB)
+	if !ok {
+		return *new(V), false
+	}
+	var v V
+	err := gob.NewDecoder(bytes.NewReader(b.Value)).Decode(&v)
+	return v, err == nil
+}
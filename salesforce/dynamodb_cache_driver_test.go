@@ -0,0 +1,90 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// dynamoDBClientStub an in memory table keyed by pk and sk
+type dynamoDBClientStub struct {
+	items map[string]map[string]map[string]types.AttributeValue
+}
+
+func newDynamoDBClientStub() *dynamoDBClientStub {
+	return &dynamoDBClientStub{items: map[string]map[string]map[string]types.AttributeValue{}}
+}
+
+func stubKeys(key map[string]types.AttributeValue) (string, string) {
+	return key[dynamoPartitionKey].(*types.AttributeValueMemberS).Value, key[dynamoSortKey].(*types.AttributeValueMemberS).Value
+}
+
+func (s *dynamoDBClientStub) GetItem(_ context.Context, params *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	pk, sk := stubKeys(params.Key)
+	return &dynamodb.GetItemOutput{Item: s.items[pk][sk]}, nil
+}
+
+func (s *dynamoDBClientStub) PutItem(_ context.Context, params *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	pk, sk := stubKeys(params.Item)
+	if s.items[pk] == nil {
+		s.items[pk] = map[string]map[string]types.AttributeValue{}
+	}
+	s.items[pk][sk] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (s *dynamoDBClientStub) DeleteItem(_ context.Context, params *dynamodb.DeleteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	pk, sk := stubKeys(params.Key)
+	delete(s.items[pk], sk)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (s *dynamoDBClientStub) Query(_ context.Context, params *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	pk := params.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS).Value
+	out := &dynamodb.QueryOutput{}
+	for _, item := range s.items[pk] {
+		out.Items = append(out.Items, item)
+	}
+	return out, nil
+}
+
+func TestDynamoDBCache(t *testing.T) {
+	ctx := context.Background()
+	client := newDynamoDBClientStub()
+	d := NewDynamoDBCacheDriver[int, Token](client, "cache-table", "salesforce-token").SetTtl(time.Hour)
+	other := NewDynamoDBCacheDriver[int, Token](client, "cache-table", "other")
+
+	assert.False(t, d.Has(ctx, 0))
+
+	assert.True(t, d.Set(ctx, 0, Token{AccessToken: "token", InstanceUrl: "https://org"}))
+	assert.True(t, other.Set(ctx, 0, Token{AccessToken: "other"}))
+
+	got, ok := d.Get(ctx, 0)
+	assert.True(t, ok)
+	assert.Equal(t, Token{AccessToken: "token", InstanceUrl: "https://org"}, got)
+	assert.Equal(t, map[int]Token{0: {AccessToken: "token", InstanceUrl: "https://org"}}, d.All(ctx))
+
+	assert.True(t, d.Clear(ctx))
+	assert.False(t, d.Has(ctx, 0))
+	assert.True(t, other.Has(ctx, 0))
+}
+
+func TestDynamoDBCache_ExpiredItem(t *testing.T) {
+	ctx := context.Background()
+	client := newDynamoDBClientStub()
+	d := NewDynamoDBCacheDriver[int, string](client, "cache-table", "expired").SetTtl(time.Hour)
+	d.Set(ctx, 1, "value")
+
+	// DynamoDB TTL deletion lags, simulate an item past its ttl that has not yet been removed
+	for _, item := range client.items["expired"] {
+		item[dynamoTtl] = &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)}
+	}
+
+	_, ok := d.Get(ctx, 1)
+	assert.False(t, ok)
+	assert.Empty(t, d.All(ctx))
+}
@@ -0,0 +1,61 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamoDBReplayStore a ReplayStore saving replay ids in a DynamoDB table, so subscribers running on Lambda or ECS
+// resume from their last checkpoint after a restart. It shares the DynamoDBCache table layout, a string partition key
+// "pk" set to the store name and a string sort key "sk" set to the subscription, so both can use one table
+type DynamoDBReplayStore struct {
+	client DynamoDBClient
+	table  string
+	name   string
+}
+
+func NewDynamoDBReplayStore(client DynamoDBClient, table, name string) *DynamoDBReplayStore {
+	return &DynamoDBReplayStore{
+		client: client,
+		table:  table,
+		name:   name,
+	}
+}
+
+func (s *DynamoDBReplayStore) itemKey(subscription string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		dynamoPartitionKey: &types.AttributeValueMemberS{Value: s.name},
+		dynamoSortKey:      &types.AttributeValueMemberS{Value: subscription},
+	}
+}
+
+func (s *DynamoDBReplayStore) Load(ctx context.Context, subscription string) ([]byte, error) {
+	out, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.table),
+		Key:            s.itemKey(subscription),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to load replay id: %w", err)
+	}
+	v, ok := out.Item[dynamoValue].(*types.AttributeValueMemberB)
+	if !ok {
+		return nil, nil
+	}
+	return v.Value, nil
+}
+
+func (s *DynamoDBReplayStore) Save(ctx context.Context, subscription string, replayId []byte) error {
+	item := s.itemKey(subscription)
+	item[dynamoValue] = &types.AttributeValueMemberB{Value: replayId}
+	if _, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.table),
+		Item:      item,
+	}); err != nil {
+		return fmt.Errorf("unable to save replay id: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type failingDynamoDBClientStub struct {
+	*dynamoDBClientStub
+}
+
+func (s failingDynamoDBClientStub) GetItem(context.Context, *dynamodb.GetItemInput, ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return nil, errors.New("throttled")
+}
+
+func (s failingDynamoDBClientStub) PutItem(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return nil, errors.New("throttled")
+}
+
+func TestDynamoDBReplayStore(t *testing.T) {
+	ctx := context.Background()
+	client := newDynamoDBClientStub()
+	s := NewDynamoDBReplayStore(client, "cache-table", "replay")
+	other := NewDynamoDBReplayStore(client, "cache-table", "other")
+
+	got, err := s.Load(ctx, "/event/Order__e")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	assert.NoError(t, s.Save(ctx, "/event/Order__e", []byte{1, 2}))
+	assert.NoError(t, s.Save(ctx, "/event/Order__e", []byte{1, 3}))
+	assert.NoError(t, other.Save(ctx, "/event/Order__e", []byte{9}))
+
+	got, err = s.Load(ctx, "/event/Order__e")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 3}, got)
+}
+
+func TestDynamoDBReplayStore_Error(t *testing.T) {
+	ctx := context.Background()
+	s := NewDynamoDBReplayStore(failingDynamoDBClientStub{newDynamoDBClientStub()}, "cache-table", "replay")
+
+	_, err := s.Load(ctx, "/event/Order__e")
+	assert.EqualError(t, err, "unable to load replay id: throttled")
+	assert.EqualError(t, s.Save(ctx, "/event/Order__e", []byte{1}), "unable to save replay id: throttled")
+}
@@ -0,0 +1,69 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+)
+
+// EventLogFile an Event Monitoring log file, the events of one type for a day or, when Interval is Hourly, an hour
+type EventLogFile struct {
+	Id        string `json:"Id"`
+	EventType string `json:"EventType"`
+	// LogDate the start of the period logged, e.g. 2024-01-31T00:00:00.000+0000
+	LogDate       string  `json:"LogDate"`
+	LogFileLength float64 `json:"LogFileLength"`
+	// Interval Daily or Hourly
+	Interval string `json:"Interval"`
+}
+
+// ListEventLogFiles lists the log files of an event type, e.g. Login or API, logged since the given time, oldest
+// first
+func ListEventLogFiles(ctx context.Context, h *RequestHelper, eventType string, since time.Time) ([]EventLogFile, error) {
+	return queryAll[EventLogFile](ctx, h, "SELECT Id, EventType, LogDate, LogFileLength, Interval FROM EventLogFile "+
+		"WHERE EventType = "+QuoteString(eventType)+" AND LogDate >= "+DateTimeLiteral(since)+" ORDER BY LogDate")
+}
+
+// DownloadEventLogFile streams the csv of a log file to w, returning the number of bytes written
+func DownloadEventLogFile(ctx context.Context, h *RequestHelper, id string, w io.Writer) (int64, error) {
+	return DownloadBlob(ctx, h, "EventLogFile", id, "LogFile", w)
+}
+
+// EachEventLogRow calls fn with each row of a log file, by column name e.g. USER_ID, as the csv is downloaded
+// - iteration stops at the first error returned by fn, which is returned
+func EachEventLogRow(ctx context.Context, h *RequestHelper, id string, fn func(row map[string]string) error) error {
+	body, err := openBlob(ctx, h, "EventLogFile", id, "LogFile")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	r := csv.NewReader(body)
+	r.ReuseRecord = true
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to parse event log file %s: %w", id, err)
+	}
+	header = append([]string(nil), header...)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to parse event log file %s: %w", id, err)
+		}
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			row[name] = record[i]
+		}
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+}
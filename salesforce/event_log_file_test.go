@@ -0,0 +1,85 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestListEventLogFiles(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "SELECT Id, EventType, LogDate, LogFileLength, Interval FROM EventLogFile "+
+			"WHERE EventType = 'Login' AND LogDate >= 2024-01-31T00:00:00Z ORDER BY LogDate", req.URL.Query().Get("q"))
+		return newResponse(http.StatusOK, `{"totalSize":1,"done":true,"records":[{"Id":"0ATxx0000000001","EventType":"Login",
+			"LogDate":"2024-01-31T00:00:00.000+0000","LogFileLength":1024,"Interval":"Daily"}]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := ListEventLogFiles(context.Background(), h, "Login", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []EventLogFile{{Id: "0ATxx0000000001", EventType: "Login", LogDate: "2024-01-31T00:00:00.000+0000",
+		LogFileLength: 1024, Interval: "Daily"}}, got)
+}
+
+func TestEachEventLogRow(t *testing.T) {
+	errStop := errors.New("stop")
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		fnErr   error
+		want    []map[string]string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "csv  each row by column name",
+			resp: newResponse(http.StatusOK, "\"EVENT_TYPE\",\"USER_ID\"\n\"Login\",\"005xx0000000001\"\n\"Login\",\"005xx0000000002\"\n"),
+			want: []map[string]string{
+				{"EVENT_TYPE": "Login", "USER_ID": "005xx0000000001"},
+				{"EVENT_TYPE": "Login", "USER_ID": "005xx0000000002"},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "empty file  no rows",
+			resp:    newResponse(http.StatusOK, ""),
+			wantErr: assert.NoError,
+		},
+		{
+			name:  "fn errors  iteration stopped",
+			resp:  newResponse(http.StatusOK, "\"EVENT_TYPE\",\"USER_ID\"\n\"Login\",\"005xx0000000001\"\n\"Login\",\"005xx0000000002\"\n"),
+			fnErr: errStop,
+			want:  []map[string]string{{"EVENT_TYPE": "Login", "USER_ID": "005xx0000000001"}},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, errStop, i...)
+			},
+		},
+		{
+			name:    "not found  error returned",
+			resp:    newResponse(http.StatusNotFound, ""),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/sobjects/EventLogFile/0ATxx0000000001/LogFile", req.URL.Path)
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			var got []map[string]string
+			err := EachEventLogRow(context.Background(), h, "0ATxx0000000001", func(row map[string]string) error {
+				got = append(got, row)
+				return tt.fnErr
+			})
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
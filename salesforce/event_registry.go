@@ -0,0 +1,63 @@
+package salesforce
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// EventRegistry maps event channels to the Go types of their events, so subscribers can deliver typed events rather
+// than maps, see pubsub.Typed and TypedStreamingHandler
+type EventRegistry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{types: map[string]reflect.Type{}}
+}
+
+// RegisterEvent registers E as the type of the events of channel, e.g. /event/Order_Shipped__e or
+// /data/AccountChangeEvent. Change events received on a multi-object channel, e.g. /data/ChangeEvents, are matched
+// by their object's channel, see ChangeEventChannel
+func RegisterEvent[E any](r *EventRegistry, channel string) *EventRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[channel] = reflect.TypeOf((*E)(nil)).Elem()
+	return r
+}
+
+// New returns a pointer to a new value of the type registered for channel, e.g. *OrderShipped, to decode an event into
+func (r *EventRegistry) New(channel string) (any, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[channel]
+	if !ok {
+		return nil, false
+	}
+	return reflect.New(t).Interface(), true
+}
+
+// NewForEvent returns New for channel, or when no type is registered for channel and the event is a change event of
+// entityName, New for the change event channel of entityName
+func (r *EventRegistry) NewForEvent(channel, entityName string) (any, error) {
+	if v, ok := r.New(channel); ok {
+		return v, nil
+	}
+	if len(entityName) > 0 {
+		if v, ok := r.New(ChangeEventChannel(entityName)); ok {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("no event type registered for %s", channel)
+}
+
+// ChangeEventChannel the channel of the change events of object, e.g. /data/AccountChangeEvent or
+// /data/Order__ChangeEvent for Order__c
+func ChangeEventChannel(object string) string {
+	if name, ok := strings.CutSuffix(object, "__c"); ok {
+		return "/data/" + name + "__ChangeEvent"
+	}
+	return "/data/" + object + "ChangeEvent"
+}
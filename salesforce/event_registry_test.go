@@ -0,0 +1,60 @@
+package salesforce
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type orderShippedEventStub struct {
+	OrderNumber string `json:"Order_Number__c"`
+}
+
+type accountChangeEventStub struct {
+	Name string `json:"Name"`
+}
+
+func TestEventRegistry_NewForEvent(t *testing.T) {
+	r := NewEventRegistry()
+	RegisterEvent[orderShippedEventStub](r, "/event/Order_Shipped__e")
+	RegisterEvent[accountChangeEventStub](r, "/data/AccountChangeEvent")
+
+	tests := []struct {
+		name       string
+		channel    string
+		entityName string
+		want       any
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "registered channel  new value",
+			channel: "/event/Order_Shipped__e",
+			want:    &orderShippedEventStub{},
+			wantErr: assert.NoError,
+		},
+		{
+			name:       "change event on multi-object channel  new value of object channel",
+			channel:    "/data/ChangeEvents",
+			entityName: "Account",
+			want:       &accountChangeEventStub{},
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "not registered  error",
+			channel:    "/data/ChangeEvents",
+			entityName: "Contact",
+			wantErr:    assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.NewForEvent(tt.channel, tt.entityName)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestChangeEventChannel(t *testing.T) {
+	assert.Equal(t, "/data/AccountChangeEvent", ChangeEventChannel("Account"))
+	assert.Equal(t, "/data/Order__ChangeEvent", ChangeEventChannel("Order__c"))
+}
@@ -0,0 +1,255 @@
+package salesforce
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExportFormat the format QueryToWriter writes records in
+type ExportFormat string
+
+const (
+	// ExportCsv a header row of the selected fields, then a row per record. Relationship fields are flattened, e.g.
+	// Owner.Name, and subqueries written as json
+	ExportCsv ExportFormat = "csv"
+	// ExportNdjson a json object per line, without the attributes of each record
+	ExportNdjson ExportFormat = "ndjson"
+)
+
+// QueryToWriter runs a query and streams every page of records to w in format, without holding more than one record in
+// memory, e.g. to extract an object to S3
+func QueryToWriter(ctx context.Context, h *RequestHelper, q string, w io.Writer, format ExportFormat) error {
+	switch format {
+	case ExportCsv:
+		return queryToCsv(ctx, h, q, w)
+	case ExportNdjson:
+		return queryToNdjson(ctx, h, q, w)
+	}
+	return fmt.Errorf("unsupported export format: %s", format)
+}
+
+func queryToNdjson(ctx context.Context, h *RequestHelper, q string, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	err := QueryEach(ctx, h, q, func(raw json.RawMessage) error {
+		record, err := decodeExportRecord(raw)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(withoutAttributes(record))
+		if err != nil {
+			return err
+		}
+		if _, err = bw.Write(append(b, '\n')); err != nil {
+			return fmt.Errorf("unable to write record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func queryToCsv(ctx context.Context, h *RequestHelper, q string, w io.Writer) error {
+	columns, err := selectedColumns(q)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.name
+	}
+	if err = cw.Write(header); err != nil {
+		return fmt.Errorf("unable to write header: %w", err)
+	}
+
+	row := make([]string, len(columns))
+	err = QueryEach(ctx, h, q, func(raw json.RawMessage) error {
+		record, err := decodeExportRecord(raw)
+		if err != nil {
+			return err
+		}
+		for i, c := range columns {
+			value, err := csvValue(lookupPath(record, c.path))
+			if err != nil {
+				return err
+			}
+			row[i] = value
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("unable to write record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// decodeExportRecord decodes a record keeping numbers as they were sent, rather than as float64
+func decodeExportRecord(raw json.RawMessage) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var record map[string]any
+	if err := dec.Decode(&record); err != nil {
+		return nil, fmt.Errorf("unable to parse response body: %w", err)
+	}
+	return record, nil
+}
+
+// exportColumn a selected field, path is the key of its value within a record
+type exportColumn struct {
+	name string
+	path []string
+}
+
+// selectedColumns the columns of a query's SELECT clause, in order. Fields are found case-insensitively in records,
+// subqueries by their relationship name and aggregates by their alias, or exprN as salesforce names them
+func selectedColumns(q string) ([]exportColumn, error) {
+	items, err := selectItems(q)
+	if err != nil {
+		return nil, err
+	}
+	var columns []exportColumn
+	expr := 0
+	for _, item := range items {
+		words := strings.Fields(item)
+		switch {
+		case strings.EqualFold(words[0], "TYPEOF"):
+			return nil, fmt.Errorf("TYPEOF is not supported by csv exports, use ndjson")
+		case strings.HasPrefix(item, "("):
+			// a subquery, named by its FROM relationship
+			_, from := cutTopLevel(strings.Trim(item, "()"), "from")
+			name := strings.Fields(from)[0]
+			columns = append(columns, exportColumn{name: name, path: []string{name}})
+		case strings.Contains(item, "("):
+			// an aggregate or function, named by its alias when it has one
+			name := fmt.Sprintf("expr%d", expr)
+			if last := words[len(words)-1]; !strings.HasSuffix(last, ")") {
+				name = last
+			} else {
+				expr++
+			}
+			columns = append(columns, exportColumn{name: name, path: []string{name}})
+		default:
+			columns = append(columns, exportColumn{name: words[0], path: strings.Split(words[0], ".")})
+		}
+	}
+	return columns, nil
+}
+
+// selectItems the comma separated items between SELECT and FROM
+func selectItems(q string) ([]string, error) {
+	q = strings.TrimSpace(q)
+	if len(q) < 7 || !strings.EqualFold(q[:7], "select ") {
+		return nil, fmt.Errorf("query must start with SELECT: %s", q)
+	}
+	selected, from := cutTopLevel(q[7:], "from")
+	if len(from) == 0 {
+		return nil, fmt.Errorf("query has no FROM: %s", q)
+	}
+
+	var items []string
+	depth, start := 0, 0
+	for i, c := range selected {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(selected[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	items = append(items, strings.TrimSpace(selected[start:]))
+	for _, item := range items {
+		if len(item) == 0 {
+			return nil, fmt.Errorf("query has an empty field: %s", q)
+		}
+	}
+	return items, nil
+}
+
+// cutTopLevel cuts s around the first keyword outside parentheses, matched case-insensitively as a whole word
+func cutTopLevel(s, keyword string) (before, after string) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ' ', '\t', '\n', '\r':
+			end := i + 1 + len(keyword)
+			if depth == 0 && end < len(s) && strings.EqualFold(s[i+1:end], keyword) && strings.ContainsRune(" \t\n\r", rune(s[end])) {
+				return s[:i], s[end:]
+			}
+		}
+	}
+	return s, ""
+}
+
+// lookupPath the value at path within record, matching keys case-insensitively as SOQL does
+func lookupPath(record map[string]any, path []string) any {
+	var value any = record
+	for _, key := range path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = nil
+		for k, v := range m {
+			if strings.EqualFold(k, key) {
+				value = v
+				break
+			}
+		}
+	}
+	return value
+}
+
+// csvValue formats a json value for a csv cell, null is empty and objects and arrays are json
+func csvValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case map[string]any, []any:
+		b, err := json.Marshal(withoutAttributes(v))
+		return string(b), err
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// withoutAttributes removes the attributes of v and any records nested within it
+func withoutAttributes(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		delete(v, "attributes")
+		for k, child := range v {
+			v[k] = withoutAttributes(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = withoutAttributes(child)
+		}
+	}
+	return v
+}
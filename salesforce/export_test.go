@@ -0,0 +1,78 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestQueryToWriter(t *testing.T) {
+	body := `{"totalSize":2,"done":true,"records":[
+		{"attributes":{"type":"Account"},"Id":"001A","Name":"Acme, Inc","NumberOfEmployees":1000000,"IsActive__c":true,"Owner":{"attributes":{"type":"User"},"Name":"Alice"},"Contacts":{"totalSize":1,"done":true,"records":[{"attributes":{"type":"Contact"},"Email":"a@acme.com"}]}},
+		{"attributes":{"type":"Account"},"Id":"001B","Name":"Globex","NumberOfEmployees":null,"IsActive__c":false,"Owner":null,"Contacts":null}
+	]}`
+
+	tests := []struct {
+		name    string
+		query   string
+		format  ExportFormat
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "csv  header and rows written",
+			query:  "SELECT Id, name, NumberOfEmployees, IsActive__c, Owner.Name, (SELECT Email FROM Contacts) FROM Account",
+			format: ExportCsv,
+			want: "Id,name,NumberOfEmployees,IsActive__c,Owner.Name,Contacts\n" +
+				`001A,"Acme, Inc",1000000,true,Alice,"{""done"":true,""records"":[{""Email"":""a@acme.com""}],""totalSize"":1}"` + "\n" +
+				"001B,Globex,,false,,\n",
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "ndjson  record per line written",
+			query:  "SELECT Id, Name FROM Account",
+			format: ExportNdjson,
+			want: `{"Contacts":{"done":true,"records":[{"Email":"a@acme.com"}],"totalSize":1},"Id":"001A","IsActive__c":true,"Name":"Acme, Inc","NumberOfEmployees":1000000,"Owner":{"Name":"Alice"}}` + "\n" +
+				`{"Contacts":null,"Id":"001B","IsActive__c":false,"Name":"Globex","NumberOfEmployees":null,"Owner":null}` + "\n",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "csv with TYPEOF  error returned",
+			query:   "SELECT TYPEOF What WHEN Account THEN Name END FROM Task",
+			format:  ExportCsv,
+			wantErr: assert.Error,
+		},
+		{
+			name:    "unknown format  error returned",
+			query:   "SELECT Id FROM Account",
+			format:  "xml",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewRequestHelper(newHttpClientMock(newResponse(200, body), nil), newTokenGetterMock("token", nil), "https://org", 55)
+
+			var w bytes.Buffer
+			err := QueryToWriter(context.Background(), h, tt.query, &w, tt.format)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, w.String())
+		})
+	}
+}
+
+func TestSelectedColumns(t *testing.T) {
+	columns, err := selectedColumns("SELECT Industry, COUNT(Id) total, MAX(AnnualRevenue) FROM Account GROUP BY Industry")
+	assert.NoError(t, err)
+	assert.Equal(t, []exportColumn{
+		{name: "Industry", path: []string{"Industry"}},
+		{name: "total", path: []string{"total"}},
+		{name: "expr0", path: []string{"expr0"}},
+	}, columns)
+
+	_, err = selectedColumns("DELETE FROM Account")
+	assert.Error(t, err)
+}
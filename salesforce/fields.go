@@ -0,0 +1,122 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldsCache the field lists by type, as reflecting over a struct on every query is wasteful
+var fieldsCache sync.Map
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	polymorphicType     = reflect.TypeOf(Polymorphic{})
+)
+
+// subquery implemented by SubqueryResult so Fields can select child relationships
+type subquery interface {
+	subqueryRecordType() reflect.Type
+}
+
+func (s SubqueryResult[E]) subqueryRecordType() reflect.Type {
+	return reflect.TypeOf((*E)(nil)).Elem()
+}
+
+var subqueryType = reflect.TypeOf((*subquery)(nil)).Elem()
+
+// Fields returns the SOQL field list of T from its json tags, so a struct and its query can't drift apart
+//   - fields tagged "-" and attributes are skipped, untagged fields use the field name as encoding/json does
+//   - nested structs are relationships, e.g. Account.Name, unless they decode themselves such as Date or Nullable
+//   - SubqueryResult fields are child relationship subqueries, e.g. (SELECT Email FROM Contacts)
+//   - Polymorphic fields select the related record's Id and Type, use TYPEOF in a handwritten query for more
+//   - relationships back to a type already being selected, e.g. Account.Parent, are skipped
+func Fields[T any]() []string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if cached, ok := fieldsCache.Load(t); ok {
+		return append([]string{}, cached.([]string)...)
+	}
+	fields := structFields(t, "", map[reflect.Type]bool{})
+	fieldsCache.Store(t, fields)
+	return append([]string{}, fields...)
+}
+
+// SelectFor returns a SELECT of T's Fields from object, to which a WHERE clause etc. can be appended
+func SelectFor[T any](object string) string {
+	return "SELECT " + strings.Join(Fields[T](), ", ") + " FROM " + object
+}
+
+// structFields the fields of struct t prefixed by its relationship path, seen guards against recursive types
+func structFields(t reflect.Type, prefix string, seen map[reflect.Type]bool) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		switch {
+		case f.Anonymous && len(name) == 0 && ft.Kind() == reflect.Struct:
+			fields = append(fields, structFields(ft, prefix, seen)...)
+		case !f.IsExported():
+			continue
+		case len(name) == 0:
+			name = f.Name
+			fallthrough
+		default:
+			fields = append(fields, fieldSelection(ft, prefix, name, seen)...)
+		}
+	}
+	return fields
+}
+
+// fieldSelection the selection of a single named field of type t
+func fieldSelection(t reflect.Type, prefix, name string, seen map[reflect.Type]bool) []string {
+	if name == "attributes" {
+		return nil
+	}
+	switch {
+	case t == polymorphicType:
+		return []string{prefix + name + ".Id", prefix + name + ".Type"}
+	case t.Implements(subqueryType):
+		child := reflect.Zero(t).Interface().(subquery).subqueryRecordType()
+		return []string{"(SELECT " + strings.Join(structFields(child, "", seen), ", ") + " FROM " + name + ")"}
+	case t.Kind() == reflect.Struct && !t.Implements(jsonUnmarshalerType) && !reflect.PointerTo(t).Implements(jsonUnmarshalerType):
+		return structFields(t, prefix+name+".", seen)
+	}
+	return []string{prefix + name}
+}
+
+// jsonFieldName the json name of f, empty when untagged, ok is false when the field is skipped with "-"
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, true
+}
+
+// sfTagOptions the comma separated options of f's sf tag, e.g. sf:"readonly" or sf:"type=Account"
+func sfTagOptions(f reflect.StructField) []string {
+	tag := f.Tag.Get("sf")
+	if len(tag) == 0 {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
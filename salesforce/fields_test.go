@@ -0,0 +1,77 @@
+package salesforce
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fieldsOwnerStub struct {
+	Name  string `json:"Name"`
+	Email string `json:"Email"`
+}
+
+type fieldsBaseStub struct {
+	Attributes Attributes `json:"attributes"`
+	Id         string     `json:"Id"`
+}
+
+type fieldsAccountStub struct {
+	fieldsBaseStub
+	Name        string                      `json:"Name"`
+	Description NullString                  `json:"Description,omitempty"`
+	Founded     Date                        `json:"Founded__c"`
+	Modified    time.Time                   `json:"LastModifiedDate"`
+	Owner       *fieldsOwnerStub            `json:"Owner"`
+	Contacts    SubqueryResult[contactStub] `json:"Contacts"`
+	Who         Polymorphic                 `json:"Who"`
+	Ignored     string                      `json:"-"`
+	Untagged    string
+	internal    string
+}
+
+type fieldsRecursiveStub struct {
+	Name   string               `json:"Name"`
+	Parent *fieldsRecursiveStub `json:"Parent"`
+}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name string
+		got  func() []string
+		want []string
+	}{
+		{
+			name: "struct with relationships  field list returned",
+			got:  Fields[fieldsAccountStub],
+			want: []string{
+				"Id",
+				"Name",
+				"Description",
+				"Founded__c",
+				"LastModifiedDate",
+				"Owner.Name",
+				"Owner.Email",
+				"(SELECT Email FROM Contacts)",
+				"Who.Id",
+				"Who.Type",
+				"Untagged",
+			},
+		},
+		{
+			name: "recursive struct  recursive relationship skipped",
+			got:  Fields[fieldsRecursiveStub],
+			want: []string{"Name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got())
+			assert.Equal(t, tt.want, tt.got(), "cached")
+		})
+	}
+}
+
+func TestSelectFor(t *testing.T) {
+	assert.Equal(t, "SELECT Name, Email FROM User", SelectFor[fieldsOwnerStub]("User"))
+}
@@ -0,0 +1,30 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoRows returned by FindOne when no record matches the query
+var ErrNoRows = errors.New("no salesforce records match the query")
+
+// ErrTooManyRows returned by FindOne when more than one record matches the query
+var ErrTooManyRows = errors.New("more than one salesforce record matches the query")
+
+// FindOne runs a SOQL query expected to match exactly one record, returning it decoded as E
+// - ErrNoRows is returned when nothing matches and ErrTooManyRows, wrapped with the number matched, when several do,
+// check them with errors.Is
+func FindOne[E any](ctx context.Context, h *RequestHelper, q string) (*E, error) {
+	resp, err := Query[E](ctx, h, q)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.TotalSize == 0 || len(resp.Records) == 0:
+		return nil, ErrNoRows
+	case resp.TotalSize > 1 || len(resp.Records) > 1:
+		return nil, fmt.Errorf("%w: %d records match", ErrTooManyRows, max(resp.TotalSize, len(resp.Records)))
+	}
+	return &resp.Records[0], nil
+}
@@ -0,0 +1,59 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestFindOne(t *testing.T) {
+	type account struct {
+		Id   string
+		Name string
+	}
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		want    *account
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "one record  returned",
+			resp:    newResponse(http.StatusOK, `{"totalSize":1,"done":true,"records":[{"Id":"001xx0000000001","Name":"Acme"}]}`),
+			want:    &account{Id: "001xx0000000001", Name: "Acme"},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "no records  ErrNoRows returned",
+			resp: newResponse(http.StatusOK, `{"totalSize":0,"done":true,"records":[]}`),
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrNoRows, i...)
+			},
+		},
+		{
+			name: "several records  ErrTooManyRows returned",
+			resp: newResponse(http.StatusOK, `{"totalSize":2,"done":true,"records":[{"Id":"001xx0000000001"},{"Id":"001xx0000000002"}]}`),
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrTooManyRows, i...) && assert.EqualError(t, err, ErrTooManyRows.Error()+": 2 records match")
+			},
+		},
+		{
+			name: "query error  error returned",
+			resp: newResponse(http.StatusBadRequest, `[{"errorCode":"MALFORMED_QUERY"}]`),
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.Error(t, err, i...) && assert.False(t, errors.Is(err, ErrNoRows), i...)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewRequestHelper(newHttpClientMock(tt.resp, nil), newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := FindOne[account](context.Background(), h, "SELECT Id, Name FROM Account WHERE Name = 'Acme'")
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
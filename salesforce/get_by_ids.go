@@ -0,0 +1,74 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"golang.org/x/sync/errgroup"
+	"slices"
+	"strings"
+)
+
+// getByIdsChunkSize the ids queried at once, keeping the url of each query well under salesforce's 16,384 character
+// limit
+const getByIdsChunkSize = 300
+
+// GetByIds fetches the records of object with the given ids, querying them in chunks so large id sets don't exceed
+// the query length limits. fields defaults to Fields[E] when empty, and the chunks are sent in parallel
+// WithConcurrency. Records are returned in chunk order, ids that aren't found are skipped
+func GetByIds[E any](ctx context.Context, h *RequestHelper, object string, fields []string, ids []string, opts ...RequestOption) ([]E, error) {
+	if len(fields) == 0 {
+		fields = Fields[E]()
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fields need to be provided")
+	}
+	o := newRequestOptions(opts)
+
+	ids = uniqueIds(ids)
+	chunks := make([][]E, (len(ids)+getByIdsChunkSize-1)/getByIdsChunkSize)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(o.concurrency, 1))
+	for i := range chunks {
+		chunk := ids[i*getByIdsChunkSize : min((i+1)*getByIdsChunkSize, len(ids))]
+		g.Go(func() error {
+			q := fmt.Sprintf("SELECT %s FROM %s WHERE Id IN %s", strings.Join(fields, ", "), object, InList(chunk...))
+			records, err := queryAll[E](gctx, h, q)
+			chunks[i] = records
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return slices.Concat(chunks...), nil
+}
+
+// uniqueIds ids without duplicates or empty ids, in order
+func uniqueIds(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if len(id) == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+// queryAll runs a query and returns the records of every page
+func queryAll[E any](ctx context.Context, h *RequestHelper, q string) ([]E, error) {
+	resp, err := Query[E](ctx, h, q)
+	if err != nil {
+		return nil, err
+	}
+	records := resp.Records
+	for !resp.Done && len(resp.NextRecordsUrl) > 0 {
+		if resp, err = QueryMore[E](ctx, h, resp.NextRecordsUrl); err != nil {
+			return nil, err
+		}
+		records = append(records, resp.Records...)
+	}
+	return records, nil
+}
@@ -0,0 +1,84 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+// idsResponder responds to id queries with a record per queried id, foo holding the id
+func idsResponder(req *http.Request) (*http.Response, error) {
+	ids := regexp.MustCompile(`'([^']+)'`).FindAllStringSubmatch(req.URL.Query().Get("q"), -1)
+	body := fmt.Sprintf(`{"totalSize":%d,"done":true,"records":[`, len(ids))
+	for i, id := range ids {
+		if i > 0 {
+			body += ","
+		}
+		body += fmt.Sprintf(`{"foo":%q}`, id[1])
+	}
+	return newResponse(200, body+"]}"), nil
+}
+
+func TestGetByIds(t *testing.T) {
+	ids := make([]string, 650)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("001xx%013d", i)
+	}
+
+	tests := []struct {
+		name      string
+		ids       []string
+		opts      []RequestOption
+		wantCalls int
+	}{
+		{
+			name:      "ids over chunk size  queried in chunks",
+			ids:       ids,
+			wantCalls: 3,
+		},
+		{
+			name:      "ids over chunk size in parallel  queried in chunks",
+			ids:       ids,
+			opts:      []RequestOption{WithConcurrency(3)},
+			wantCalls: 3,
+		},
+		{
+			name:      "duplicate ids  queried once",
+			ids:       []string{ids[0], ids[0], ""},
+			wantCalls: 1,
+		},
+		{
+			name:      "no ids  no query sent",
+			ids:       nil,
+			wantCalls: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(idsResponder)
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := GetByIds[recordStub](context.Background(), h, "Account", nil, tt.ids, tt.opts...)
+			assert.NoError(t, err)
+			want := uniqueIds(tt.ids)
+			if assert.Len(t, got, len(want)) {
+				for i, id := range want {
+					assert.Equal(t, id, got[i].Foo)
+				}
+			}
+			client.AssertNumberOfCalls(t, "Do", tt.wantCalls)
+		})
+	}
+}
+
+func TestGetByIds_QueryError(t *testing.T) {
+	h, _ := NewRequestHelper(newHttpClientMock(newResponse(400, `[]`), nil), newTokenGetterMock("token", nil), "https://org", 55)
+
+	_, err := GetByIds[recordStub](context.Background(), h, "Account", []string{"Foo"}, []string{"001xx0000000001"})
+	assert.ErrorAs(t, err, &QueryError{})
+}
@@ -0,0 +1,97 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// idSuffixChars the characters of an 18 character id's checksum suffix
+const idSuffixChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ012345"
+
+// ValidId whether id is a well formed 15 or 18 character salesforce id, including the 18 character checksum
+func ValidId(id string) bool {
+	if len(id) != 15 && len(id) != 18 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return len(id) == 15 || idSuffix(id[:15]) == id[15:]
+}
+
+// To18 converts a case-sensitive 15 character id to its case-insensitive 18 character form, 18 character ids are
+// returned as is
+func To18(id string) (string, error) {
+	if !ValidId(id) {
+		return "", fmt.Errorf("invalid salesforce id: %q", id)
+	}
+	if len(id) == 18 {
+		return id, nil
+	}
+	return id + idSuffix(id), nil
+}
+
+// KeyPrefix the first three characters of id, which identify its object, e.g. 001 for Account. Empty when id is
+// invalid
+func KeyPrefix(id string) string {
+	if !ValidId(id) {
+		return ""
+	}
+	return id[:3]
+}
+
+// EqualIds whether a and b are the same record, regardless of whether either is the 15 or 18 character form
+func EqualIds(a, b string) bool {
+	a18, err := To18(a)
+	if err != nil {
+		return false
+	}
+	b18, err := To18(b)
+	if err != nil {
+		return false
+	}
+	return a18 == b18
+}
+
+// idSuffix the checksum suffix of a 15 character id, one character per five, with a bit set for each uppercase letter
+func idSuffix(id15 string) string {
+	var suffix strings.Builder
+	for chunk := 0; chunk < 3; chunk++ {
+		bits := 0
+		for i := 0; i < 5; i++ {
+			if c := id15[chunk*5+i]; c >= 'A' && c <= 'Z' {
+				bits |= 1 << i
+			}
+		}
+		suffix.WriteByte(idSuffixChars[bits])
+	}
+	return suffix.String()
+}
+
+// Id a salesforce id which is normalised to 18 characters when decoded, so ids from sources mixing the 15 and 18
+// character forms can be compared with ==
+//
+//	type Contact struct {
+//		AccountId salesforce.Id `json:"AccountId"`
+//	}
+type Id string
+
+func (id *Id) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil || len(*s) == 0 {
+		*id = ""
+		return nil
+	}
+	id18, err := To18(*s)
+	if err != nil {
+		return err
+	}
+	*id = Id(id18)
+	return nil
+}
@@ -0,0 +1,54 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTo18(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "15 character id  converted", id: "001A0000006Vm9r", want: "001A0000006Vm9rIAC", wantErr: assert.NoError},
+		{name: "18 character id  unchanged", id: "001A0000006Vm9rIAC", want: "001A0000006Vm9rIAC", wantErr: assert.NoError},
+		{name: "18 character id with bad checksum  error returned", id: "001A0000006Vm9rAAA", wantErr: assert.Error},
+		{name: "wrong length  error returned", id: "001A0000006", wantErr: assert.Error},
+		{name: "invalid characters  error returned", id: "001A0000006Vm9-", wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := To18(tt.id)
+			if !tt.wantErr(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKeyPrefix(t *testing.T) {
+	assert.Equal(t, "001", KeyPrefix("001A0000006Vm9r"))
+	assert.Equal(t, "", KeyPrefix("001"))
+}
+
+func TestEqualIds(t *testing.T) {
+	assert.True(t, EqualIds("001A0000006Vm9r", "001A0000006Vm9rIAC"))
+	assert.False(t, EqualIds("001A0000006Vm9r", "001a0000006Vm9r"))
+	assert.False(t, EqualIds("", ""))
+}
+
+func TestId_UnmarshalJSON(t *testing.T) {
+	var got struct {
+		AccountId Id `json:"AccountId"`
+		OwnerId   Id `json:"OwnerId"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"AccountId":"001A0000006Vm9r","OwnerId":null}`), &got))
+	assert.Equal(t, Id("001A0000006Vm9rIAC"), got.AccountId)
+	assert.Equal(t, Id(""), got.OwnerId)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"AccountId":"not-an-id"}`), &got))
+}
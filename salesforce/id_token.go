@@ -0,0 +1,141 @@
+package salesforce
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+// jwksRefetchInterval the least time between JWKS fetches for an unknown kid, so tokens signed with a made up kid can't
+// make every verification fetch the keys
+const jwksRefetchInterval = time.Minute
+
+// IdTokenClaims the claims of a verified OpenID Connect id_token
+type IdTokenClaims struct {
+	jwt.RegisteredClaims
+	PreferredUsername string `json:"preferred_username"`
+	Email             string `json:"email"`
+	Name              string `json:"name"`
+	Nonce             string `json:"nonce"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode jwk modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode jwk exponent: %w", err)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
+
+// VerifyIdToken verifies an id_token's signature against the login host's JWKS, and that it was issued by the login
+// host to the connected app and hasn't expired, returning its claims
+func (tf *TokenFetcher) VerifyIdToken(ctx context.Context, idToken string) (*IdTokenClaims, error) {
+	cfg, err := tf.config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &IdTokenClaims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return tf.verificationKey(ctx, cfg, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(tf.loginUrl(cfg)),
+		jwt.WithAudience(cfg.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(tf.clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to verify salesforce id token: %w", err)
+	}
+	return claims, nil
+}
+
+// verificationKey returns the JWKS key for kid, the keys are fetched on first use and again when kid isn't found as
+// salesforce rotates them, at most once per jwksRefetchInterval
+func (tf *TokenFetcher) verificationKey(ctx context.Context, cfg *tokenFetcherCfg, kid string) (*rsa.PublicKey, error) {
+	tf.mu.Lock()
+	key, ok := tf.jwks[kid]
+	recent := tf.jwks != nil && time.Since(tf.jwksFetched) < jwksRefetchInterval
+	tf.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if recent {
+		return nil, fmt.Errorf("salesforce id token signed with unknown key: %s", kid)
+	}
+
+	keys, err := tf.fetchJwks(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	tf.mu.Lock()
+	tf.jwks = keys
+	tf.jwksFetched = time.Now()
+	tf.mu.Unlock()
+
+	if key, ok = keys[kid]; !ok {
+		return nil, fmt.Errorf("salesforce id token signed with unknown key: %s", kid)
+	}
+	return key, nil
+}
+
+func (tf *TokenFetcher) fetchJwks(ctx context.Context, cfg *tokenFetcherCfg) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tf.loginUrl(cfg)+"/id/keys", nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create salesforce jwks request: %w", err)
+	}
+	resp, err := tf.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch salesforce jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected salesforce jwks response code: %d", resp.StatusCode)
+	}
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var set jwks
+	if err = json.Unmarshal(resBody, &set); err != nil {
+		return nil, fmt.Errorf("unable to parse salesforce jwks: %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		key, err := k.rsaPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
@@ -0,0 +1,181 @@
+package salesforce
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func isJwksRequest(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/id/keys")
+}
+
+func newJwksResponse(kid string, key *rsa.PublicKey) *http.Response {
+	body, _ := json.Marshal(jwks{Keys: []jwk{{
+		Kid: kid,
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	return newResponse(200, string(body))
+}
+
+func newIdToken(t *testing.T, key *rsa.PrivateKey, kid, aud string, exp time.Time) string {
+	return signIdToken(t, key, kid, IdTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://login.salesforce.com",
+			Subject:   "https://login.salesforce.com/id/00Dxx0000001gEH/005xx0000012345",
+			Audience:  jwt.ClaimStrings{aud},
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+		PreferredUsername: "user@example.com",
+	})
+}
+
+func signIdToken(t *testing.T, key *rsa.PrivateKey, kid string, claims IdTokenClaims) string {
+	j := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	j.Header["kid"] = kid
+	tok, err := j.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+func TestTokenFetcher_VerifyIdToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		idToken string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "valid id token, claims returned",
+			idToken: newIdToken(t, key, "232", "client-id", time.Now().Add(time.Hour)),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "issued to another client, returns error",
+			idToken: newIdToken(t, key, "232", "other-client", time.Now().Add(time.Hour)),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "expired, returns error",
+			idToken: newIdToken(t, key, "232", "client-id", time.Now().Add(-time.Hour)),
+			wantErr: assert.Error,
+		},
+		{
+			name: "issued by another host, returns error",
+			idToken: signIdToken(t, key, "232", IdTokenClaims{RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "https://evil.example.com",
+				Audience:  jwt.ClaimStrings{"client-id"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			}}),
+			wantErr: assert.Error,
+		},
+		{
+			name: "no expiry, returns error",
+			idToken: signIdToken(t, key, "232", IdTokenClaims{RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:   "https://login.salesforce.com",
+				Audience: jwt.ClaimStrings{"client-id"},
+			}}),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "signed with another key, returns error",
+			idToken: newIdToken(t, otherKey, "232", "client-id", time.Now().Add(time.Hour)),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "unknown kid, returns error",
+			idToken: newIdToken(t, key, "999", "client-id", time.Now().Add(time.Hour)),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(CredentialsProviderMock)
+			provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(isJwksRequest)).Return(newJwksResponse("232", &key.PublicKey), nil).Once()
+			tf, _ := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider})
+
+			claims, err := tf.VerifyIdToken(context.Background(), tt.idToken)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assert.Equal(t, "user@example.com", claims.PreferredUsername)
+		})
+	}
+}
+
+func TestTokenFetcher_VerifyIdToken_UnknownKidRefetchThrottled(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isJwksRequest)).Return(func(*http.Request) (*http.Response, error) {
+		return newJwksResponse("232", &key.PublicKey), nil
+	})
+	tf, _ := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider})
+
+	for range 3 {
+		_, err = tf.VerifyIdToken(context.Background(), newIdToken(t, key, "999", "client-id", time.Now().Add(time.Hour)))
+		assert.Error(t, err)
+	}
+	client.AssertNumberOfCalls(t, "Do", 1)
+
+	_, err = tf.VerifyIdToken(context.Background(), newIdToken(t, key, "232", "client-id", time.Now().Add(time.Hour)))
+	assert.NoError(t, err)
+	client.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestTokenFetcher_FetchToken_VerifyIdToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idToken := newIdToken(t, key, "232", "client-id", time.Now().Add(time.Hour))
+
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"token","id_token":"`+idToken+`"}`), nil).Once()
+	client.On("Do", mock.MatchedBy(isJwksRequest)).Return(newJwksResponse("232", &key.PublicKey), nil).Once()
+	tf, _ := NewTokenFetcher(TokenParams{
+		HttpClient:    client,
+		Credentials:   provider,
+		Introspect:    IntrospectNever,
+		VerifyIdToken: true,
+		Backoff:       &backoff.StopBackOff{},
+	})
+
+	token, err := tf.FetchToken(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, idToken, token.IdToken)
+	if assert.NotNil(t, token.IdTokenClaims) {
+		assert.Equal(t, "user@example.com", token.IdTokenClaims.PreferredUsername)
+	}
+}
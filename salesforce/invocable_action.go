@@ -0,0 +1,115 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Action the path of an invocable action under /actions, e.g. standard/emailSimple or custom/flow/Onboard_Customer
+type Action string
+
+// StandardAction a standard invocable action, e.g. emailSimple or chatterPost
+func StandardAction(name string) Action {
+	return Action("standard/" + name)
+}
+
+// FlowAction an autolaunched flow, by api name
+func FlowAction(name string) Action {
+	return Action("custom/flow/" + name)
+}
+
+// ApexAction an invocable Apex method, by class name, prefixed by its namespace when packaged e.g. ns__Class
+func ApexAction(name string) Action {
+	return Action("custom/apex/" + name)
+}
+
+// EmailAlertAction an email alert of an object, e.g. Case and Escalation_Notice
+func EmailAlertAction(object, name string) Action {
+	return Action("custom/emailAlert/" + object + "." + name)
+}
+
+// ActionSummary an action listed by ListActions
+type ActionSummary struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+}
+
+// ActionParameter an input or output of an action
+type ActionParameter struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+}
+
+// ActionDescribe the inputs and outputs of an action
+type ActionDescribe struct {
+	Name    string            `json:"name"`
+	Label   string            `json:"label"`
+	Type    string            `json:"type"`
+	Inputs  []ActionParameter `json:"inputs"`
+	Outputs []ActionParameter `json:"outputs"`
+}
+
+// ActionResult the outcome of an action for one input, with its outputs decoded as O
+type ActionResult[O any] struct {
+	ActionName   string     `json:"actionName"`
+	IsSuccess    bool       `json:"isSuccess"`
+	OutputValues O          `json:"outputValues"`
+	Errors       []ApiError `json:"errors"`
+}
+
+// ListActions lists the actions of a kind, standard, custom or a custom type e.g. custom/flow or custom/apex
+func ListActions(ctx context.Context, h *RequestHelper, kind string) ([]ActionSummary, error) {
+	reqUrl, err := h.dataUrl(ctx, "/actions/"+strings.Trim(kind, "/"))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := getJson[struct {
+		Actions []ActionSummary `json:"actions"`
+	}](ctx, h, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Actions, nil
+}
+
+// DescribeAction fetches the inputs and outputs of an action
+func DescribeAction(ctx context.Context, h *RequestHelper, action Action) (*ActionDescribe, error) {
+	reqUrl, err := h.dataUrl(ctx, "/actions/"+string(action))
+	if err != nil {
+		return nil, err
+	}
+	return getJson[ActionDescribe](ctx, h, reqUrl)
+}
+
+// InvokeAction invokes an action once for each of inputs, encoded by their json field names as the action's input
+// names, returning a result per input in order. The results are returned along with an error when the action failed
+// for any input
+func InvokeAction[I, O any](ctx context.Context, h *RequestHelper, action Action, inputs ...I) ([]ActionResult[O], error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("inputs need to be provided")
+	}
+	reqUrl, err := h.dataUrl(ctx, "/actions/"+string(action))
+	if err != nil {
+		return nil, err
+	}
+	results, err := sendJson[[]ActionResult[O]](ctx, h, http.MethodPost, reqUrl, map[string]any{"inputs": inputs})
+	if err != nil {
+		return nil, err
+	}
+	failed := 0
+	for _, r := range *results {
+		if !r.IsSuccess {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return *results, fmt.Errorf("salesforce action %s failed for %d of %d inputs", action, failed, len(inputs))
+	}
+	return *results, nil
+}
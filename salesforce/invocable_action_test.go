@@ -0,0 +1,82 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+type onboardInputStub struct {
+	AccountId string `json:"accountId"`
+}
+
+type onboardOutputStub struct {
+	ContactCount int `json:"contactCount"`
+}
+
+func TestInvokeAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    string
+		want    []ActionResult[onboardOutputStub]
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "flow succeeds  outputs decoded",
+			resp: `[{"actionName":"Onboard_Customer","isSuccess":true,"outputValues":{"contactCount":2},"errors":null},
+				{"actionName":"Onboard_Customer","isSuccess":true,"outputValues":{"contactCount":0},"errors":null}]`,
+			want: []ActionResult[onboardOutputStub]{
+				{ActionName: "Onboard_Customer", IsSuccess: true, OutputValues: onboardOutputStub{ContactCount: 2}},
+				{ActionName: "Onboard_Customer", IsSuccess: true},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "flow fails for an input  results and error returned",
+			resp: `[{"actionName":"Onboard_Customer","isSuccess":true,"outputValues":{"contactCount":2},"errors":null},
+				{"actionName":"Onboard_Customer","isSuccess":false,"outputValues":null,"errors":[{"statusCode":"UNKNOWN_EXCEPTION","message":"boom"}]}]`,
+			want: []ActionResult[onboardOutputStub]{
+				{ActionName: "Onboard_Customer", IsSuccess: true, OutputValues: onboardOutputStub{ContactCount: 2}},
+				{ActionName: "Onboard_Customer", Errors: []ApiError{{StatusCode: "UNKNOWN_EXCEPTION", Message: "boom"}}},
+			},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/actions/custom/flow/Onboard_Customer", req.URL.Path)
+				b, _ := io.ReadAll(req.Body)
+				assert.JSONEq(t, `{"inputs":[{"accountId":"001xx0000000001"},{"accountId":"001xx0000000002"}]}`, string(b))
+				return newResponse(http.StatusOK, tt.resp), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := InvokeAction[onboardInputStub, onboardOutputStub](context.Background(), h, FlowAction("Onboard_Customer"),
+				onboardInputStub{AccountId: "001xx0000000001"}, onboardInputStub{AccountId: "001xx0000000002"})
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestActions(t *testing.T) {
+	assert.Equal(t, Action("standard/emailSimple"), StandardAction("emailSimple"))
+	assert.Equal(t, Action("custom/apex/ns__Onboard"), ApexAction("ns__Onboard"))
+	assert.Equal(t, Action("custom/emailAlert/Case.Escalation_Notice"), EmailAlertAction("Case", "Escalation_Notice"))
+}
+
+func TestDescribeAction(t *testing.T) {
+	client := newHttpClientMock(newResponse(http.StatusOK, `{"name":"Onboard_Customer","type":"FLOW",
+		"inputs":[{"name":"accountId","type":"STRING","required":true}],"outputs":[{"name":"contactCount","type":"INTEGER"}]}`), nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := DescribeAction(context.Background(), h, FlowAction("Onboard_Customer"))
+	assert.NoError(t, err)
+	assert.Equal(t, []ActionParameter{{Name: "accountId", Type: "STRING", Required: true}}, got.Inputs)
+	assert.Equal(t, "contactCount", got.Outputs[0].Name)
+}
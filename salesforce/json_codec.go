@@ -0,0 +1,66 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JsonCodec encodes request bodies and decodes response bodies, e.g. jsoniter.ConfigCompatibleWithStandardLibrary
+// in place of encoding/json, which dominates the CPU time of large query extracts. It must follow encoding/json's
+// handling of struct tags and json.Marshaler and json.Unmarshaler
+type JsonCodec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JsonDecoder decodes json values read from a stream, e.g. *jsoniter.Decoder
+type JsonDecoder interface {
+	Decode(v any) error
+}
+
+// SetJsonCodec encodes and decodes the records of queries, gets and writes with c rather than encoding/json. Error
+// bodies and payload rewriting, e.g. WithValidation and stripping read only fields, still use encoding/json. Without
+// SetJsonDecoder a response body is read into memory before c decodes it
+func (h *RequestHelper) SetJsonCodec(c JsonCodec) *RequestHelper {
+	h.codec = c
+	return h
+}
+
+// SetJsonDecoder decodes response bodies as they are read with the decoders of newDecoder, e.g. the NewDecoder of the
+// JsonCodec, so large query pages aren't held in memory twice
+func (h *RequestHelper) SetJsonDecoder(newDecoder func(r io.Reader) JsonDecoder) *RequestHelper {
+	h.newDecoder = newDecoder
+	return h
+}
+
+// marshal encodes v with the codec of h
+func (h *RequestHelper) marshal(v any) ([]byte, error) {
+	if h.codec == nil {
+		return json.Marshal(v)
+	}
+	return h.codec.Marshal(v)
+}
+
+// unmarshal decodes data into v with the codec of h
+func (h *RequestHelper) unmarshal(data []byte, v any) error {
+	if h.codec == nil {
+		return json.Unmarshal(data, v)
+	}
+	return h.codec.Unmarshal(data, v)
+}
+
+// decode decodes the json read from r into v with the decoder or codec of h
+func (h *RequestHelper) decode(r io.Reader, v any) error {
+	switch {
+	case h.newDecoder != nil:
+		return h.newDecoder(r).Decode(v)
+	case h.codec == nil:
+		return json.NewDecoder(r).Decode(v)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("unable to read response body: %w", err)
+	}
+	return h.codec.Unmarshal(b, v)
+}
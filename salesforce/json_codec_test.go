@@ -0,0 +1,143 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// countingCodec an encoding/json JsonCodec counting its calls
+type countingCodec struct {
+	marshals, unmarshals int
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func TestRequestHelper_SetJsonCodec(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPost {
+			return newResponse(http.StatusCreated, `{"id":"001xx0000000001","success":true}`), nil
+		}
+		return newResponse(http.StatusOK, `{"totalSize":1,"done":true,"records":[{"Id":"001xx0000000001","Name":"Acme"}]}`), nil
+	})
+	codec := &countingCodec{}
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	h.SetJsonCodec(codec)
+
+	resp, err := Query[struct{ Id, Name string }](context.Background(), h, "SELECT Id, Name FROM Account")
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme", resp.Records[0].Name)
+
+	id, err := Post(context.Background(), h, "Account", map[string]any{"Name": "Acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, "001xx0000000001", id)
+
+	assert.Equal(t, 1, codec.marshals)
+	assert.Equal(t, 2, codec.unmarshals)
+}
+
+func TestRequestHelper_SetJsonCodec_Patch(t *testing.T) {
+	client := newHttpClientMock(newResponse(http.StatusNoContent, ``), nil)
+	codec := &countingCodec{}
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	h.SetJsonCodec(codec)
+
+	_, err := Patch(context.Background(), h, "Account", "001xx0000000001", map[string]any{"Name": "Acme"})
+	assert.NoError(t, err)
+	_, err = Patch(context.Background(), h, "Account", "001xx0000000001", map[string]any{"Name": "Acme"}, WithFieldMask("Name"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, codec.marshals)
+}
+
+func TestRequestHelper_SetJsonCodec_QueryEach(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []RequestOption
+		wantUnmarshals int
+	}{
+		{name: "streamed  page split and records decoded by codec", wantUnmarshals: 3},
+		{name: "prefetched  pages decoded by codec", opts: []RequestOption{WithPrefetch()}, wantUnmarshals: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				return newResponse(http.StatusOK, `{"totalSize":2,"done":true,"records":[{"Name":"Acme"},{"Name":"Globex"}]}`), nil
+			})
+			codec := &countingCodec{}
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+			h.SetJsonCodec(codec)
+
+			var names []string
+			err := QueryEach(context.Background(), h, "SELECT Name FROM Account", func(r struct{ Name string }) error {
+				names = append(names, r.Name)
+				return nil
+			}, tt.opts...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Acme", "Globex"}, names)
+			assert.Equal(t, tt.wantUnmarshals, codec.unmarshals)
+		})
+	}
+}
+
+func TestRequestHelper_SetJsonCodec_Composite(t *testing.T) {
+	client := newHttpClientMock(newResponse(http.StatusOK, `{"compositeResponse":[{"referenceId":"refAccount",
+		"httpStatusCode":200,"body":{"Id":"001xx0000000001","Name":"Acme"}}]}`), nil)
+	codec := &countingCodec{}
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	h.SetJsonCodec(codec)
+
+	resp, err := NewComposite(false).
+		Add("refAccount", CompositePatch("Account", "001xx0000000001", map[string]any{"Name": "Acme"})).
+		Send(context.Background(), h)
+	assert.NoError(t, err)
+	// the record and the request are encoded, the record's fields merged and the response decoded by the codec
+	assert.Equal(t, 2, codec.marshals)
+	assert.Equal(t, 2, codec.unmarshals)
+
+	result, _ := resp.Result("refAccount")
+	var account struct{ Id, Name string }
+	assert.NoError(t, result.Decode(&account))
+	assert.Equal(t, "Acme", account.Name)
+	assert.Equal(t, 3, codec.unmarshals)
+}
+
+// countingDecoder an encoding/json JsonDecoder counting the decoders created
+type countingDecoder struct {
+	created int
+}
+
+func (c *countingDecoder) newDecoder(r io.Reader) JsonDecoder {
+	c.created++
+	return json.NewDecoder(r)
+}
+
+func TestRequestHelper_SetJsonDecoder(t *testing.T) {
+	client := newHttpClientMock(newResponse(http.StatusOK, `{"totalSize":1,"done":true,"records":[{"Name":"Acme"}]}`), nil)
+	codec := &countingCodec{}
+	dec := &countingDecoder{}
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	h.SetJsonCodec(codec).SetJsonDecoder(dec.newDecoder)
+
+	resp, err := Query[struct{ Name string }](context.Background(), h, "SELECT Name FROM Account")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme", resp.Records[0].Name)
+	assert.Equal(t, 1, dec.created)
+	assert.Equal(t, 0, codec.unmarshals)
+}
@@ -0,0 +1,93 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/ellogroup/ello-golang-cache/cache"
+	"github.com/ellogroup/ello-golang-cache/driver"
+	"go.uber.org/zap"
+	"os"
+	"time"
+)
+
+const (
+	// lambdaInitTypeEnv the environment variable lambda sets to how the execution environment was initialised
+	lambdaInitTypeEnv = "AWS_LAMBDA_INITIALIZATION_TYPE"
+	// lambdaProvisioned the initialisation type of a provisioned concurrency environment
+	lambdaProvisioned = "provisioned-concurrency"
+	// defaultLambdaWarmTimeout the longest WarmProvisioned blocks initialisation for
+	defaultLambdaWarmTimeout = 5 * time.Second
+)
+
+// LambdaParams the configuration of NewLambda
+type LambdaParams struct {
+	TokenParams
+	// BaseUrl optional, the token's instance url is used when empty
+	BaseUrl    string
+	ApiVersion int
+	// Driver optional, e.g. NewDynamoDBCacheDriver, shares the token with the function's other execution
+	// environments, each environment caches its own token in memory when nil
+	Driver driver.Cache[int, cache.RecordCacheItem[Token]]
+	// Logger optional, logs the token cache and a failed warm up
+	Logger *zap.Logger
+	// WarmProvisioned fetches the token during initialisation of a provisioned concurrency environment, which runs
+	// ahead of any invocation, so the first invocation doesn't wait on the JWT exchange. On demand environments never
+	// block in initialisation
+	WarmProvisioned bool
+	// WarmTimeout optional, the longest WarmProvisioned blocks initialisation for, defaults to 5 seconds
+	WarmTimeout time.Duration
+}
+
+// Lambda the token cache and request helper of an AWS Lambda, created once in initialisation, e.g. a package level
+// var, and shared by every invocation of the execution environment
+type Lambda struct {
+	Token  *TokenCache
+	Helper *RequestHelper
+}
+
+// NewLambda creates a Lambda, without making any network calls: the credentials and token are fetched by the first
+// invocation which needs them, unless WarmProvisioned. The token is cached on demand, rather than refreshed in the
+// background, as lambda freezes background goroutines between invocations
+func NewLambda(p LambdaParams) (*Lambda, error) {
+	log := p.Logger
+	if log == nil {
+		log = zap.NewNop()
+	}
+	d := p.Driver
+	if d == nil {
+		d = newMemoryTokenDriver()
+	}
+	tc, err := newTokenCache(p.TokenParams, d, log, TokenRefreshOnDemand)
+	if err != nil {
+		return nil, err
+	}
+
+	var h *RequestHelper
+	if len(p.BaseUrl) > 0 {
+		h, err = NewRequestHelper(p.HttpClient, tc, p.BaseUrl, p.ApiVersion)
+	} else {
+		h, err = NewRequestHelperWithInstanceUrl(p.HttpClient, tc, p.ApiVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Lambda{Token: tc, Helper: h}
+	if p.WarmProvisioned && os.Getenv(lambdaInitTypeEnv) == lambdaProvisioned {
+		timeout := p.WarmTimeout
+		if timeout <= 0 {
+			timeout = defaultLambdaWarmTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// a failed warm up is retried by the first invocation, it mustn't fail initialisation
+		if err := l.Warm(ctx); err != nil {
+			log.Warn("unable to warm salesforce token", zap.Error(err))
+		}
+	}
+	return l, nil
+}
+
+// Warm fetches the token if one isn't already cached, e.g. from a warm up event handler or an invocation's spare time
+func (l *Lambda) Warm(ctx context.Context) error {
+	return l.Token.Warm(ctx)
+}
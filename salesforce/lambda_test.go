@@ -0,0 +1,138 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewLambda(t *testing.T) {
+	tests := []struct {
+		name        string
+		p           LambdaParams
+		wantBaseUrl string
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "base url  used",
+			p: LambdaParams{
+				TokenParams: TokenParams{HttpClient: new(HttpClientMock), Credentials: new(CredentialsProviderMock)},
+				BaseUrl:     "https://org.my.salesforce.com",
+				ApiVersion:  59,
+			},
+			wantBaseUrl: "https://org.my.salesforce.com",
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "no base url  instance url used",
+			p: LambdaParams{
+				TokenParams: TokenParams{HttpClient: new(HttpClientMock), Credentials: new(CredentialsProviderMock)},
+				ApiVersion:  59,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid token params  error returned",
+			p:       LambdaParams{ApiVersion: 59},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// no calls are expected of the mocks, initialisation mustn't block on the network
+			got, err := NewLambda(tt.p)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantBaseUrl, got.Helper.baseUrl)
+		})
+	}
+}
+
+func TestNewLambda_WarmProvisioned(t *testing.T) {
+	t.Setenv(lambdaInitTypeEnv, lambdaProvisioned)
+	creds := new(CredentialsProviderMock)
+	creds.On("Credentials", mock.Anything).Return(Credentials{}, assert.AnError)
+
+	l, err := NewLambda(LambdaParams{
+		TokenParams:     TokenParams{HttpClient: new(HttpClientMock), Credentials: creds, Backoff: &backoff.StopBackOff{}},
+		ApiVersion:      59,
+		WarmProvisioned: true,
+	})
+
+	// a failed warm up doesn't fail initialisation
+	assert.NoError(t, err)
+	assert.NotNil(t, l)
+	creds.AssertCalled(t, "Credentials", mock.Anything)
+}
+
+func TestNewLambda_NoDriverReads(t *testing.T) {
+	d := newCountingDriver()
+
+	_, err := NewLambda(LambdaParams{
+		TokenParams: TokenParams{HttpClient: new(HttpClientMock), Credentials: new(CredentialsProviderMock)},
+		ApiVersion:  59,
+		Driver:      d,
+	})
+
+	// a DynamoDB driver would otherwise be queried during initialisation
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), d.alls.Load())
+	assert.Equal(t, int32(0), d.gets.Load())
+}
+
+func TestNewLambda_WarmTimeout(t *testing.T) {
+	t.Setenv(lambdaInitTypeEnv, lambdaProvisioned)
+	creds := new(CredentialsProviderMock)
+	creds.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).
+		Run(func(mock.Arguments) { <-release }).
+		Return(newResponse(http.StatusOK, `{"access_token":"late"}`), nil)
+
+	start := time.Now()
+	_, err := NewLambda(LambdaParams{
+		TokenParams:     TokenParams{HttpClient: client, Credentials: creds, Introspect: IntrospectNever},
+		ApiVersion:      59,
+		WarmProvisioned: true,
+		WarmTimeout:     50 * time.Millisecond,
+	})
+
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// lambdaHandler the Lambda of the example, created in initialisation and shared by every invocation
+var lambdaHandler *Lambda
+
+func ExampleNewLambda() {
+	// in the function's init, or main before lambda.Start, nothing here blocks on salesforce
+	var err error
+	lambdaHandler, err = NewLambda(LambdaParams{
+		TokenParams: TokenParams{
+			HttpClient: &http.Client{Timeout: 30 * time.Second},
+			SMClient:   secretsmanager.New(secretsmanager.Options{Region: "eu-west-2"}),
+			SMKey:      "SALESFORCE_AUTH_CREDS",
+		},
+		ApiVersion: 59,
+		// fetch the token ahead of the first invocation when running with provisioned concurrency
+		WarmProvisioned: true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// in the handler, the token is fetched on first use and cached for later invocations
+	handle := func(ctx context.Context, accountId string) error {
+		_, err := Get[map[string]any](ctx, lambdaHandler.Helper, "Account", accountId, "Name")
+		return err
+	}
+	_ = handle
+}
@@ -0,0 +1,44 @@
+package salesforce
+
+import (
+	"time"
+)
+
+const (
+	// MetricRequest counted for every request sent to salesforce, tagged with method and status
+	MetricRequest = "salesforce.request"
+	// MetricRequestLatency time taken for salesforce to respond, tagged with method and status
+	MetricRequestLatency = "salesforce.request.latency"
+
+	// MetricTokenCacheHit counted when a token is served from the cache without a refresh
+	MetricTokenCacheHit = "salesforce.token_cache.hit"
+	// MetricTokenCacheMiss counted when there is no fresh token cached and a request waits on a fetch
+	MetricTokenCacheMiss = "salesforce.token_cache.miss"
+	// MetricTokenCacheRefresh counted for every token fetch, whether scheduled, on demand or ahead of expiry
+	MetricTokenCacheRefresh = "salesforce.token_cache.refresh"
+	// MetricTokenCacheRefreshFailure counted when a token fetch fails after all retries
+	MetricTokenCacheRefreshFailure = "salesforce.token_cache.refresh_failure"
+	// MetricTokenCacheRefreshLatency time taken to fetch a token, including retries
+	MetricTokenCacheRefreshLatency = "salesforce.token_cache.refresh_latency"
+
+	// MetricSubscriptionEvent counted for every event received by an event subscription, tagged with subscription
+	MetricSubscriptionEvent = "salesforce.subscription.event"
+	// MetricSubscriptionDecodeFailure counted when an event can't be decoded, tagged with subscription
+	MetricSubscriptionDecodeFailure = "salesforce.subscription.decode_failure"
+	// MetricSubscriptionReconnect counted when a subscription run reconnects after a failure, tagged with subscription
+	MetricSubscriptionReconnect = "salesforce.subscription.reconnect"
+	// MetricSubscriptionLag time between an event being published and received, tagged with subscription. A growing
+	// lag means the subscriber is falling behind its channel
+	MetricSubscriptionLag = "salesforce.subscription.lag"
+	// MetricSubscriptionReplayAge time since the replay id of a subscription was last saved to its ReplayStore,
+	// recorded on every receive, tagged with subscription. A growing age means a restart would redeliver ever more
+	// events, as the subscriber is stuck on a batch or its checkpoints are failing
+	MetricSubscriptionReplayAge = "salesforce.subscription.replay_age"
+)
+
+// Metrics receives counters and timings from RequestHelper, TokenCache and event subscriptions, to be forwarded to e.g. statsd,
+// prometheus or cloudwatch
+type Metrics interface {
+	Count(name string, value int64, tags map[string]string)
+	Timing(name string, d time.Duration, tags map[string]string)
+}
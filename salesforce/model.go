@@ -1,5 +1,10 @@
 package salesforce
 
+import (
+	"strconv"
+	"strings"
+)
+
 // QueryResponse see https://ellogroup.atlassian.net/wiki/spaces/EP/pages/13402137/Salesforce+Package#QueryResponse%5BE-any%5D
 // for more detail on below
 // NB. if more models added here please update the above page
@@ -7,12 +12,15 @@ type QueryResponse[E any] struct {
 	TotalSize int  `json:"totalSize"`
 	Done      bool `json:"done"`
 	Records   []E  `json:"records"`
+	// NextRecordsUrl the url of the next page when Done is false, see QueryMore
+	NextRecordsUrl string `json:"nextRecordsUrl,omitempty"`
 }
 
 // PostResponse is the response from Salesforce for a post/create request
 type PostResponse struct {
-	Id      string `json:"id"`
-	Success bool   `json:"success"`
+	Id      string     `json:"id"`
+	Success bool       `json:"success"`
+	Errors  []ApiError `json:"errors"`
 }
 
 // Attributes to be added, optionally, to concrete types of E for QueryResponse[E]
@@ -20,3 +28,144 @@ type Attributes struct {
 	Type string `json:"type"`
 	Url  string `json:"url"`
 }
+
+// RecordId the id of the record, parsed from Url, empty when Url isn't a record url
+func (a Attributes) RecordId() string {
+	_, id, err := ParseRecordUrl(a.Url)
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// ApiVersion the major REST API version the record was returned by, parsed from Url, 0 when Url has no version
+func (a Attributes) ApiVersion() int {
+	for _, segment := range strings.Split(a.Url, "/") {
+		if !strings.HasPrefix(segment, "v") || !strings.HasSuffix(segment, ".0") {
+			continue
+		}
+		if v, err := strconv.Atoi(strings.TrimSuffix(segment[1:], ".0")); err == nil {
+			return v
+		}
+	}
+	return 0
+}
+
+// UserInfo is the response from the Salesforce OAuth userinfo endpoint, identifying the user and org a token acts as
+type UserInfo struct {
+	UserId            string `json:"user_id"`
+	OrganizationId    string `json:"organization_id"`
+	PreferredUsername string `json:"preferred_username"`
+	Name              string `json:"name"`
+	Email             string `json:"email"`
+	UserType          string `json:"user_type"`
+	Active            bool   `json:"active"`
+	Locale            string `json:"locale"`
+	Language          string `json:"language"`
+	ZoneInfo          string `json:"zoneinfo"`
+}
+
+// ApiVersion is an entry from the Salesforce versions endpoint, /services/data
+type ApiVersion struct {
+	Label   string `json:"label"`
+	Url     string `json:"url"`
+	Version string `json:"version"`
+}
+
+// UpsertResponse is the response from Salesforce for an upsert by external id, Created is false when an existing
+// record was updated
+type UpsertResponse struct {
+	Id      string `json:"id"`
+	Success bool   `json:"success"`
+	Created bool   `json:"created"`
+}
+
+// ApiError is an error reported by Salesforce against a record, e.g. in an sObject Collections response
+type ApiError struct {
+	StatusCode string   `json:"statusCode"`
+	Message    string   `json:"message"`
+	Fields     []string `json:"fields"`
+}
+
+// CollectionResult is the outcome for a single record of an sObject Collections request
+type CollectionResult struct {
+	Id      string     `json:"id"`
+	Success bool       `json:"success"`
+	Created bool       `json:"created"`
+	Errors  []ApiError `json:"errors"`
+}
+
+// PicklistValue an active value of a picklist field
+type PicklistValue struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+	// ValidFor the indexes into the field's ControllerValues the value is valid for, for dependent picklists
+	ValidFor []int `json:"validFor"`
+}
+
+// PicklistField the active values of a picklist field for a record type
+type PicklistField struct {
+	// ControllerValues the controlling field's values by index, empty unless the picklist is dependent
+	ControllerValues map[string]int  `json:"controllerValues"`
+	DefaultValue     *PicklistValue  `json:"defaultValue"`
+	Values           []PicklistValue `json:"values"`
+}
+
+// Contains whether value is one of the field's active values
+func (f PicklistField) Contains(value string) bool {
+	for _, v := range f.Values {
+		if v.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ObjectDescribe is the describe of an object, its metadata and fields
+type ObjectDescribe struct {
+	Name       string          `json:"name"`
+	Label      string          `json:"label"`
+	KeyPrefix  string          `json:"keyPrefix"`
+	Createable bool            `json:"createable"`
+	Updateable bool            `json:"updateable"`
+	Deletable  bool            `json:"deletable"`
+	Fields     []FieldDescribe `json:"fields"`
+}
+
+// Field the describe of the named field, matched case-insensitively as salesforce does, nil if there isn't one
+func (d ObjectDescribe) Field(name string) *FieldDescribe {
+	for i, f := range d.Fields {
+		if strings.EqualFold(f.Name, name) {
+			return &d.Fields[i]
+		}
+	}
+	return nil
+}
+
+// FieldDescribe is the describe of a single field of an object
+type FieldDescribe struct {
+	Name               string          `json:"name"`
+	Label              string          `json:"label"`
+	Type               string          `json:"type"`
+	Length             int             `json:"length"`
+	Nillable           bool            `json:"nillable"`
+	Createable         bool            `json:"createable"`
+	Updateable         bool            `json:"updateable"`
+	DefaultedOnCreate  bool            `json:"defaultedOnCreate"`
+	Calculated         bool            `json:"calculated"`
+	AutoNumber         bool            `json:"autoNumber"`
+	RestrictedPicklist bool            `json:"restrictedPicklist"`
+	PicklistValues     []PicklistEntry `json:"picklistValues"`
+}
+
+// Required whether the field must be set when creating a record
+func (f FieldDescribe) Required() bool {
+	return f.Createable && !f.Nillable && !f.DefaultedOnCreate && f.Type != "boolean"
+}
+
+// PicklistEntry a value of a picklist field's describe
+type PicklistEntry struct {
+	Label  string `json:"label"`
+	Value  string `json:"value"`
+	Active bool   `json:"active"`
+}
@@ -0,0 +1,34 @@
+package salesforce
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAttributes(t *testing.T) {
+	tests := []struct {
+		name           string
+		attributes     Attributes
+		wantRecordId   string
+		wantApiVersion int
+	}{
+		{
+			name:           "record url  id and version returned",
+			attributes:     Attributes{Type: "Account", Url: "/services/data/v55.0/sobjects/Account/001xx000003DGb2AAG"},
+			wantRecordId:   "001xx000003DGb2AAG",
+			wantApiVersion: 55,
+		},
+		{
+			name:           "no url  empty returned",
+			attributes:     Attributes{Type: "AggregateResult"},
+			wantRecordId:   "",
+			wantApiVersion: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantRecordId, tt.attributes.RecordId())
+			assert.Equal(t, tt.wantApiVersion, tt.attributes.ApiVersion())
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNullableNotSpecified returned by Nullable.Get when the value is neither set nor null
+var ErrNullableNotSpecified = errors.New("nullable value not specified")
+
+// ErrNullableIsNull returned by Nullable.Get when the value is explicitly null
+var ErrNullableIsNull = errors.New("nullable value is null")
+
+// Nullable a field which can be omitted, set to null or set to a value, so a Patch can blank a salesforce field. Use
+// with omitempty: an unspecified Nullable is omitted, a null one is sent as null
+//
+//	type Account struct {
+//		Description salesforce.Nullable[string] `json:"Description,omitempty"`
+//	}
+//
+// It is a map so that omitempty applies, true holds the value, false marks null
+type Nullable[T any] map[bool]T
+
+// NullString, NullBool, NullFloat and NullDate the common salesforce field types
+type (
+	NullString = Nullable[string]
+	NullBool   = Nullable[bool]
+	NullFloat  = Nullable[float64]
+	NullDate   = Nullable[Date]
+)
+
+// NewNullableWithValue a Nullable set to v
+func NewNullableWithValue[T any](v T) Nullable[T] {
+	n := Nullable[T]{}
+	n.Set(v)
+	return n
+}
+
+// NewNullNullable a Nullable set to null
+func NewNullNullable[T any]() Nullable[T] {
+	n := Nullable[T]{}
+	n.SetNull()
+	return n
+}
+
+// Get returns the value, ErrNullableIsNull if null or ErrNullableNotSpecified if unspecified
+func (n Nullable[T]) Get() (T, error) {
+	var empty T
+	if n.IsNull() {
+		return empty, ErrNullableIsNull
+	}
+	if !n.IsSpecified() {
+		return empty, ErrNullableNotSpecified
+	}
+	return n[true], nil
+}
+
+func (n *Nullable[T]) Set(v T) {
+	*n = map[bool]T{true: v}
+}
+
+func (n *Nullable[T]) SetNull() {
+	var empty T
+	*n = map[bool]T{false: empty}
+}
+
+func (n *Nullable[T]) SetUnspecified() {
+	*n = nil
+}
+
+// IsNull whether the value is explicitly null
+func (n Nullable[T]) IsNull() bool {
+	_, ok := n[false]
+	return ok
+}
+
+// IsSpecified whether the value is set or null, rather than omitted
+func (n Nullable[T]) IsSpecified() bool {
+	return len(n) > 0
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.IsNull() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n[true])
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		n.SetNull()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.Set(v)
+	return nil
+}
+
+const dateLayout = "2006-01-02"
+
+// Date a salesforce date field, formatted as YYYY-MM-DD rather than a full timestamp
+type Date struct {
+	time.Time
+}
+
+// NewDate a Date for the given day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) String() string {
+	return d.Format(dateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return fmt.Errorf("invalid salesforce date %q: %w", s, err)
+	}
+	*d = Date{t}
+	return nil
+}
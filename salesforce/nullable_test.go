@@ -0,0 +1,69 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type nullableStub struct {
+	Description NullString `json:"Description,omitempty"`
+	CloseDate   NullDate   `json:"CloseDate,omitempty"`
+}
+
+func TestNullable_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		record nullableStub
+		want   string
+	}{
+		{
+			name:   "unspecified  omitted",
+			record: nullableStub{},
+			want:   `{}`,
+		},
+		{
+			name:   "null  sent as null",
+			record: nullableStub{Description: NewNullNullable[string]()},
+			want:   `{"Description":null}`,
+		},
+		{
+			name:   "value  sent as value",
+			record: nullableStub{Description: NewNullableWithValue("text"), CloseDate: NewNullableWithValue(NewDate(2024, time.March, 1))},
+			want:   `{"Description":"text","CloseDate":"2024-03-01"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.record)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestNullable_UnmarshalJSON(t *testing.T) {
+	var got nullableStub
+	assert.NoError(t, json.Unmarshal([]byte(`{"Description":null,"CloseDate":"2024-03-01"}`), &got))
+
+	assert.True(t, got.Description.IsNull())
+	_, err := got.Description.Get()
+	assert.ErrorIs(t, err, ErrNullableIsNull)
+
+	date, err := got.CloseDate.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, NewDate(2024, time.March, 1), date)
+
+	got = nullableStub{}
+	assert.NoError(t, json.Unmarshal([]byte(`{}`), &got))
+	assert.False(t, got.Description.IsSpecified())
+	_, err = got.Description.Get()
+	assert.ErrorIs(t, err, ErrNullableNotSpecified)
+}
+
+func TestDiff_Nullable(t *testing.T) {
+	got, err := Diff(nullableStub{Description: NewNullableWithValue("text")}, nullableStub{Description: NewNullNullable[string]()})
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(got["Description"]))
+}
@@ -0,0 +1,75 @@
+package salesforce
+
+import (
+	"context"
+)
+
+// ObjectClient a typed client for a single sObject, so the object name and record type are given once rather than on
+// every call to the request functions
+type ObjectClient[T any] struct {
+	h    *RequestHelper
+	name string
+}
+
+func NewObjectClient[T any](h *RequestHelper, name string) *ObjectClient[T] {
+	return &ObjectClient[T]{
+		h:    h,
+		name: name,
+	}
+}
+
+// Name the sObject api name the client is bound to
+func (o *ObjectClient[T]) Name() string {
+	return o.name
+}
+
+// Get fetches a record by id, fields limits the fields returned
+func (o *ObjectClient[T]) Get(ctx context.Context, id string, fields ...string) (*T, error) {
+	return Get[T](ctx, o.h, o.name, id, fields...)
+}
+
+// Exists whether the record with id exists
+func (o *ObjectClient[T]) Exists(ctx context.Context, id string) (bool, error) {
+	return Exists(ctx, o.h, o.name, id)
+}
+
+// Query runs a SOQL query decoding the records as T
+func (o *ObjectClient[T]) Query(ctx context.Context, q string) (*QueryResponse[T], error) {
+	return Query[T](ctx, o.h, q)
+}
+
+// Create creates a record, returning its id, WithValidation checks the record before it is sent
+func (o *ObjectClient[T]) Create(ctx context.Context, record T, opts ...RequestOption) (string, error) {
+	return Post(ctx, o.h, o.name, record, opts...)
+}
+
+// Update updates the record with id, WithFieldMask limits the fields sent and WithValidation checks them
+func (o *ObjectClient[T]) Update(ctx context.Context, id string, record T, opts ...RequestOption) error {
+	_, err := Patch(ctx, o.h, o.name, id, record, opts...)
+	return err
+}
+
+// UpdateChanged updates the record with id, sending only the fields of modified which differ from original. No
+// request is sent when nothing has changed
+func (o *ObjectClient[T]) UpdateChanged(ctx context.Context, id string, original, modified T) error {
+	changed, err := Diff(original, modified)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	_, err = Patch(ctx, o.h, o.name, id, changed)
+	return err
+}
+
+// Upsert creates or updates the record with extId in the external id field extIdField, WithFieldMask limits the fields
+// sent and WithValidation checks them
+func (o *ObjectClient[T]) Upsert(ctx context.Context, extIdField, extId string, record T, opts ...RequestOption) (*UpsertResponse, error) {
+	return Upsert(ctx, o.h, o.name, extIdField, extId, record, opts...)
+}
+
+// Delete deletes the record with id
+func (o *ObjectClient[T]) Delete(ctx context.Context, id string) error {
+	return Delete(ctx, o.h, o.name, id)
+}
@@ -0,0 +1,55 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestObjectClient(t *testing.T) {
+	newBody := func(body string) io.ReadCloser {
+		return io.NopCloser(bytes.NewReader([]byte(body)))
+	}
+	isRequest := func(method, reqUrl string) func(req *http.Request) bool {
+		return func(req *http.Request) bool {
+			return req.Method == method && req.URL.String() == reqUrl
+		}
+	}
+
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isRequest(http.MethodGet, "https://org/services/data/v55.0/sobjects/Account/id-123"))).
+		Return(&http.Response{StatusCode: 200, Body: newBody(`{"foo":"bar"}`)}, nil)
+	client.On("Do", mock.MatchedBy(isRequest(http.MethodPost, "https://org/services/data/v55.0/sobjects/Account"))).
+		Return(&http.Response{StatusCode: 201, Body: newBody(`{"id":"id-123","success":true}`)}, nil)
+	client.On("Do", mock.MatchedBy(isRequest(http.MethodPatch, "https://org/services/data/v55.0/sobjects/Account/id-123"))).
+		Return(&http.Response{StatusCode: 204, Body: newBody(``)}, nil)
+	client.On("Do", mock.MatchedBy(isRequest(http.MethodPatch, "https://org/services/data/v55.0/sobjects/Account/Ext__c/ext-1"))).
+		Return(&http.Response{StatusCode: 201, Body: newBody(`{"id":"id-123","success":true,"created":true}`)}, nil)
+	client.On("Do", mock.MatchedBy(isRequest(http.MethodDelete, "https://org/services/data/v55.0/sobjects/Account/id-123"))).
+		Return(&http.Response{StatusCode: 204, Body: newBody(``)}, nil)
+
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	accounts := NewObjectClient[recordStub](h, "Account")
+	ctx := context.Background()
+
+	got, err := accounts.Get(ctx, "id-123")
+	assert.NoError(t, err)
+	assert.Equal(t, &recordStub{Foo: "bar"}, got)
+
+	id, err := accounts.Create(ctx, recordStub{Foo: "bar"})
+	assert.NoError(t, err)
+	assert.Equal(t, "id-123", id)
+
+	assert.NoError(t, accounts.Update(ctx, "id-123", recordStub{Foo: "baz"}))
+
+	upserted, err := accounts.Upsert(ctx, "Ext__c", "ext-1", recordStub{Foo: "bar"})
+	assert.NoError(t, err)
+	assert.True(t, upserted.Created)
+
+	assert.NoError(t, accounts.Delete(ctx, "id-123"))
+	client.AssertNumberOfCalls(t, "Do", 5)
+}
@@ -0,0 +1,175 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// RequestOption optional settings for a single request, e.g. WithFieldMask
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	fieldMask   []string
+	concurrency int
+	prefetch    bool
+	dryRun      bool
+	allOrNone   bool
+	validate    bool
+	writable    bool
+	headers     http.Header
+	// bulkThreshold the number of records above which a write uses Bulk API 2.0 jobs, see WithBulkThreshold
+	bulkThreshold int
+	// queryTimeoutRetries and narrowQuery the retries of a query which times out, see WithQueryTimeoutRetry
+	queryTimeoutRetries int
+	narrowQuery         QueryNarrower
+}
+
+func newRequestOptions(opts []RequestOption) requestOptions {
+	o := requestOptions{concurrency: 1, bulkThreshold: defaultBulkThreshold}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithFieldMask sends only the given fields (by json name) of the record, fields in the mask the record omits, e.g.
+// empty omitempty fields, are sent as null to clear them
+func WithFieldMask(fields ...string) RequestOption {
+	return func(o *requestOptions) {
+		o.fieldMask = fields
+	}
+}
+
+// WithConcurrency sends up to n of the requests an operation is split into at once, e.g. the chunks of GetByIds, they
+// are sent one at a time by default
+func WithConcurrency(n int) RequestOption {
+	return func(o *requestOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithPrefetch fetches the next page of a query while the records of the current page are processed, e.g. by
+// QueryEach, so slow processing and page requests overlap. At most one page is fetched ahead
+func WithPrefetch() RequestOption {
+	return func(o *requestOptions) {
+		o.prefetch = true
+	}
+}
+
+// WithDryRun reports what an operation would change without changing it, e.g. the records DeleteWhere matches
+func WithDryRun() RequestOption {
+	return func(o *requestOptions) {
+		o.dryRun = true
+	}
+}
+
+// WithAllOrNone rolls back every record of a multi-record write when any fails, e.g. UpsertMany, returning an
+// AllOrNoneError. Such writes are limited to a single request, 200 records, so they remain a single transaction
+func WithAllOrNone() RequestOption {
+	return func(o *requestOptions) {
+		o.allOrNone = true
+	}
+}
+
+// WithBulkThreshold sends writes of more than n records, e.g. UpsertMany, as Bulk API 2.0 jobs rather than sObject
+// Collections requests, 2,000 by default. A bulk job uses a handful of API requests however many records it has, but
+// is processed asynchronously, so takes longer for a few records. n <= 0 never uses bulk jobs
+func WithBulkThreshold(n int) RequestOption {
+	return func(o *requestOptions) {
+		o.bulkThreshold = n
+	}
+}
+
+// useBulk whether a write of n records is sent as Bulk API 2.0 jobs, never for all or none writes which need a single
+// transaction
+func (o requestOptions) useBulk(n int) bool {
+	return o.bulkThreshold > 0 && n > o.bulkThreshold && !o.allOrNone
+}
+
+// WithValidation checks a record against its object's describe before it is sent, e.g. by Post or Patch, returning a
+// ValidationError for unknown, read only, mistyped, too long or missing required fields and values not in a restricted
+// picklist rather than spending an API call on a request salesforce would reject. The describe is fetched once per
+// RequestHelper
+func WithValidation() RequestOption {
+	return func(o *requestOptions) {
+		o.validate = true
+	}
+}
+
+// WithWritableFieldsOnly drops the fields of a record the running user can't write before it is sent, e.g. by Post or
+// Patch, using its object's describe: formula, auto number and other read only fields, and fields hidden from the user
+// by field level security, rather than salesforce failing the request with INVALID_FIELD_FOR_INSERT_UPDATE. The
+// describe is fetched once per RequestHelper
+func WithWritableFieldsOnly() RequestOption {
+	return func(o *requestOptions) {
+		o.writable = true
+	}
+}
+
+// WithHeader sets a header on the request, e.g. Sforce-Duplicate-Rule-Header, replacing any value the request would
+// otherwise send. Salesforce's own call option headers have their own options, e.g. WithAutoAssign
+func WithHeader(name, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.headers == nil {
+			o.headers = http.Header{}
+		}
+		o.headers.Set(name, value)
+	}
+}
+
+// WithAutoAssign runs an assignment rule on a created or updated Case or Lead, the active rule when ruleId is empty
+func WithAutoAssign(ruleId string) RequestOption {
+	if len(ruleId) == 0 {
+		ruleId = "TRUE"
+	}
+	return WithHeader("Sforce-Auto-Assign", ruleId)
+}
+
+// WithCallOptions sets the Sforce-Call-Options header, e.g. to identify the client or a managed package's namespace,
+// from options such as "client=ops-tool" or "defaultNamespace=ns"
+func WithCallOptions(options ...string) RequestOption {
+	return WithHeader("Sforce-Call-Options", strings.Join(options, ", "))
+}
+
+// QueryNarrower returns a narrower version of a query which timed out, e.g. over a shorter date range, for retry
+// attempt, numbered from 1. Returning an error stops the retries
+type QueryNarrower func(q string, attempt int) (string, error)
+
+// WithQueryTimeoutRetry retries a query which fails with QUERY_TIMEOUT up to retries times, e.g. for backfills over
+// skewed data. Each retry halves the batch size of the Sforce-Query-Options header, down to salesforce's minimum of
+// 200, and, when narrow isn't nil, runs the query narrow returns
+func WithQueryTimeoutRetry(retries int, narrow QueryNarrower) RequestOption {
+	return func(o *requestOptions) {
+		o.queryTimeoutRetries = retries
+		o.narrowQuery = narrow
+	}
+}
+
+// setHeaders sets the headers of WithHeader on req
+func (o requestOptions) setHeaders(req *http.Request) {
+	for name, values := range o.headers {
+		req.Header[name] = values
+	}
+}
+
+// marshalRecord marshals record for a request body with the codec of h, applying the field mask if one is set
+func (o requestOptions) marshalRecord(h *RequestHelper, record any) ([]byte, error) {
+	b, err := h.marshal(record)
+	if err != nil || len(o.fieldMask) == 0 {
+		return b, err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	masked := make(map[string]json.RawMessage, len(o.fieldMask))
+	for _, name := range o.fieldMask {
+		value, ok := fields[name]
+		if !ok {
+			value = json.RawMessage("null")
+		}
+		masked[name] = value
+	}
+	return json.Marshal(masked)
+}
@@ -0,0 +1,176 @@
+package salesforce
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// outboundMessageMaxSize the largest envelope read, an outbound message holds up to 100 notifications
+const outboundMessageMaxSize = 10 << 20
+
+// outboundMessageAck the response acknowledging delivery, salesforce retries the message until it receives one
+const outboundMessageAck = `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">
+<soapenv:Body>
+<notificationsResponse xmlns="http://soap.sforce.com/2005/09/outbound"><Ack>true</Ack></notificationsResponse>
+</soapenv:Body>
+</soapenv:Envelope>`
+
+// OutboundMessage the notifications of an Outbound Message workflow action
+type OutboundMessage struct {
+	OrganizationId string `xml:"OrganizationId"`
+	ActionId       string `xml:"ActionId"`
+	// SessionId a session for calling back into salesforce, when the action is configured to send one
+	SessionId     string                 `xml:"SessionId"`
+	EnterpriseUrl string                 `xml:"EnterpriseUrl"`
+	PartnerUrl    string                 `xml:"PartnerUrl"`
+	Notifications []OutboundNotification `xml:"Notification"`
+}
+
+// OutboundNotification a record sent by an outbound message
+type OutboundNotification struct {
+	// Id the notification id, notifications may be delivered more than once
+	Id      string          `xml:"Id"`
+	SObject OutboundSObject `xml:"sObject"`
+}
+
+// OutboundSObject the fields of a record selected for the outbound message
+type OutboundSObject struct {
+	// Type the object, e.g. Account
+	Type string
+	// Fields the field values by name, fields sent as nil are omitted
+	Fields map[string]string
+	// raw the sObject xml, for DecodeOutboundSObject
+	raw []byte
+}
+
+func (o *OutboundSObject) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var sObject struct {
+		Type   string `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+		Inner  []byte `xml:",innerxml"`
+		Fields []struct {
+			XMLName xml.Name
+			Nil     bool   `xml:"http://www.w3.org/2001/XMLSchema-instance nil,attr"`
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	}
+	if err := d.DecodeElement(&sObject, &start); err != nil {
+		return err
+	}
+	// the type is prefixed by the namespace of the enterprise wsdl, e.g. sf:Account
+	o.Type = sObject.Type[strings.Index(sObject.Type, ":")+1:]
+	o.Fields = make(map[string]string, len(sObject.Fields))
+	for _, f := range sObject.Fields {
+		if !f.Nil {
+			o.Fields[f.XMLName.Local] = f.Value
+		}
+	}
+	o.raw = []byte("<sObject>" + string(sObject.Inner) + "</sObject>")
+	return nil
+}
+
+// DecodeOutboundSObject decodes the fields of an outbound message record into E by xml field names, e.g. a struct
+// with `xml:"Name"` tags, so numbers and booleans are parsed
+func DecodeOutboundSObject[E any](o OutboundSObject) (*E, error) {
+	var e E
+	if err := xml.Unmarshal(o.raw, &e); err != nil {
+		return nil, fmt.Errorf("unable to decode outbound message %s: %w", o.Type, err)
+	}
+	return &e, nil
+}
+
+// OutboundMessageHandler an http.Handler receiving Outbound Messages, it checks they come from the expected org and
+// acknowledges them once handled. Salesforce retries messages which aren't acknowledged, for up to 24 hours
+type OutboundMessageHandler struct {
+	orgId   string
+	handler func(ctx context.Context, m OutboundMessage) error
+	// clientCAs when set, the client certificate must be signed by one of them
+	clientCAs *x509.CertPool
+}
+
+// NewOutboundMessageHandler creates an http.Handler passing messages from the org orgId to handler, the message is
+// acknowledged when handler returns nil, otherwise salesforce retries it
+func NewOutboundMessageHandler(orgId string, handler func(ctx context.Context, m OutboundMessage) error) (*OutboundMessageHandler, error) {
+	if !ValidId(orgId) {
+		return nil, fmt.Errorf("invalid orgId %q", orgId)
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("handler needs to be provided")
+	}
+	return &OutboundMessageHandler{
+		orgId:   orgId,
+		handler: handler,
+	}, nil
+}
+
+// SetClientCAs requires a client certificate signed by one of pool, e.g. the salesforce client certificate the
+// outbound message is configured to send. The server must request client certificates, e.g. tls.RequestClientCert
+func (o *OutboundMessageHandler) SetClientCAs(pool *x509.CertPool) *OutboundMessageHandler {
+	o.clientCAs = pool
+	return o
+}
+
+func (o *OutboundMessageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := o.verifyClientCert(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	var envelope struct {
+		Body struct {
+			Notifications *OutboundMessage `xml:"notifications"`
+		} `xml:"Body"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(r.Body, outboundMessageMaxSize)).Decode(&envelope); err != nil {
+		http.Error(w, "invalid outbound message", http.StatusBadRequest)
+		return
+	}
+	m := envelope.Body.Notifications
+	if m == nil {
+		http.Error(w, "invalid outbound message", http.StatusBadRequest)
+		return
+	}
+	if !EqualIds(m.OrganizationId, o.orgId) {
+		http.Error(w, "unexpected organization", http.StatusForbidden)
+		return
+	}
+
+	if err := o.handler(r.Context(), *m); err != nil {
+		http.Error(w, "unable to handle outbound message", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	_, _ = io.WriteString(w, outboundMessageAck)
+}
+
+// verifyClientCert checks the client certificate chains to clientCAs, when set
+func (o *OutboundMessageHandler) verifyClientCert(r *http.Request) error {
+	if o.clientCAs == nil {
+		return nil
+	}
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return fmt.Errorf("client certificate required")
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range r.TLS.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := r.TLS.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         o.clientCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("invalid client certificate")
+	}
+	return nil
+}
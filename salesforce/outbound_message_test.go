@@ -0,0 +1,140 @@
+package salesforce
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const outboundMessageStub = `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+<soapenv:Body>
+<notifications xmlns="http://soap.sforce.com/2005/09/outbound">
+<OrganizationId>00Dxx0000001gEREAY</OrganizationId>
+<ActionId>04kxx0000000001AAA</ActionId>
+<SessionId xsi:nil="true"/>
+<EnterpriseUrl>https://org.my.salesforce.com/services/Soap/c/55.0/00Dxx0000001gER</EnterpriseUrl>
+<PartnerUrl>https://org.my.salesforce.com/services/Soap/u/55.0/00Dxx0000001gER</PartnerUrl>
+<Notification>
+<Id>04lxx0000000001AAA</Id>
+<sObject xsi:type="sf:Account" xmlns:sf="urn:sobject.enterprise.soap.sforce.com">
+<sf:Id>001xx000003DGb2AAG</sf:Id>
+<sf:Name>Acme</sf:Name>
+<sf:NumberOfEmployees>12</sf:NumberOfEmployees>
+<sf:Industry xsi:nil="true"/>
+</sObject>
+</Notification>
+</notifications>
+</soapenv:Body>
+</soapenv:Envelope>`
+
+type outboundAccountStub struct {
+	Id                string `xml:"Id"`
+	Name              string `xml:"Name"`
+	NumberOfEmployees int    `xml:"NumberOfEmployees"`
+}
+
+func TestOutboundMessageHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		orgId      string
+		method     string
+		body       string
+		handlerErr error
+		clientCAs  *x509.CertPool
+		wantStatus int
+		wantAck    bool
+	}{
+		{
+			name:       "valid message  handled and acknowledged",
+			orgId:      "00Dxx0000001gER",
+			method:     http.MethodPost,
+			body:       outboundMessageStub,
+			wantStatus: http.StatusOK,
+			wantAck:    true,
+		},
+		{
+			name:       "other org  forbidden",
+			orgId:      "00Dxx0000001gFF",
+			method:     http.MethodPost,
+			body:       outboundMessageStub,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "handler fails  not acknowledged",
+			orgId:      "00Dxx0000001gER",
+			method:     http.MethodPost,
+			body:       outboundMessageStub,
+			handlerErr: errors.New("failed"),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "not an outbound message  bad request",
+			orgId:      "00Dxx0000001gER",
+			method:     http.MethodPost,
+			body:       `<foo/>`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "get  method not allowed",
+			orgId:      "00Dxx0000001gER",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "client certificate required but not sent  forbidden",
+			orgId:      "00Dxx0000001gER",
+			method:     http.MethodPost,
+			body:       outboundMessageStub,
+			clientCAs:  x509.NewCertPool(),
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *OutboundMessage
+			h, err := NewOutboundMessageHandler(tt.orgId, func(ctx context.Context, m OutboundMessage) error {
+				got = &m
+				return tt.handlerErr
+			})
+			assert.NoError(t, err)
+			h.SetClientCAs(tt.clientCAs)
+
+			req := httptest.NewRequest(tt.method, "/outbound", strings.NewReader(tt.body))
+			req.TLS = &tls.ConnectionState{}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantAck, strings.Contains(rec.Body.String(), "<Ack>true</Ack>"))
+			if !tt.wantAck {
+				return
+			}
+			assert.Equal(t, "04kxx0000000001AAA", got.ActionId)
+			assert.Empty(t, got.SessionId)
+			if assert.Len(t, got.Notifications, 1) {
+				n := got.Notifications[0]
+				assert.Equal(t, "04lxx0000000001AAA", n.Id)
+				assert.Equal(t, "Account", n.SObject.Type)
+				assert.Equal(t, map[string]string{"Id": "001xx000003DGb2AAG", "Name": "Acme", "NumberOfEmployees": "12"}, n.SObject.Fields)
+
+				account, err := DecodeOutboundSObject[outboundAccountStub](n.SObject)
+				assert.NoError(t, err)
+				assert.Equal(t, &outboundAccountStub{Id: "001xx000003DGb2AAG", Name: "Acme", NumberOfEmployees: 12}, account)
+			}
+		})
+	}
+}
+
+func TestNewOutboundMessageHandler(t *testing.T) {
+	_, err := NewOutboundMessageHandler("not an id", func(context.Context, OutboundMessage) error { return nil })
+	assert.Error(t, err)
+	_, err = NewOutboundMessageHandler("00Dxx0000001gER", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,31 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// MasterRecordTypeId the id of the master record type, for objects without record types
+const MasterRecordTypeId = "012000000000000AAA"
+
+type picklistValuesResponse struct {
+	PicklistFieldValues map[string]PicklistField `json:"picklistFieldValues"`
+}
+
+// GetPicklistValues fetches the active values of every picklist field on object for recordTypeId via the UI API, keyed
+// by field name, e.g. to render valid options or validate input before a write
+func GetPicklistValues(ctx context.Context, h *RequestHelper, object, recordTypeId string) (map[string]PicklistField, error) {
+	if len(object) == 0 || len(recordTypeId) == 0 {
+		return nil, fmt.Errorf("object and record type id need to be provided")
+	}
+	reqUrl, err := h.dataUrl(ctx, "/ui-api/object-info/"+url.PathEscape(object)+"/picklist-values/"+url.PathEscape(recordTypeId))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := getJson[picklistValuesResponse](ctx, h, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	return resp.PicklistFieldValues, nil
+}
@@ -0,0 +1,72 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestGetPicklistValues(t *testing.T) {
+	tests := []struct {
+		name         string
+		client       *HttpClientMock
+		recordTypeId string
+		want         map[string]PicklistField
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "successful request  picklist values returned",
+			client: newHttpClientMock(&http.Response{Body: io.NopCloser(
+				bytes.NewReader([]byte(`{"eTag":"abc","picklistFieldValues":{"Industry":{"controllerValues":{},"defaultValue":null,"values":[{"attributes":null,"label":"Banking","validFor":[],"value":"Banking"}]}}}`))),
+				StatusCode: 200,
+			}, nil),
+			recordTypeId: MasterRecordTypeId,
+			want: map[string]PicklistField{
+				"Industry": {
+					ControllerValues: map[string]int{},
+					Values:           []PicklistValue{{Label: "Banking", Value: "Banking", ValidFor: []int{}}},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:         "no record type id  error returned",
+			client:       newHttpClientMock(nil, nil),
+			recordTypeId: "",
+			wantErr:      assert.Error,
+		},
+		{
+			name:         "404 status code  error returned",
+			client:       newHttpClientMock(&http.Response{Body: io.NopCloser(nil), StatusCode: 404}, nil),
+			recordTypeId: MasterRecordTypeId,
+			wantErr:      assert.Error,
+		},
+		{
+			name:         "http.Do() returns error  error returned",
+			client:       newHttpClientMock(&http.Response{Body: io.NopCloser(nil), StatusCode: 0}, fmt.Errorf("http client error")),
+			recordTypeId: MasterRecordTypeId,
+			wantErr:      assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewRequestHelper(tt.client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := GetPicklistValues(context.Background(), h, "Account", tt.recordTypeId)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			assert.True(t, got["Industry"].Contains("Banking"))
+			assert.False(t, got["Industry"].Contains("Retail"))
+			tt.client.AssertCalled(t, "Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.URL.String() == "https://org/services/data/v55.0/ui-api/object-info/Account/picklist-values/012000000000000AAA"
+			}))
+		})
+	}
+}
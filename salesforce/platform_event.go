@@ -0,0 +1,81 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PublishResult is the response from Salesforce for a published platform event
+type PublishResult struct {
+	// Id the id of the publish, not of a record, as events aren't stored as records
+	Id      string     `json:"id"`
+	Success bool       `json:"success"`
+	Errors  []ApiError `json:"errors"`
+}
+
+// PublishEvent publishes a platform event, e.g. Order_Shipped__e
+// - the payload is validated against the event's describe, fetched once per RequestHelper, returning a
+// ValidationError for unknown, read only or missing required fields before anything is published
+// - a PublishResult is returned even when salesforce reports the publish failed, along with an error
+func PublishEvent(ctx context.Context, h *RequestHelper, eventApiName string, payload any) (*PublishResult, error) {
+	if !strings.HasSuffix(eventApiName, "__e") {
+		return nil, fmt.Errorf("invalid platform event %q: api name must end in __e", eventApiName)
+	}
+	fields, err := jsonFields(payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	if err = validateEvent(ctx, h, eventApiName, fields); err != nil {
+		return nil, err
+	}
+
+	reqUrl, err := h.dataUrl(ctx, "/sobjects/"+url.PathEscape(eventApiName))
+	if err != nil {
+		return nil, err
+	}
+	reqBody, err := h.marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	req, err := h.newRequest(ctx, http.MethodPost, reqUrl, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse response body: %w", err)
+	}
+	var result *PublishResult
+	if err = h.unmarshal(resBody, &result); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return result, fmt.Errorf("salesforce returns a failure result: %s", resBody)
+	}
+	return result, nil
+}
+
+// validateEvent checks the fields of an event payload against the event's describe, as a record being created
+func validateEvent(ctx context.Context, h *RequestHelper, eventApiName string, fields map[string]json.RawMessage) error {
+	d, err := h.cachedDescribe(ctx, eventApiName)
+	if err != nil {
+		return fmt.Errorf("unable to describe platform event %s: %w", eventApiName, err)
+	}
+	return validateFields(d, fields, false)
+}
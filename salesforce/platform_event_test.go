@@ -0,0 +1,102 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const orderShippedDescribe = `{"name":"Order_Shipped__e","fields":[
+	{"name":"ReplayId","type":"string","nillable":true,"createable":false},
+	{"name":"Order_Number__c","type":"string","nillable":false,"createable":true},
+	{"name":"Carrier__c","type":"string","nillable":true,"createable":true}
+]}`
+
+type orderShippedStub struct {
+	OrderNumber *string `json:"Order_Number__c,omitempty"`
+	Carrier     string  `json:"Carrier__c,omitempty"`
+	ReplayId    string  `json:"ReplayId,omitempty"`
+	Unknown     string  `json:"Unknown__c,omitempty"`
+}
+
+func TestPublishEvent(t *testing.T) {
+	orderNumber := "ORD-1"
+
+	tests := []struct {
+		name          string
+		event         string
+		payload       orderShippedStub
+		publishResp   string
+		want          *PublishResult
+		wantPublished bool
+		wantFields    []string
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "valid payload  event published",
+			event:         "Order_Shipped__e",
+			payload:       orderShippedStub{OrderNumber: &orderNumber, Carrier: "DHL"},
+			publishResp:   `{"id":"e00xx0000000001","success":true,"errors":[]}`,
+			want:          &PublishResult{Id: "e00xx0000000001", Success: true, Errors: []ApiError{}},
+			wantPublished: true,
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "publish fails  result and error returned",
+			event:         "Order_Shipped__e",
+			payload:       orderShippedStub{OrderNumber: &orderNumber},
+			publishResp:   `{"id":"","success":false,"errors":[{"statusCode":"LIMIT_EXCEEDED","message":"limit"}]}`,
+			want:          &PublishResult{Success: false, Errors: []ApiError{{StatusCode: "LIMIT_EXCEEDED", Message: "limit"}}},
+			wantPublished: true,
+			wantErr:       assert.Error,
+		},
+		{
+			name:       "invalid payload  ValidationError returned without publishing",
+			event:      "Order_Shipped__e",
+			payload:    orderShippedStub{ReplayId: "1", Unknown: "x"},
+			wantFields: []string{"Order_Number__c", "ReplayId", "Unknown__c"},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorAs(t, err, &ValidationError{}, i...)
+			},
+		},
+		{
+			name:    "not an event  error returned",
+			event:   "Account",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return strings.HasSuffix(req.URL.Path, "/describe")
+			})).Return(newResponse(200, orderShippedDescribe), nil)
+			client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.Method == http.MethodPost && req.URL.String() == "https://org/services/data/v55.0/sobjects/Order_Shipped__e"
+			})).Return(newResponse(201, tt.publishResp), nil)
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := PublishEvent(context.Background(), h, tt.event, tt.payload)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+			if tt.wantPublished {
+				client.AssertCalled(t, "Do", mock.MatchedBy(func(req *http.Request) bool { return req.Method == http.MethodPost }))
+			} else {
+				client.AssertNotCalled(t, "Do", mock.MatchedBy(func(req *http.Request) bool { return req.Method == http.MethodPost }))
+			}
+
+			var validationErr ValidationError
+			if errors.As(err, &validationErr) {
+				var fields []string
+				for _, fe := range validationErr.Errors {
+					fields = append(fields, fe.Field)
+				}
+				assert.Equal(t, tt.wantFields, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Polymorphic a polymorphic lookup field, e.g. Task.Who, whose related record can be one of several objects. The
+// object is read from attributes.type and the raw json kept to decode once it's known
+//
+//	type Task struct {
+//		Who salesforce.Polymorphic `json:"Who"`
+//	}
+//
+//	switch task.Who.Type() {
+//	case "Contact":
+//		contact, err := salesforce.DecodePolymorphic[Contact](task.Who)
+//	}
+type Polymorphic struct {
+	Attributes Attributes
+	Raw        json.RawMessage
+}
+
+func (p *Polymorphic) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*p = Polymorphic{}
+		return nil
+	}
+	var record struct {
+		Attributes Attributes `json:"attributes"`
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return fmt.Errorf("unable to parse polymorphic field: %w", err)
+	}
+	p.Attributes = record.Attributes
+	p.Raw = append(p.Raw[:0], data...)
+	return nil
+}
+
+func (p Polymorphic) MarshalJSON() ([]byte, error) {
+	if p.IsNull() {
+		return []byte("null"), nil
+	}
+	return p.Raw, nil
+}
+
+// Type the object of the related record, e.g. Contact or Lead, empty when the lookup is null
+func (p Polymorphic) Type() string {
+	return p.Attributes.Type
+}
+
+// IsNull whether the lookup is empty
+func (p Polymorphic) IsNull() bool {
+	return len(p.Raw) == 0
+}
+
+// DecodePolymorphic decodes the related record as E, callers should check Type first
+func DecodePolymorphic[E any](p Polymorphic) (*E, error) {
+	if p.IsNull() {
+		return nil, nil
+	}
+	var record *E
+	if err := json.Unmarshal(p.Raw, &record); err != nil {
+		return nil, fmt.Errorf("unable to parse %s record: %w", p.Type(), err)
+	}
+	return record, nil
+}
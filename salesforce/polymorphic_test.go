@@ -0,0 +1,52 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPolymorphic(t *testing.T) {
+	type contact struct {
+		Attributes Attributes `json:"attributes"`
+		Email      string     `json:"Email"`
+	}
+	type task struct {
+		Who Polymorphic `json:"Who"`
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantType string
+		want     *contact
+	}{
+		{
+			name:     "contact  decoded as contact",
+			body:     `{"Who":{"attributes":{"type":"Contact","url":"/services/data/v55.0/sobjects/Contact/003"},"Email":"a@example.com"}}`,
+			wantType: "Contact",
+			want:     &contact{Attributes: Attributes{Type: "Contact", Url: "/services/data/v55.0/sobjects/Contact/003"}, Email: "a@example.com"},
+		},
+		{
+			name:     "null lookup  nil returned",
+			body:     `{"Who":null}`,
+			wantType: "",
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got task
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &got))
+			assert.Equal(t, tt.wantType, got.Who.Type())
+
+			record, err := DecodePolymorphic[contact](got.Who)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, record)
+
+			body, err := json.Marshal(got)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.body, string(body))
+		})
+	}
+}
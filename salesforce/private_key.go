@@ -0,0 +1,186 @@
+package salesforce
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"hash"
+)
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHmacWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHmacWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHmacWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHmacWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// ErrPrivateKeyPassphrase returned when an encrypted private key can't be decrypted with the passphrase provided
+var ErrPrivateKeyPassphrase = errors.New("private key passphrase missing or incorrect")
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     algorithmIdentifier
+	EncryptedData []byte
+}
+
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc algorithmIdentifier
+	EncryptionScheme  algorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                 `asn1:"optional"`
+	Prf            algorithmIdentifier `asn1:"optional"`
+}
+
+// parsePrivateKey parses a PEM encoded PKCS#1, PKCS#8 or SEC 1 (EC) private key. Encrypted PKCS#8 keys
+// (ENCRYPTED PRIVATE KEY, PBES2 with PBKDF2 and AES/3DES-CBC) are decrypted with passphrase.
+func parsePrivateKey(pemBytes []byte, passphrase string) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "ENCRYPTED PRIVATE KEY":
+		if len(passphrase) == 0 {
+			return nil, ErrPrivateKeyPassphrase
+		}
+		der, err := decryptPKCS8(block.Bytes, []byte(passphrase))
+		if err != nil {
+			return nil, err
+		}
+		key, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			// an incorrect passphrase usually yields garbage with valid padding rather than a padding error
+			return nil, ErrPrivateKeyPassphrase
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type: %s", block.Type)
+}
+
+func decryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("unable to parse encrypted private key: %w", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported private key encryption: %s, only PBES2 is supported", info.Algorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("unable to parse private key encryption parameters: %w", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported private key derivation: %s, only PBKDF2 is supported", params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("unable to parse private key derivation parameters: %w", err)
+	}
+	prf, err := pbkdf2Prf(kdf.Prf.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("unable to parse private key encryption iv: %w", err)
+	}
+
+	scheme := params.EncryptionScheme.Algorithm
+	keyLen, newCipher, err := pbes2Cipher(scheme)
+	if err != nil {
+		return nil, err
+	}
+	key := pbkdf2.Key(passphrase, kdf.Salt, kdf.IterationCount, keyLen, prf)
+	block, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() || len(info.EncryptedData)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("invalid encrypted private key")
+	}
+
+	out := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, info.EncryptedData)
+	return unpad(out, block.BlockSize())
+}
+
+func pbkdf2Prf(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case len(oid) == 0, oid.Equal(oidHmacWithSHA1):
+		return sha1.New, nil
+	case oid.Equal(oidHmacWithSHA256):
+		return sha256.New, nil
+	case oid.Equal(oidHmacWithSHA384):
+		return sha512.New384, nil
+	case oid.Equal(oidHmacWithSHA512):
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("unsupported private key derivation prf: %s", oid)
+}
+
+func pbes2Cipher(oid asn1.ObjectIdentifier) (int, func([]byte) (cipher.Block, error), error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return 16, aes.NewCipher, nil
+	case oid.Equal(oidAES192CBC):
+		return 24, aes.NewCipher, nil
+	case oid.Equal(oidAES256CBC):
+		return 32, aes.NewCipher, nil
+	case oid.Equal(oidDESEDE3CBC):
+		return 24, des.NewTripleDESCipher, nil
+	}
+	return 0, nil, fmt.Errorf("unsupported private key cipher: %s", oid)
+}
+
+// unpad removes PKCS#7 padding, a bad pad almost always means the wrong passphrase
+func unpad(b []byte, blockSize int) ([]byte, error) {
+	if len(b) == 0 {
+		return nil, ErrPrivateKeyPassphrase
+	}
+	n := int(b[len(b)-1])
+	if n == 0 || n > blockSize || n > len(b) {
+		return nil, ErrPrivateKeyPassphrase
+	}
+	for _, p := range b[len(b)-n:] {
+		if int(p) != n {
+			return nil, ErrPrivateKeyPassphrase
+		}
+	}
+	return b[:len(b)-n], nil
+}
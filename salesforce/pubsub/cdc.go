@@ -0,0 +1,173 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ChangeEventsTopic the channel of change events of every object selected for Change Data Capture
+const ChangeEventsTopic = "/data/ChangeEvents"
+
+// ChangeType the kind of change a change event records
+type ChangeType string
+
+const (
+	ChangeCreate   ChangeType = "CREATE"
+	ChangeUpdate   ChangeType = "UPDATE"
+	ChangeDelete   ChangeType = "DELETE"
+	ChangeUndelete ChangeType = "UNDELETE"
+	// ChangeGapCreate etc. gap events, sent in place of change events that couldn't be generated, carry only the
+	// header and the affected record ids, the records need to be fetched to get their changes
+	ChangeGapCreate   ChangeType = "GAP_CREATE"
+	ChangeGapUpdate   ChangeType = "GAP_UPDATE"
+	ChangeGapDelete   ChangeType = "GAP_DELETE"
+	ChangeGapUndelete ChangeType = "GAP_UNDELETE"
+	// ChangeGapOverflow sent in place of the change events of a transaction with too many changes, the records of
+	// EntityName changed during the transaction need to be fetched
+	ChangeGapOverflow ChangeType = "GAP_OVERFLOW"
+)
+
+// IsGap whether the event is a gap event, without the record fields
+func (t ChangeType) IsGap() bool {
+	return strings.HasPrefix(string(t), "GAP_")
+}
+
+// ChangeEventHeader the header of a change event
+type ChangeEventHeader struct {
+	EntityName      string     `json:"entityName"`
+	RecordIds       []string   `json:"recordIds"`
+	ChangeType      ChangeType `json:"changeType"`
+	ChangeOrigin    string     `json:"changeOrigin"`
+	TransactionKey  string     `json:"transactionKey"`
+	SequenceNumber  int        `json:"sequenceNumber"`
+	CommitTimestamp int64      `json:"commitTimestamp"`
+	CommitNumber    int64      `json:"commitNumber"`
+	CommitUser      string     `json:"commitUser"`
+	// ChangedFields the fields set by the change, with compound fields as e.g. BillingAddress.City
+	ChangedFields []string `json:"changedFields"`
+	// NulledFields the fields set to null by the change
+	NulledFields []string `json:"nulledFields"`
+	// DiffFields the long text fields sent as a diff of their previous value
+	DiffFields []string `json:"diffFields"`
+}
+
+// ChangeEvent a decoded change event
+type ChangeEvent struct {
+	Event
+	Header ChangeEventHeader
+	// Fields the changed record fields, without ChangeEventHeader
+	Fields map[string]any
+}
+
+// Decode decodes the record fields into v by json field names, e.g. a struct with json tags
+func (e ChangeEvent) Decode(v any) error {
+	b, err := json.Marshal(e.Fields)
+	if err != nil {
+		return fmt.Errorf("unable to decode salesforce change event %s: %w", e.Id, err)
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("unable to decode salesforce change event %s: %w", e.Id, err)
+	}
+	return nil
+}
+
+// ChangeHandler handles a change event, returning an error stops the subscription
+type ChangeHandler func(ctx context.Context, e ChangeEvent) error
+
+// ChangeConsumer subscribes to change events and dispatches each to the handler of its object
+type ChangeConsumer struct {
+	client   *Client
+	handlers map[string]ChangeHandler
+	fallback ChangeHandler
+}
+
+func NewChangeConsumer(client *Client) *ChangeConsumer {
+	return &ChangeConsumer{
+		client:   client,
+		handlers: map[string]ChangeHandler{},
+	}
+}
+
+// Handle sets the handler of the change events of object, e.g. Account or Order__c
+func (c *ChangeConsumer) Handle(object string, handler ChangeHandler) *ChangeConsumer {
+	c.handlers[strings.ToLower(object)] = handler
+	return c
+}
+
+// HandleDefault sets the handler of the change events of objects without a handler, which are otherwise skipped
+func (c *ChangeConsumer) HandleDefault(handler ChangeHandler) *ChangeConsumer {
+	c.fallback = handler
+	return c
+}
+
+// OnChange sets the handler of the change events of object, with the record fields decoded into E. Gap events have
+// no record fields, so E is its zero value
+func OnChange[E any](c *ChangeConsumer, object string, fn func(ctx context.Context, e ChangeEvent, record E) error) *ChangeConsumer {
+	return c.Handle(object, func(ctx context.Context, e ChangeEvent) error {
+		var record E
+		if !e.Header.ChangeType.IsGap() {
+			if err := e.Decode(&record); err != nil {
+				return err
+			}
+		}
+		return fn(ctx, e, record)
+	})
+}
+
+// Subscribe subscribes to change events as Client.Subscribe, defaulting the topic to ChangeEventsTopic. Use a
+// per-object channel, e.g. /data/AccountChangeEvent, or a custom channel to receive fewer events
+func (c *ChangeConsumer) Subscribe(ctx context.Context, s Subscription) error {
+	if len(s.Topic) == 0 {
+		s.Topic = ChangeEventsTopic
+	}
+	return c.client.Subscribe(ctx, s, c.dispatch)
+}
+
+// Run subscribes to change events as Client.Run, reconnecting when the connection fails, defaulting the topic to
+// ChangeEventsTopic
+func (c *ChangeConsumer) Run(ctx context.Context, s Subscription) error {
+	if len(s.Topic) == 0 {
+		s.Topic = ChangeEventsTopic
+	}
+	return c.client.Run(ctx, s, c.dispatch)
+}
+
+// dispatch decodes a change event and passes it to the handler of its object
+func (c *ChangeConsumer) dispatch(ctx context.Context, e Event) error {
+	ce, err := c.decode(ctx, e)
+	if err != nil {
+		return err
+	}
+	handler, ok := c.handlers[strings.ToLower(ce.Header.EntityName)]
+	if !ok {
+		handler = c.fallback
+	}
+	if handler == nil {
+		return nil
+	}
+	return handler(ctx, ce)
+}
+
+func (c *ChangeConsumer) decode(ctx context.Context, e Event) (ChangeEvent, error) {
+	fields, err := c.client.Decode(ctx, e)
+	if err != nil {
+		return ChangeEvent{}, err
+	}
+	header, ok := fields["ChangeEventHeader"]
+	if !ok {
+		return ChangeEvent{}, fmt.Errorf("salesforce event %s is not a change event", e.Id)
+	}
+	delete(fields, "ChangeEventHeader")
+
+	ce := ChangeEvent{Event: e, Fields: fields}
+	b, err := json.Marshal(header)
+	if err != nil {
+		return ChangeEvent{}, fmt.Errorf("unable to decode salesforce change event %s header: %w", e.Id, err)
+	}
+	if err = json.Unmarshal(b, &ce.Header); err != nil {
+		return ChangeEvent{}, fmt.Errorf("unable to decode salesforce change event %s header: %w", e.Id, err)
+	}
+	return ce, nil
+}
@@ -0,0 +1,92 @@
+package pubsub
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type accountStub struct {
+	Name           *string `json:"Name"`
+	BillingAddress *struct {
+		City *string `json:"City"`
+	} `json:"BillingAddress"`
+}
+
+func newChangeConsumerStub(t *testing.T) (*ChangeConsumer, *streamStub) {
+	e := newAccountChangeEvent(t)
+	stream := &streamStub{responses: []*fetchResponse{{
+		Events: []consumerEvent{{Event: producerEvent{Id: e.Id, SchemaId: e.SchemaId, Payload: e.Payload}, ReplayId: []byte{1}}},
+	}}}
+	calls := 0
+	c := newSchemaClientStub(&calls)
+	c.openSubscribe = newClientStub(stream).openSubscribe
+	return NewChangeConsumer(c), stream
+}
+
+func TestChangeConsumer_Subscribe(t *testing.T) {
+	tests := []struct {
+		name     string
+		object   string
+		fallback bool
+		wantName string
+		wantAny  bool
+	}{
+		{
+			name:     "object handled  typed callback",
+			object:   "account",
+			wantName: "Acme",
+		},
+		{
+			name:     "object not handled  default handler",
+			object:   "Contact",
+			fallback: true,
+			wantAny:  true,
+		},
+		{
+			name:   "object not handled  skipped",
+			object: "Contact",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, stream := newChangeConsumerStub(t)
+			var gotName string
+			var gotHeader ChangeEventHeader
+			OnChange(c, tt.object, func(ctx context.Context, e ChangeEvent, record accountStub) error {
+				gotHeader = e.Header
+				gotName = *record.Name
+				return nil
+			})
+			gotAny := false
+			if tt.fallback {
+				c.HandleDefault(func(ctx context.Context, e ChangeEvent) error {
+					gotAny = true
+					assert.NotContains(t, e.Fields, "ChangeEventHeader")
+					return nil
+				})
+			}
+
+			_ = c.Subscribe(context.Background(), Subscription{})
+			assert.Equal(t, ChangeEventsTopic, stream.sent[0].TopicName)
+			assert.Equal(t, tt.wantName, gotName)
+			assert.Equal(t, tt.wantAny, gotAny)
+			if len(tt.wantName) > 0 {
+				assert.Equal(t, ChangeEventHeader{
+					EntityName:      "Account",
+					RecordIds:       []string{"001xx000003DGb2AAG"},
+					ChangeType:      ChangeUpdate,
+					CommitTimestamp: 1700000000000,
+					ChangedFields:   []string{"Name", "Industry", "BillingAddress.City"},
+					NulledFields:    []string{"Industry"},
+				}, gotHeader)
+			}
+		})
+	}
+}
+
+func TestChangeType_IsGap(t *testing.T) {
+	assert.True(t, ChangeGapOverflow.IsGap())
+	assert.True(t, ChangeGapUpdate.IsGap())
+	assert.False(t, ChangeUpdate.IsGap())
+}
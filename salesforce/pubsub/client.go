@@ -0,0 +1,163 @@
+package pubsub
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/ellogroup/ello-golang-salesforce/salesforce"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"sync"
+	"time"
+)
+
+// DefaultEndpoint the global Pub/Sub API endpoint
+const DefaultEndpoint = "api.pubsub.salesforce.com:7443"
+
+var validate = validator.New()
+
+// Params the Pub/Sub API client configuration
+type Params struct {
+	// Token the access token sent with each call, e.g. salesforce.TokenCache
+	Token salesforce.TokenGetter `validate:"required"`
+	// InstanceUrl the org's instance url, defaults to the token's instance url when Token is a
+	// salesforce.InstanceTokenGetter
+	InstanceUrl string
+	// TenantId the org id, e.g. UserInfo.OrganizationId
+	TenantId string `validate:"required"`
+	// Endpoint the Pub/Sub API endpoint, defaults to DefaultEndpoint
+	Endpoint string
+	// Backoff the backoff between the reconnects of Run, see salesforce.ReconnectBackOff for the defaults of its zero
+	// fields
+	Backoff salesforce.BackoffPolicy
+	// Metrics records the subscription metrics, e.g. salesforce.MetricSubscriptionLag
+	Metrics salesforce.Metrics
+}
+
+// Client a Salesforce Pub/Sub API client, subscribing to platform event and change data capture channels
+type Client struct {
+	conn        *grpc.ClientConn
+	token       salesforce.TokenGetter
+	instanceUrl func(ctx context.Context) (string, error)
+	tenantId    string
+	backoff     salesforce.BackoffPolicy
+	metrics     salesforce.Metrics
+	// openSubscribe opens a Subscribe stream, replaced in tests
+	openSubscribe func(ctx context.Context) (subscribeStream, error)
+	// invoke sends a unary call, replaced in tests
+	invoke func(ctx context.Context, method string, req, resp message) error
+	// schemas the parsed schema of each schema id, schemas don't change once published
+	schemas sync.Map
+}
+
+// NewClient creates a Pub/Sub API client, the connection is established when first used. Close the client when done
+func NewClient(p Params) (*Client, error) {
+	if err := validate.Struct(p); err != nil {
+		return nil, err
+	}
+	instanceUrlGetter, ok := p.Token.(salesforce.InstanceUrlGetter)
+	if !ok && len(p.InstanceUrl) == 0 {
+		return nil, fmt.Errorf("instanceUrl needs to be provided, when token doesn't supply one")
+	}
+	if len(p.Endpoint) == 0 {
+		p.Endpoint = DefaultEndpoint
+	}
+
+	conn, err := grpc.NewClient(p.Endpoint, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create salesforce pubsub client: %w", err)
+	}
+	c := &Client{
+		conn:     conn,
+		token:    p.Token,
+		tenantId: p.TenantId,
+		backoff:  p.Backoff,
+		metrics:  p.Metrics,
+		instanceUrl: func(context.Context) (string, error) {
+			return p.InstanceUrl, nil
+		},
+	}
+	if len(p.InstanceUrl) == 0 {
+		c.instanceUrl = instanceUrlGetter.InstanceUrl
+	}
+	c.openSubscribe = c.grpcSubscribe
+	c.invoke = c.grpcInvoke
+	return c, nil
+}
+
+// count counts a subscription metric, when metrics are recorded
+func (c *Client) count(name, topic string) {
+	if c.metrics != nil {
+		c.metrics.Count(name, 1, map[string]string{"subscription": topic})
+	}
+}
+
+// timing times a subscription metric, when metrics are recorded
+func (c *Client) timing(name, topic string, d time.Duration) {
+	if c.metrics != nil {
+		c.metrics.Timing(name, d, map[string]string{"subscription": topic})
+	}
+}
+
+// Close closes the connection to the Pub/Sub API
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// withAuth adds the auth metadata the Pub/Sub API requires to ctx
+func (c *Client) withAuth(ctx context.Context) (context.Context, error) {
+	token, err := c.token.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get salesforce token: %w", err)
+	}
+	instanceUrl, err := c.instanceUrl(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get salesforce instance url: %w", err)
+	}
+	return metadata.AppendToOutgoingContext(ctx, "accesstoken", token, "instanceurl", instanceUrl, "tenantid", c.tenantId), nil
+}
+
+// subscribeStream a bidirectional Subscribe stream
+type subscribeStream interface {
+	Send(req *fetchRequest) error
+	Recv() (*fetchResponse, error)
+}
+
+type grpcSubscribeStream struct {
+	grpc.ClientStream
+}
+
+func (s grpcSubscribeStream) Send(req *fetchRequest) error {
+	return s.SendMsg(req)
+}
+
+func (s grpcSubscribeStream) Recv() (*fetchResponse, error) {
+	resp := &fetchResponse{}
+	if err := s.RecvMsg(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) grpcSubscribe(ctx context.Context) (subscribeStream, error) {
+	ctx, err := c.withAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	desc := &grpc.StreamDesc{StreamName: "Subscribe", ServerStreams: true, ClientStreams: true}
+	stream, err := c.conn.NewStream(ctx, desc, "/eventbus.v1.PubSub/Subscribe", grpc.ForceCodec(codec{}))
+	if err != nil {
+		return nil, err
+	}
+	return grpcSubscribeStream{stream}, nil
+}
+
+func (c *Client) grpcInvoke(ctx context.Context, method string, req, resp message) error {
+	ctx, err := c.withAuth(ctx)
+	if err != nil {
+		return err
+	}
+	return c.conn.Invoke(ctx, "/eventbus.v1.PubSub/"+method, req, resp, grpc.ForceCodec(codec{}))
+}
@@ -0,0 +1,113 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ellogroup/ello-golang-salesforce/salesforce"
+	"time"
+)
+
+// changeEventHeaderBitmaps the ChangeEventHeader fields which are bitmaps of field indexes
+var changeEventHeaderBitmaps = []string{"changedFields", "nulledFields", "diffFields"}
+
+// Decode decodes an event's Avro payload with its schema, which is fetched once per schema id. Nullable fields are
+// their value or nil, and the changedFields, nulledFields and diffFields of a change event's ChangeEventHeader are
+// mapped from bitmaps to field names
+func (c *Client) Decode(ctx context.Context, e Event) (map[string]any, error) {
+	s, err := c.schema(ctx, e.SchemaId)
+	if err != nil {
+		return nil, err
+	}
+	record, err := s.decode(e.Payload)
+	if err != nil {
+		c.count(salesforce.MetricSubscriptionDecodeFailure, e.Topic)
+		return nil, fmt.Errorf("unable to decode salesforce event %s: %w", e.Id, err)
+	}
+
+	header, ok := record["ChangeEventHeader"].(map[string]any)
+	if !ok {
+		c.recordLag(e.Topic, record["CreatedDate"])
+		return record, nil
+	}
+	c.recordLag(e.Topic, header["commitTimestamp"])
+	for _, key := range changeEventHeaderBitmaps {
+		bitmaps, ok := header[key].([]any)
+		if !ok {
+			continue
+		}
+		names, err := s.changedFieldNames(bitmaps)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode salesforce event %s %s: %w", e.Id, key, err)
+		}
+		header[key] = names
+	}
+	return record, nil
+}
+
+// recordLag records the time since an event was published, from its CreatedDate or change event commitTimestamp, as
+// epoch milliseconds or a timestamp-millis time
+func (c *Client) recordLag(topic string, published any) {
+	if c.metrics == nil {
+		return
+	}
+	var at time.Time
+	switch p := published.(type) {
+	case int64:
+		at = time.UnixMilli(p)
+	case time.Time:
+		at = p
+	default:
+		return
+	}
+	c.metrics.Timing(salesforce.MetricSubscriptionLag, time.Since(at), map[string]string{"subscription": topic})
+}
+
+// DecodeInto decodes an event as Decode, into v by json field names, e.g. a struct with json tags
+func (c *Client) DecodeInto(ctx context.Context, e Event, v any) error {
+	record, err := c.Decode(ctx, e)
+	if err != nil {
+		return err
+	}
+	return decodeRecord(e, record, v)
+}
+
+// decodeRecord decodes a decoded event into v by json field names
+func decodeRecord(e Event, record map[string]any, v any) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("unable to decode salesforce event %s: %w", e.Id, err)
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("unable to decode salesforce event %s: %w", e.Id, err)
+	}
+	return nil
+}
+
+// TypedHandler handles an event decoded into the type registered for its channel, v is a pointer to it, e.g.
+// *OrderShipped
+type TypedHandler func(ctx context.Context, e Event, v any) error
+
+// Typed a Handler decoding each event into the type registered for its topic in r, or for a change event received on
+// a multi-object channel, e.g. /data/ChangeEvents, the type registered for its object's channel. An event without a
+// registered type stops the subscription
+func Typed(c *Client, r *salesforce.EventRegistry, fn TypedHandler) Handler {
+	return func(ctx context.Context, e Event) error {
+		record, err := c.Decode(ctx, e)
+		if err != nil {
+			return err
+		}
+		entityName := ""
+		if header, ok := record["ChangeEventHeader"].(map[string]any); ok {
+			entityName, _ = header["entityName"].(string)
+		}
+		v, err := r.NewForEvent(e.Topic, entityName)
+		if err != nil {
+			return err
+		}
+		if err = decodeRecord(e, record, v); err != nil {
+			return err
+		}
+		return fn(ctx, e, v)
+	}
+}
@@ -0,0 +1,149 @@
+package pubsub
+
+import (
+	"context"
+	"github.com/ellogroup/ello-golang-salesforce/salesforce"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const accountChangeEventSchema = `{
+	"type": "record", "name": "AccountChangeEvent", "namespace": "com.sforce.eventbus",
+	"fields": [
+		{"name": "ChangeEventHeader", "type": {"type": "record", "name": "ChangeEventHeader", "fields": [
+			{"name": "entityName", "type": "string"},
+			{"name": "recordIds", "type": {"type": "array", "items": "string"}},
+			{"name": "changeType", "type": {"type": "enum", "name": "ChangeType", "symbols": ["CREATE", "UPDATE", "DELETE"]}},
+			{"name": "commitTimestamp", "type": "long"},
+			{"name": "changedFields", "type": {"type": "array", "items": "string"}},
+			{"name": "nulledFields", "type": {"type": "array", "items": "string"}},
+			{"name": "diffFields", "type": {"type": "array", "items": "string"}}
+		]}},
+		{"name": "Name", "type": ["null", "string"], "default": null},
+		{"name": "BillingAddress", "type": ["null", {"type": "record", "name": "Address", "fields": [
+			{"name": "Street", "type": ["null", "string"], "default": null},
+			{"name": "City", "type": ["null", "string"], "default": null}
+		]}], "default": null},
+		{"name": "Industry", "type": ["null", "string"], "default": null}
+	]
+}`
+
+type accountChangeEventStub struct {
+	ChangeEventHeader struct {
+		EntityName    string   `json:"entityName"`
+		ChangeType    string   `json:"changeType"`
+		RecordIds     []string `json:"recordIds"`
+		ChangedFields []string `json:"changedFields"`
+		NulledFields  []string `json:"nulledFields"`
+	}
+	Name           *string `json:"Name"`
+	BillingAddress *struct {
+		City *string `json:"City"`
+	} `json:"BillingAddress"`
+	Industry *string `json:"Industry"`
+}
+
+func newAccountChangeEvent(t *testing.T) Event {
+	codec, err := goavro.NewCodec(accountChangeEventSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := codec.BinaryFromNative(nil, map[string]any{
+		"ChangeEventHeader": map[string]any{
+			"entityName":      "Account",
+			"recordIds":       []any{"001xx000003DGb2AAG"},
+			"changeType":      "UPDATE",
+			"commitTimestamp": int64(1700000000000),
+			"changedFields":   []any{"0xA", "2-0x2"},
+			"nulledFields":    []any{"0x8"},
+			"diffFields":      []any{},
+		},
+		"Name":           goavro.Union("string", "Acme"),
+		"BillingAddress": goavro.Union("com.sforce.eventbus.Address", map[string]any{"Street": nil, "City": goavro.Union("string", "Leeds")}),
+		"Industry":       nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Event{Id: "e-1", SchemaId: "schema-1", Payload: payload}
+}
+
+func newSchemaClientStub(calls *int) *Client {
+	return &Client{invoke: func(ctx context.Context, method string, req, resp message) error {
+		*calls++
+		resp.(*schemaInfo).SchemaJson = accountChangeEventSchema
+		return nil
+	}}
+}
+
+func TestClient_Decode(t *testing.T) {
+	calls := 0
+	c := newSchemaClientStub(&calls)
+	e := newAccountChangeEvent(t)
+
+	got, err := c.Decode(context.Background(), e)
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme", got["Name"])
+	assert.Nil(t, got["Industry"])
+	assert.Equal(t, map[string]any{"Street": nil, "City": "Leeds"}, got["BillingAddress"])
+
+	header := got["ChangeEventHeader"].(map[string]any)
+	assert.Equal(t, []string{"Name", "Industry", "BillingAddress.City"}, header["changedFields"])
+	assert.Equal(t, []string{"Industry"}, header["nulledFields"])
+
+	_, err = c.Decode(context.Background(), newAccountChangeEvent(t))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls, "schema cached")
+}
+
+func TestClient_DecodeInto(t *testing.T) {
+	calls := 0
+	c := newSchemaClientStub(&calls)
+
+	var got accountChangeEventStub
+	assert.NoError(t, c.DecodeInto(context.Background(), newAccountChangeEvent(t), &got))
+	assert.Equal(t, "UPDATE", got.ChangeEventHeader.ChangeType)
+	assert.Equal(t, []string{"001xx000003DGb2AAG"}, got.ChangeEventHeader.RecordIds)
+	assert.Equal(t, []string{"Name", "Industry", "BillingAddress.City"}, got.ChangeEventHeader.ChangedFields)
+	if assert.NotNil(t, got.Name) {
+		assert.Equal(t, "Acme", *got.Name)
+	}
+	if assert.NotNil(t, got.BillingAddress) && assert.NotNil(t, got.BillingAddress.City) {
+		assert.Equal(t, "Leeds", *got.BillingAddress.City)
+	}
+	assert.Nil(t, got.Industry)
+}
+
+func TestSchema_changedFieldNames_Invalid(t *testing.T) {
+	s, err := parseSchema(accountChangeEventSchema)
+	assert.NoError(t, err)
+
+	for _, bitmap := range []string{"zz", "0x100", "9-0x1"} {
+		_, err = s.changedFieldNames([]any{bitmap})
+		assert.Error(t, err, bitmap)
+	}
+}
+
+func TestTyped(t *testing.T) {
+	calls := 0
+	c := newSchemaClientStub(&calls)
+	r := salesforce.NewEventRegistry()
+	salesforce.RegisterEvent[accountChangeEventStub](r, "/data/AccountChangeEvent")
+
+	var got any
+	handler := Typed(c, r, func(ctx context.Context, e Event, v any) error {
+		got = v
+		return nil
+	})
+	e := newAccountChangeEvent(t)
+	e.Topic = "/data/ChangeEvents"
+	assert.NoError(t, handler(context.Background(), e))
+	if assert.IsType(t, &accountChangeEventStub{}, got) && assert.NotNil(t, got.(*accountChangeEventStub).Name) {
+		assert.Equal(t, "Acme", *got.(*accountChangeEventStub).Name)
+	}
+
+	e.Topic = "/event/Order__e"
+	r = salesforce.NewEventRegistry()
+	assert.EqualError(t, Typed(c, r, nil)(context.Background(), e), "no event type registered for /event/Order__e")
+}
@@ -0,0 +1,99 @@
+package pubsub
+
+import (
+	"context"
+	"github.com/ellogroup/ello-golang-salesforce/salesforce"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+// metricsStub records the counts and timings by metric name
+type metricsStub struct {
+	mu      sync.Mutex
+	counts  map[string]int64
+	timings map[string][]time.Duration
+}
+
+func newMetricsStub() *metricsStub {
+	return &metricsStub{counts: map[string]int64{}, timings: map[string][]time.Duration{}}
+}
+
+func (m *metricsStub) Count(name string, value int64, tags map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[name+" "+tags["subscription"]] += value
+}
+
+func (m *metricsStub) Timing(name string, d time.Duration, tags map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timings[name+" "+tags["subscription"]] = append(m.timings[name+" "+tags["subscription"]], d)
+}
+
+func TestClient_Metrics(t *testing.T) {
+	metrics := newMetricsStub()
+	stream := &streamStub{responses: []*fetchResponse{
+		{Events: []consumerEvent{newConsumerEvent("a", 1), newConsumerEvent("b", 2)}, PendingNumRequested: 98},
+	}}
+	calls := 0
+	c := newSchemaClientStub(&calls)
+	c.openSubscribe = newClientStub(stream).openSubscribe
+	c.metrics = metrics
+	c.backoff = salesforce.BackoffPolicy{Initial: time.Millisecond, MaxRetries: 1}
+
+	_ = c.Run(context.Background(), Subscription{Topic: "/data/AccountChangeEvent"}, func(context.Context, Event) error {
+		return nil
+	})
+	assert.Equal(t, int64(2), metrics.counts[salesforce.MetricSubscriptionEvent+" /data/AccountChangeEvent"])
+	assert.Equal(t, int64(1), metrics.counts[salesforce.MetricSubscriptionReconnect+" /data/AccountChangeEvent"])
+
+	e := newAccountChangeEvent(t)
+	e.Topic = "/data/AccountChangeEvent"
+	_, err := c.Decode(context.Background(), e)
+	assert.NoError(t, err)
+	if assert.Len(t, metrics.timings[salesforce.MetricSubscriptionLag+" /data/AccountChangeEvent"], 1) {
+		assert.Greater(t, metrics.timings[salesforce.MetricSubscriptionLag+" /data/AccountChangeEvent"][0], time.Hour)
+	}
+
+	e.Payload = []byte{0xff}
+	_, err = c.Decode(context.Background(), e)
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), metrics.counts[salesforce.MetricSubscriptionDecodeFailure+" /data/AccountChangeEvent"])
+}
+
+func TestClient_Metrics_ReplayAge(t *testing.T) {
+	tests := []struct {
+		name        string
+		replayStore salesforce.ReplayStore
+		wantTimings int
+	}{
+		{name: "replay store  age recorded on every receive", replayStore: salesforce.NewMemoryReplayStore(), wantTimings: 2},
+		{name: "no replay store  nothing recorded", wantTimings: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := newMetricsStub()
+			stream := &streamStub{responses: []*fetchResponse{
+				{Events: []consumerEvent{newConsumerEvent("a", 1)}, PendingNumRequested: 99},
+				{LatestReplayId: []byte{2}, PendingNumRequested: 99},
+			}}
+			c := newClientStub(stream)
+			c.metrics = metrics
+
+			slow := 50 * time.Millisecond
+			s := Subscription{Topic: "/event/Order__e", ReplayStore: tt.replayStore}
+			_ = c.Subscribe(context.Background(), s, func(context.Context, Event) error {
+				time.Sleep(slow)
+				return nil
+			})
+
+			timings := metrics.timings[salesforce.MetricSubscriptionReplayAge+" /event/Order__e"]
+			if assert.Len(t, timings, tt.wantTimings) && tt.wantTimings > 0 {
+				// the slow batch was checkpointed, so the age is from its save rather than the subscription starting
+				assert.Less(t, timings[1], slow)
+			}
+		})
+	}
+}
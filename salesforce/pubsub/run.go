@@ -0,0 +1,73 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/ellogroup/ello-golang-salesforce/salesforce"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// Run subscribes as Subscribe, reconnecting with exponential backoff, Params.Backoff, when the stream fails. A token
+// salesforce rejected is refreshed before reconnecting, and each reconnect resumes after the last event delivered, or
+// from the ReplayStore. Run returns ctx.Err() once ctx is done, otherwise a salesforce.SubscriptionError once the
+// handler fails, the credentials are rejected or the Backoff gives up
+func (c *Client) Run(ctx context.Context, s Subscription, handler Handler) error {
+	if len(s.Topic) == 0 {
+		return fmt.Errorf("topic needs to be provided")
+	}
+	b := salesforce.ReconnectBackOff(c.backoff)
+	attempts := 0
+	for {
+		delivered := false
+		var handlerErr error
+		err := c.Subscribe(ctx, s, func(ctx context.Context, e Event) error {
+			if handlerErr = handler(ctx, e); handlerErr != nil {
+				return handlerErr
+			}
+			s.ReplayPreset = ReplayCustom
+			s.ReplayId = e.ReplayId
+			delivered = true
+			return nil
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if handlerErr != nil {
+			return salesforce.SubscriptionError{Subscription: s.Topic, Err: err}
+		}
+
+		if delivered {
+			attempts = 0
+			b.Reset()
+		}
+		attempts++
+		wait := b.NextBackOff()
+		if wait == backoff.Stop {
+			return salesforce.SubscriptionError{Subscription: s.Topic, Attempts: attempts, Err: err}
+		}
+		c.count(salesforce.MetricSubscriptionReconnect, s.Topic)
+		if status.Code(err) == codes.Unauthenticated {
+			if err = salesforce.RefreshToken(ctx, c.token, s.Topic); err != nil {
+				return err
+			}
+		}
+		if !sleep(ctx, wait) {
+			return ctx.Err()
+		}
+	}
+}
+
+// sleep waits d, returning false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
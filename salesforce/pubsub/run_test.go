@@ -0,0 +1,83 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"github.com/ellogroup/ello-golang-salesforce/salesforce"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+type refreshingTokenStub struct {
+	tokenStub
+	refreshes int
+}
+
+func (s *refreshingTokenStub) Refresh(context.Context) error {
+	s.refreshes++
+	return nil
+}
+
+func TestClient_Run(t *testing.T) {
+	handlerErr := errors.New("handler error")
+	tests := []struct {
+		name          string
+		streams       []*streamStub
+		wantResumeId  []byte
+		wantRefreshes int
+		wantAttempts  int
+		wantErr       error
+	}{
+		{
+			name: "token rejected  refreshed and resumed after the last event",
+			streams: []*streamStub{
+				{responses: []*fetchResponse{{Events: []consumerEvent{newConsumerEvent("a", 1)}}}, err: status.Error(codes.Unauthenticated, "expired")},
+				{responses: []*fetchResponse{{Events: []consumerEvent{newConsumerEvent("stop", 2)}}}},
+			},
+			wantResumeId:  []byte{1},
+			wantRefreshes: 1,
+			wantErr:       handlerErr,
+		},
+		{
+			name:         "stream keeps failing  attempts exhausted",
+			streams:      []*streamStub{{}, {}, {}},
+			wantAttempts: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &refreshingTokenStub{}
+			opened := 0
+			c := &Client{
+				token:   token,
+				backoff: salesforce.BackoffPolicy{Initial: time.Millisecond, MaxRetries: 2},
+				openSubscribe: func(context.Context) (subscribeStream, error) {
+					opened++
+					return tt.streams[opened-1], nil
+				},
+			}
+
+			err := c.Run(context.Background(), Subscription{Topic: "/event/Order__e"}, func(ctx context.Context, e Event) error {
+				if e.Id == "stop" {
+					return handlerErr
+				}
+				return nil
+			})
+
+			var subErr salesforce.SubscriptionError
+			assert.ErrorAs(t, err, &subErr)
+			assert.Equal(t, tt.wantAttempts, subErr.Attempts)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			if tt.wantResumeId != nil {
+				assert.Equal(t, ReplayCustom, tt.streams[1].sent[0].ReplayPreset)
+				assert.Equal(t, tt.wantResumeId, tt.streams[1].sent[0].ReplayId)
+			}
+			assert.Equal(t, tt.wantRefreshes, token.refreshes)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/linkedin/goavro/v2"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// schema a parsed event schema, the json is kept to unwrap unions and map change event bitmaps to field names
+type schema struct {
+	codec *goavro.Codec
+	root  map[string]any
+	// named the named types of the schema, by name and full name
+	named map[string]any
+}
+
+// schema returns the schema of schemaId, fetching it with GetSchema on first use
+func (c *Client) schema(ctx context.Context, schemaId string) (*schema, error) {
+	if s, ok := c.schemas.Load(schemaId); ok {
+		return s.(*schema), nil
+	}
+	info := &schemaInfo{}
+	if err := c.invoke(ctx, "GetSchema", &schemaRequest{SchemaId: schemaId}, info); err != nil {
+		return nil, fmt.Errorf("unable to get salesforce pubsub schema %s: %w", schemaId, err)
+	}
+	s, err := parseSchema(info.SchemaJson)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse salesforce pubsub schema %s: %w", schemaId, err)
+	}
+	c.schemas.Store(schemaId, s)
+	return s, nil
+}
+
+func parseSchema(schemaJson string) (*schema, error) {
+	codec, err := goavro.NewCodec(schemaJson)
+	if err != nil {
+		return nil, err
+	}
+	var root map[string]any
+	if err = json.Unmarshal([]byte(schemaJson), &root); err != nil {
+		return nil, err
+	}
+	s := &schema{codec: codec, root: root, named: map[string]any{}}
+	s.collectNamed(root, "")
+	return s, nil
+}
+
+// collectNamed records the named types within t, namespace is the enclosing namespace
+func (s *schema) collectNamed(t any, namespace string) {
+	switch t := t.(type) {
+	case []any:
+		for _, branch := range t {
+			s.collectNamed(branch, namespace)
+		}
+	case map[string]any:
+		if ns, ok := t["namespace"].(string); ok {
+			namespace = ns
+		}
+		if name, ok := t["name"].(string); ok && isNamedType(t["type"]) {
+			s.named[name] = t
+			if len(namespace) > 0 && !strings.Contains(name, ".") {
+				s.named[namespace+"."+name] = t
+			}
+		}
+		if fields, ok := t["fields"].([]any); ok {
+			for _, f := range fields {
+				if f, ok := f.(map[string]any); ok {
+					s.collectNamed(f["type"], namespace)
+				}
+			}
+		}
+		s.collectNamed(t["items"], namespace)
+		s.collectNamed(t["values"], namespace)
+		if _, ok := t["type"].(string); !ok {
+			s.collectNamed(t["type"], namespace)
+		}
+	}
+}
+
+func isNamedType(t any) bool {
+	return t == "record" || t == "enum" || t == "fixed"
+}
+
+// resolve returns the definition of a named type reference, or t when it isn't one
+func (s *schema) resolve(t any) any {
+	if name, ok := t.(string); ok {
+		if def, ok := s.named[name]; ok {
+			return def
+		}
+	}
+	return t
+}
+
+// decode decodes an Avro payload, unwrapping unions so nullable fields are their value or nil
+func (s *schema) decode(payload []byte) (map[string]any, error) {
+	native, _, err := s.codec.NativeFromBinary(payload)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := s.unwrap(s.root, native).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("event payload isn't a record")
+	}
+	return record, nil
+}
+
+// unwrap replaces goavro's union wrappers, {"type": value}, with the value, following the schema t of v
+func (s *schema) unwrap(t any, v any) any {
+	t = s.resolve(t)
+	switch t := t.(type) {
+	case []any:
+		wrapped, ok := v.(map[string]any)
+		if !ok || len(wrapped) != 1 {
+			return v
+		}
+		for name, value := range wrapped {
+			return s.unwrap(s.unionBranch(t, name), value)
+		}
+	case map[string]any:
+		switch t["type"] {
+		case "record":
+			record, ok := v.(map[string]any)
+			if !ok {
+				return v
+			}
+			fields, _ := t["fields"].([]any)
+			for _, f := range fields {
+				f, _ := f.(map[string]any)
+				name, _ := f["name"].(string)
+				if value, ok := record[name]; ok {
+					record[name] = s.unwrap(f["type"], value)
+				}
+			}
+			return record
+		case "array":
+			items, ok := v.([]any)
+			if !ok {
+				return v
+			}
+			for i, item := range items {
+				items[i] = s.unwrap(t["items"], item)
+			}
+			return items
+		case "map":
+			values, ok := v.(map[string]any)
+			if !ok {
+				return v
+			}
+			for k, value := range values {
+				values[k] = s.unwrap(t["values"], value)
+			}
+			return values
+		}
+	}
+	return v
+}
+
+// unionBranch the branch of a union named name, as goavro names them
+func (s *schema) unionBranch(union []any, name string) any {
+	for _, branch := range union {
+		if typeName(s.resolve(branch)) == name || branch == name {
+			return branch
+		}
+		if def, ok := s.resolve(branch).(map[string]any); ok {
+			if n, _ := def["name"].(string); len(n) > 0 && strings.HasSuffix(name, "."+n) {
+				return branch
+			}
+		}
+	}
+	return nil
+}
+
+// typeName the name goavro gives a union branch of type t
+func typeName(t any) string {
+	switch t := t.(type) {
+	case string:
+		return t
+	case map[string]any:
+		if name, ok := t["name"].(string); ok && isNamedType(t["type"]) {
+			return name
+		}
+		if name, ok := t["type"].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// changedFieldNames maps a change event header's bitmap field list, e.g. changedFields, to field names. Each entry is
+// either a bitmap of top level field indexes, 0x..., or of the fields of a compound field, index-0x..., such as the
+// FirstName of a contact's Name which is returned as Name.FirstName
+func (s *schema) changedFieldNames(bitmaps []any) ([]string, error) {
+	var names []string
+	for _, b := range bitmaps {
+		bitmap, _ := b.(string)
+		fields := s.fields(s.root)
+		prefix := ""
+		if parent, hex, ok := strings.Cut(bitmap, "-"); ok {
+			i, err := strconv.Atoi(parent)
+			if err != nil || i < 0 || i >= len(fields) {
+				return nil, fmt.Errorf("invalid change event bitmap %q", bitmap)
+			}
+			name, _ := fields[i]["name"].(string)
+			prefix = name + "."
+			fields = s.fields(s.nonNull(fields[i]["type"]))
+			bitmap = hex
+		}
+
+		bits, ok := new(big.Int).SetString(strings.TrimPrefix(bitmap, "0x"), 16)
+		if !ok {
+			return nil, fmt.Errorf("invalid change event bitmap %q", b)
+		}
+		for i := 0; i < bits.BitLen(); i++ {
+			if bits.Bit(i) == 0 {
+				continue
+			}
+			if i >= len(fields) {
+				return nil, fmt.Errorf("change event bitmap %q references field %d of %d", b, i, len(fields))
+			}
+			name, _ := fields[i]["name"].(string)
+			names = append(names, prefix+name)
+		}
+	}
+	return names, nil
+}
+
+// fields the fields of record type t
+func (s *schema) fields(t any) []map[string]any {
+	def, _ := s.resolve(t).(map[string]any)
+	raw, _ := def["fields"].([]any)
+	fields := make([]map[string]any, 0, len(raw))
+	for _, f := range raw {
+		if f, ok := f.(map[string]any); ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// nonNull the non null branch of a nullable union, or t when it isn't a union
+func (s *schema) nonNull(t any) any {
+	union, ok := t.([]any)
+	if !ok {
+		return t
+	}
+	for _, branch := range union {
+		if branch != "null" {
+			return branch
+		}
+	}
+	return nil
+}
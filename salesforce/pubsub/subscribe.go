@@ -0,0 +1,177 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"github.com/ellogroup/ello-golang-salesforce/salesforce"
+	"time"
+)
+
+// ReplayPreset where a subscription starts reading a channel
+type ReplayPreset int32
+
+const (
+	// ReplayLatest only events published after subscribing
+	ReplayLatest ReplayPreset = 0
+	// ReplayEarliest the earliest retained events, up to 72 hours old
+	ReplayEarliest ReplayPreset = 1
+	// ReplayCustom the events after Subscription.ReplayId
+	ReplayCustom ReplayPreset = 2
+)
+
+// defaultBatchSize the events requested at a time when Subscription.BatchSize isn't set
+const defaultBatchSize = 100
+
+// Subscription a channel to subscribe to
+type Subscription struct {
+	// Topic the channel, e.g. /event/Order_Shipped__e or /data/AccountChangeEvent
+	Topic        string
+	ReplayPreset ReplayPreset
+	// ReplayId the replay id to resume after, for ReplayCustom
+	ReplayId []byte
+	// BatchSize the events requested at a time, the next batch is requested once every event has been delivered.
+	// Defaults to 100, the Pub/Sub API allows up to 100
+	BatchSize int32
+	// ReplayStore when set, the subscription resumes after the replay id it holds, falling back to ReplayPreset when
+	// there isn't one, and the replay id is saved after each batch of events is handled
+	ReplayStore salesforce.ReplayStore
+	// Name the key of the subscription in ReplayStore, defaults to Topic
+	Name string
+}
+
+// name the key of the subscription in its ReplayStore
+func (s Subscription) name() string {
+	if len(s.Name) > 0 {
+		return s.Name
+	}
+	return s.Topic
+}
+
+// resume sets the replay id to resume from, from the ReplayStore
+func (s *Subscription) resume(ctx context.Context) error {
+	if s.ReplayStore == nil {
+		return nil
+	}
+	replayId, err := s.ReplayStore.Load(ctx, s.name())
+	if err != nil {
+		return fmt.Errorf("unable to load replay id of %s: %w", s.name(), err)
+	}
+	if len(replayId) > 0 {
+		s.ReplayPreset = ReplayCustom
+		s.ReplayId = replayId
+	}
+	return nil
+}
+
+// checkpoint saves the replay id of a handled batch, or of a keepalive, to the ReplayStore, returning whether one was
+// saved
+func (s Subscription) checkpoint(ctx context.Context, resp *fetchResponse) (bool, error) {
+	if s.ReplayStore == nil {
+		return false, nil
+	}
+	replayId := resp.LatestReplayId
+	if len(resp.Events) > 0 {
+		replayId = resp.Events[len(resp.Events)-1].ReplayId
+	}
+	if len(replayId) == 0 {
+		return false, nil
+	}
+	if err := s.ReplayStore.Save(ctx, s.name(), replayId); err != nil {
+		return false, fmt.Errorf("unable to save replay id of %s: %w", s.name(), err)
+	}
+	return true, nil
+}
+
+// Event a received event, the payload is Avro encoded with the schema SchemaId
+type Event struct {
+	Topic    string
+	Id       string
+	SchemaId string
+	Payload  []byte
+	// ReplayId the position of the event in the channel, to resume after it
+	ReplayId []byte
+	Headers  map[string][]byte
+}
+
+// Handler handles an event, returning an error stops the subscription
+type Handler func(ctx context.Context, e Event) error
+
+// Subscribe subscribes to a channel and delivers each event to handler in order, blocking until ctx is done, the
+// stream fails or handler returns an error. Events are requested in batches of Subscription.BatchSize, so no more than
+// a batch is ever buffered. With a ReplayStore, events are delivered at least once: a batch interrupted part way is
+// redelivered on resume
+func (c *Client) Subscribe(ctx context.Context, s Subscription, handler Handler) error {
+	if len(s.Topic) == 0 {
+		return fmt.Errorf("topic needs to be provided")
+	}
+	if err := s.resume(ctx); err != nil {
+		return err
+	}
+	if s.ReplayPreset == ReplayCustom && len(s.ReplayId) == 0 {
+		return fmt.Errorf("replayId needs to be provided for a custom replay")
+	}
+	batchSize := s.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.openSubscribe(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to subscribe to %s: %w", s.Topic, err)
+	}
+	req := &fetchRequest{TopicName: s.Topic, ReplayPreset: s.ReplayPreset, ReplayId: s.ReplayId, NumRequested: batchSize}
+	if err = stream.Send(req); err != nil {
+		return fmt.Errorf("unable to subscribe to %s: %w", s.Topic, err)
+	}
+
+	// when the replay id was last saved, from the subscription starting until the first checkpoint
+	saved := time.Now()
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("salesforce pubsub subscription to %s failed: %w", s.Topic, err)
+		}
+		if s.ReplayStore != nil {
+			c.timing(salesforce.MetricSubscriptionReplayAge, s.Topic, time.Since(saved))
+		}
+		for _, ce := range resp.Events {
+			c.count(salesforce.MetricSubscriptionEvent, s.Topic)
+			if err = handler(ctx, newEvent(s.Topic, ce)); err != nil {
+				return fmt.Errorf("salesforce pubsub handler for %s failed: %w", s.Topic, err)
+			}
+		}
+		ok, err := s.checkpoint(ctx, resp)
+		if err != nil {
+			return err
+		}
+		if ok {
+			saved = time.Now()
+		}
+		// request the next batch once the last has been delivered
+		if resp.PendingNumRequested == 0 {
+			if err = stream.Send(&fetchRequest{TopicName: s.Topic, NumRequested: batchSize}); err != nil {
+				return fmt.Errorf("unable to request events of %s: %w", s.Topic, err)
+			}
+		}
+	}
+}
+
+func newEvent(topic string, ce consumerEvent) Event {
+	headers := make(map[string][]byte, len(ce.Event.Headers))
+	for _, h := range ce.Event.Headers {
+		headers[h.Key] = h.Value
+	}
+	return Event{
+		Topic:    topic,
+		Id:       ce.Event.Id,
+		SchemaId: ce.Event.SchemaId,
+		Payload:  ce.Event.Payload,
+		ReplayId: ce.ReplayId,
+		Headers:  headers,
+	}
+}
@@ -0,0 +1,226 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"github.com/ellogroup/ello-golang-salesforce/salesforce"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"sync"
+	"testing"
+)
+
+// streamStub a subscribe stream returning responses in order, then err or io.EOF
+type streamStub struct {
+	mu        sync.Mutex
+	responses []*fetchResponse
+	sent      []*fetchRequest
+	err       error
+}
+
+func (s *streamStub) Send(req *fetchRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, req)
+	return nil
+}
+
+func (s *streamStub) Recv() (*fetchResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.responses) == 0 && s.err != nil {
+		return nil, s.err
+	}
+	if len(s.responses) == 0 {
+		return nil, io.EOF
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
+
+func newClientStub(stream *streamStub) *Client {
+	return &Client{openSubscribe: func(context.Context) (subscribeStream, error) {
+		return stream, nil
+	}}
+}
+
+func newConsumerEvent(id string, replayId byte) consumerEvent {
+	return consumerEvent{Event: producerEvent{Id: id, SchemaId: "schema-1", Payload: []byte(id)}, ReplayId: []byte{replayId}}
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	handlerErr := fmt.Errorf("handler error")
+
+	tests := []struct {
+		name         string
+		subscription Subscription
+		responses    []*fetchResponse
+		handlerErr   error
+		wantIds      []string
+		wantSent     []*fetchRequest
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name:         "events delivered  next batch requested once pending reaches 0",
+			subscription: Subscription{Topic: "/event/Order__e", BatchSize: 2},
+			responses: []*fetchResponse{
+				{Events: []consumerEvent{newConsumerEvent("a", 1)}, PendingNumRequested: 1},
+				{Events: []consumerEvent{newConsumerEvent("b", 2)}, PendingNumRequested: 0},
+				{LatestReplayId: []byte{2}, PendingNumRequested: 2},
+			},
+			wantIds: []string{"a", "b"},
+			wantSent: []*fetchRequest{
+				{TopicName: "/event/Order__e", NumRequested: 2},
+				{TopicName: "/event/Order__e", NumRequested: 2},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name:         "custom replay  replay id sent",
+			subscription: Subscription{Topic: "/event/Order__e", ReplayPreset: ReplayCustom, ReplayId: []byte{9}},
+			wantSent: []*fetchRequest{
+				{TopicName: "/event/Order__e", ReplayPreset: ReplayCustom, ReplayId: []byte{9}, NumRequested: defaultBatchSize},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name:         "handler returns error  subscription stopped",
+			subscription: Subscription{Topic: "/event/Order__e"},
+			responses: []*fetchResponse{
+				{Events: []consumerEvent{newConsumerEvent("a", 1), newConsumerEvent("b", 2)}, PendingNumRequested: 98},
+			},
+			handlerErr: handlerErr,
+			wantIds:    []string{"a"},
+			wantSent:   []*fetchRequest{{TopicName: "/event/Order__e", NumRequested: defaultBatchSize}},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, handlerErr, i...)
+			},
+		},
+		{
+			name:         "custom replay without replay id  error returned",
+			subscription: Subscription{Topic: "/event/Order__e", ReplayPreset: ReplayCustom},
+			wantErr:      assert.Error,
+		},
+		{
+			name:         "no topic  error returned",
+			subscription: Subscription{},
+			wantErr:      assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &streamStub{responses: tt.responses}
+			c := newClientStub(stream)
+
+			var ids []string
+			err := c.Subscribe(context.Background(), tt.subscription, func(ctx context.Context, e Event) error {
+				ids = append(ids, e.Id)
+				assert.Equal(t, tt.subscription.Topic, e.Topic)
+				assert.Equal(t, []byte(e.Id), e.Payload)
+				return tt.handlerErr
+			})
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.wantIds, ids)
+			assert.Equal(t, tt.wantSent, stream.sent)
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  Params
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "token, instance url and tenant  client returned",
+			params:  Params{Token: tokenStub{}, InstanceUrl: "https://org.my.salesforce.com", TenantId: "00Dxx"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "instance token getter  client returned",
+			params:  Params{Token: instanceTokenStub{}, TenantId: "00Dxx"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "no instance url  error returned",
+			params:  Params{Token: tokenStub{}, TenantId: "00Dxx"},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "no tenant  error returned",
+			params:  Params{Token: tokenStub{}, InstanceUrl: "https://org.my.salesforce.com"},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(tt.params)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assert.NoError(t, c.Close())
+		})
+	}
+}
+
+type tokenStub struct{}
+
+func (tokenStub) Get(context.Context) (string, error) {
+	return "token", nil
+}
+
+type instanceTokenStub struct {
+	tokenStub
+}
+
+func (instanceTokenStub) InstanceUrl(context.Context) (string, error) {
+	return "https://org.my.salesforce.com", nil
+}
+
+func TestClient_Subscribe_ReplayStore(t *testing.T) {
+	tests := []struct {
+		name         string
+		stored       []byte
+		responses    []*fetchResponse
+		wantFirst    *fetchRequest
+		wantReplayId []byte
+	}{
+		{
+			name:   "replay id stored  resumed after it and checkpointed",
+			stored: []byte{5},
+			responses: []*fetchResponse{
+				{Events: []consumerEvent{newConsumerEvent("a", 6), newConsumerEvent("b", 7)}, PendingNumRequested: 98},
+			},
+			wantFirst:    &fetchRequest{TopicName: "/event/Order__e", ReplayPreset: ReplayCustom, ReplayId: []byte{5}, NumRequested: defaultBatchSize},
+			wantReplayId: []byte{7},
+		},
+		{
+			name: "nothing stored  preset used and keepalive checkpointed",
+			responses: []*fetchResponse{
+				{LatestReplayId: []byte{8}, PendingNumRequested: 100},
+			},
+			wantFirst:    &fetchRequest{TopicName: "/event/Order__e", ReplayPreset: ReplayEarliest, NumRequested: defaultBatchSize},
+			wantReplayId: []byte{8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := salesforce.NewMemoryReplayStore()
+			if tt.stored != nil {
+				assert.NoError(t, store.Save(ctx, "orders", tt.stored))
+			}
+			stream := &streamStub{responses: tt.responses}
+			c := newClientStub(stream)
+
+			s := Subscription{Topic: "/event/Order__e", ReplayPreset: ReplayEarliest, ReplayStore: store, Name: "orders"}
+			_ = c.Subscribe(ctx, s, func(context.Context, Event) error { return nil })
+
+			assert.Equal(t, tt.wantFirst, stream.sent[0])
+			got, _ := store.Load(ctx, "orders")
+			assert.Equal(t, tt.wantReplayId, got)
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package pubsub
+
+import (
+	"fmt"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The Pub/Sub API messages used by this package, encoded by hand with protowire rather than generated from
+// pubsub_api.proto, as only a handful of fields are needed. Field numbers follow the proto
+
+// message a Pub/Sub API message which can be sent or received
+type message interface {
+	marshal() []byte
+	unmarshal(b []byte) error
+}
+
+// codec encodes messages for grpc, in place of the generated protobuf codec
+type codec struct{}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(message)
+	if !ok {
+		return nil, fmt.Errorf("unsupported pubsub message type %T", v)
+	}
+	return m.marshal(), nil
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(message)
+	if !ok {
+		return fmt.Errorf("unsupported pubsub message type %T", v)
+	}
+	return m.unmarshal(data)
+}
+
+func (codec) Name() string {
+	return "proto"
+}
+
+// fetchRequest requests events of a topic, num_requested is the flow control
+type fetchRequest struct {
+	TopicName    string
+	ReplayPreset ReplayPreset
+	ReplayId     []byte
+	NumRequested int32
+	AuthRefresh  string
+}
+
+func (m *fetchRequest) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.TopicName)
+	b = appendVarint(b, 2, uint64(m.ReplayPreset))
+	b = appendBytes(b, 3, m.ReplayId)
+	b = appendVarint(b, 4, uint64(m.NumRequested))
+	b = appendString(b, 5, m.AuthRefresh)
+	return b
+}
+
+func (m *fetchRequest) unmarshal(b []byte) error {
+	return parseFields(b, func(f wireField) error {
+		switch f.num {
+		case 1:
+			m.TopicName = string(f.bytes)
+		case 2:
+			m.ReplayPreset = ReplayPreset(f.varint)
+		case 3:
+			m.ReplayId = f.bytes
+		case 4:
+			m.NumRequested = int32(f.varint)
+		case 5:
+			m.AuthRefresh = string(f.bytes)
+		}
+		return nil
+	})
+}
+
+// fetchResponse a batch of events, or a keepalive when Events is empty
+type fetchResponse struct {
+	Events              []consumerEvent
+	LatestReplayId      []byte
+	RpcId               string
+	PendingNumRequested int32
+}
+
+func (m *fetchResponse) marshal() []byte {
+	var b []byte
+	for _, e := range m.Events {
+		b = appendBytes(b, 1, e.marshal())
+	}
+	b = appendBytes(b, 2, m.LatestReplayId)
+	b = appendString(b, 3, m.RpcId)
+	b = appendVarint(b, 4, uint64(m.PendingNumRequested))
+	return b
+}
+
+func (m *fetchResponse) unmarshal(b []byte) error {
+	return parseFields(b, func(f wireField) error {
+		switch f.num {
+		case 1:
+			var e consumerEvent
+			if err := e.unmarshal(f.bytes); err != nil {
+				return err
+			}
+			m.Events = append(m.Events, e)
+		case 2:
+			m.LatestReplayId = f.bytes
+		case 3:
+			m.RpcId = string(f.bytes)
+		case 4:
+			m.PendingNumRequested = int32(f.varint)
+		}
+		return nil
+	})
+}
+
+// consumerEvent an event and its replay id
+type consumerEvent struct {
+	Event    producerEvent
+	ReplayId []byte
+}
+
+func (m *consumerEvent) marshal() []byte {
+	var b []byte
+	b = appendBytes(b, 1, m.Event.marshal())
+	b = appendBytes(b, 2, m.ReplayId)
+	return b
+}
+
+func (m *consumerEvent) unmarshal(b []byte) error {
+	return parseFields(b, func(f wireField) error {
+		switch f.num {
+		case 1:
+			return m.Event.unmarshal(f.bytes)
+		case 2:
+			m.ReplayId = f.bytes
+		}
+		return nil
+	})
+}
+
+// producerEvent an Avro encoded event
+type producerEvent struct {
+	Id       string
+	SchemaId string
+	Payload  []byte
+	Headers  []eventHeader
+}
+
+func (m *producerEvent) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.Id)
+	b = appendString(b, 2, m.SchemaId)
+	b = appendBytes(b, 3, m.Payload)
+	for _, h := range m.Headers {
+		b = appendBytes(b, 4, h.marshal())
+	}
+	return b
+}
+
+func (m *producerEvent) unmarshal(b []byte) error {
+	return parseFields(b, func(f wireField) error {
+		switch f.num {
+		case 1:
+			m.Id = string(f.bytes)
+		case 2:
+			m.SchemaId = string(f.bytes)
+		case 3:
+			m.Payload = f.bytes
+		case 4:
+			var h eventHeader
+			if err := h.unmarshal(f.bytes); err != nil {
+				return err
+			}
+			m.Headers = append(m.Headers, h)
+		}
+		return nil
+	})
+}
+
+type eventHeader struct {
+	Key   string
+	Value []byte
+}
+
+func (m *eventHeader) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.Key)
+	b = appendBytes(b, 2, m.Value)
+	return b
+}
+
+func (m *eventHeader) unmarshal(b []byte) error {
+	return parseFields(b, func(f wireField) error {
+		switch f.num {
+		case 1:
+			m.Key = string(f.bytes)
+		case 2:
+			m.Value = f.bytes
+		}
+		return nil
+	})
+}
+
+// wireField a decoded field, bytes is set for length delimited fields and varint for varints
+type wireField struct {
+	num    protowire.Number
+	bytes  []byte
+	varint uint64
+}
+
+// parseFields calls fn with each field of b, skipping fields of other wire types as they aren't used
+func parseFields(b []byte, fn func(f wireField) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("unable to parse pubsub message: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		f := wireField{num: num}
+		switch typ {
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(b)
+		case protowire.VarintType:
+			f.varint, n = protowire.ConsumeVarint(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return fmt.Errorf("unable to parse pubsub message: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		if typ != protowire.BytesType && typ != protowire.VarintType {
+			continue
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendString, appendBytes and appendVarint append a field, omitting zero values as proto3 does
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// schemaRequest requests the Avro schema of an event
+type schemaRequest struct {
+	SchemaId string
+}
+
+func (m *schemaRequest) marshal() []byte {
+	return appendString(nil, 1, m.SchemaId)
+}
+
+func (m *schemaRequest) unmarshal(b []byte) error {
+	return parseFields(b, func(f wireField) error {
+		if f.num == 1 {
+			m.SchemaId = string(f.bytes)
+		}
+		return nil
+	})
+}
+
+// schemaInfo the Avro schema of an event, as json
+type schemaInfo struct {
+	SchemaJson string
+	SchemaId   string
+	RpcId      string
+}
+
+func (m *schemaInfo) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.SchemaJson)
+	b = appendString(b, 2, m.SchemaId)
+	b = appendString(b, 3, m.RpcId)
+	return b
+}
+
+func (m *schemaInfo) unmarshal(b []byte) error {
+	return parseFields(b, func(f wireField) error {
+		switch f.num {
+		case 1:
+			m.SchemaJson = string(f.bytes)
+		case 2:
+			m.SchemaId = string(f.bytes)
+		case 3:
+			m.RpcId = string(f.bytes)
+		}
+		return nil
+	})
+}
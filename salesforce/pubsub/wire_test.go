@@ -0,0 +1,48 @@
+package pubsub
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCodec(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  message
+		zero func() message
+	}{
+		{
+			name: "fetch request  round trips",
+			msg:  &fetchRequest{TopicName: "/event/Order__e", ReplayPreset: ReplayCustom, ReplayId: []byte{1, 2}, NumRequested: 100, AuthRefresh: "token"},
+			zero: func() message { return &fetchRequest{} },
+		},
+		{
+			name: "fetch response  round trips",
+			msg: &fetchResponse{
+				Events: []consumerEvent{{
+					Event:    producerEvent{Id: "a", SchemaId: "s", Payload: []byte{0, 1}, Headers: []eventHeader{{Key: "k", Value: []byte("v")}}},
+					ReplayId: []byte{3},
+				}},
+				LatestReplayId:      []byte{3},
+				RpcId:               "rpc",
+				PendingNumRequested: 99,
+			},
+			zero: func() message { return &fetchResponse{} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := codec{}.Marshal(tt.msg)
+			assert.NoError(t, err)
+			got := tt.zero()
+			assert.NoError(t, codec{}.Unmarshal(b, got))
+			assert.Equal(t, tt.msg, got)
+		})
+	}
+}
+
+func TestCodec_UnsupportedType(t *testing.T) {
+	_, err := codec{}.Marshal("not a message")
+	assert.Error(t, err)
+	assert.Error(t, codec{}.Unmarshal(nil, new(string)))
+}
@@ -0,0 +1,120 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+)
+
+// NotifyForFields which field changes of a record generate a PushTopic event
+type NotifyForFields string
+
+const (
+	// NotifyAll changes to any field
+	NotifyAll NotifyForFields = "All"
+	// NotifyReferenced changes to fields in the query SELECT or WHERE clauses, the default
+	NotifyReferenced NotifyForFields = "Referenced"
+	// NotifySelect changes to fields in the query SELECT clause
+	NotifySelect NotifyForFields = "Select"
+	// NotifyWhere changes to fields in the query WHERE clause
+	NotifyWhere NotifyForFields = "Where"
+)
+
+const pushTopicObject = "PushTopic"
+
+// pushTopicNameMaxLength the longest PushTopic name salesforce accepts
+const pushTopicNameMaxLength = 25
+
+// PushTopic a PushTopic record, which publishes events to /topic/{Name} for records matching Query
+type PushTopic struct {
+	Id          string  `json:"Id,omitempty"`
+	Name        string  `json:"Name"`
+	Query       string  `json:"Query"`
+	ApiVersion  float64 `json:"ApiVersion"`
+	Description string  `json:"Description,omitempty"`
+	IsActive    bool    `json:"IsActive"`
+	// NotifyForFields defaults to NotifyReferenced
+	NotifyForFields          NotifyForFields `json:"NotifyForFields,omitempty"`
+	NotifyForOperationCreate bool            `json:"NotifyForOperationCreate"`
+	NotifyForOperationUpdate bool            `json:"NotifyForOperationUpdate"`
+	NotifyForOperationDelete bool            `json:"NotifyForOperationDelete"`
+	// NotifyForOperationUndelete notifies of undeleted records
+	NotifyForOperationUndelete bool `json:"NotifyForOperationUndelete"`
+}
+
+// Channel the streaming channel of the PushTopic
+func (t PushTopic) Channel() string {
+	return "/topic/" + t.Name
+}
+
+// pushTopicFields the fields of PushTopic queried
+const pushTopicFields = "Id, Name, Query, ApiVersion, Description, IsActive, NotifyForFields, NotifyForOperationCreate, " +
+	"NotifyForOperationUpdate, NotifyForOperationDelete, NotifyForOperationUndelete"
+
+func (h *RequestHelper) validPushTopic(t PushTopic) (PushTopic, error) {
+	if len(t.Name) == 0 {
+		return t, fmt.Errorf("push topic name needs to be provided")
+	}
+	if len(t.Name) > pushTopicNameMaxLength {
+		return t, fmt.Errorf("push topic name %s is longer than %d characters", t.Name, pushTopicNameMaxLength)
+	}
+	if len(t.Query) == 0 {
+		return t, fmt.Errorf("push topic query needs to be provided")
+	}
+	if t.ApiVersion == 0 {
+		t.ApiVersion = float64(h.apiVersion)
+	}
+	t.Id = ""
+	return t, nil
+}
+
+// CreatePushTopic creates a PushTopic, returning its id. ApiVersion defaults to the RequestHelper's api version
+func CreatePushTopic(ctx context.Context, h *RequestHelper, t PushTopic) (string, error) {
+	t, err := h.validPushTopic(t)
+	if err != nil {
+		return "", err
+	}
+	return Post(ctx, h, pushTopicObject, t)
+}
+
+// GetPushTopic fetches a PushTopic by name, nil when there is none
+func GetPushTopic(ctx context.Context, h *RequestHelper, name string) (*PushTopic, error) {
+	resp, err := Query[PushTopic](ctx, h, fmt.Sprintf("SELECT %s FROM PushTopic WHERE Name = %s", pushTopicFields, QuoteString(name)))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Records) == 0 {
+		return nil, nil
+	}
+	return &resp.Records[0], nil
+}
+
+// UpdatePushTopic updates the PushTopic with id, e.g. to change its query or deactivate it
+func UpdatePushTopic(ctx context.Context, h *RequestHelper, id string, t PushTopic) error {
+	t, err := h.validPushTopic(t)
+	if err != nil {
+		return err
+	}
+	_, err = Patch(ctx, h, pushTopicObject, id, t)
+	return err
+}
+
+// DeletePushTopic deletes the PushTopic with id
+func DeletePushTopic(ctx context.Context, h *RequestHelper, id string) error {
+	return Delete(ctx, h, pushTopicObject, id)
+}
+
+// EnsurePushTopic creates the PushTopic, or updates the PushTopic with the same name, so subscribers can provision
+// their topic on startup. Returns the PushTopic id
+func EnsurePushTopic(ctx context.Context, h *RequestHelper, t PushTopic) (string, error) {
+	if _, err := h.validPushTopic(t); err != nil {
+		return "", err
+	}
+	existing, err := GetPushTopic(ctx, h, t.Name)
+	if err != nil {
+		return "", err
+	}
+	if existing == nil {
+		return CreatePushTopic(ctx, h, t)
+	}
+	return existing.Id, UpdatePushTopic(ctx, h, existing.Id, t)
+}
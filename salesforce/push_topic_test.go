@@ -0,0 +1,89 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestEnsurePushTopic(t *testing.T) {
+	tests := []struct {
+		name       string
+		topic      PushTopic
+		queryResp  string
+		wantId     string
+		wantMethod string
+		wantPath   string
+		wantErr    string
+	}{
+		{
+			name:       "no push topic  created",
+			topic:      PushTopic{Name: "AccountUpdates", Query: "SELECT Id FROM Account", NotifyForOperationUpdate: true},
+			queryResp:  `{"totalSize":0,"done":true,"records":[]}`,
+			wantId:     "0IFxx0000000001",
+			wantMethod: http.MethodPost,
+			wantPath:   "/services/data/v55.0/sobjects/PushTopic",
+		},
+		{
+			name:       "push topic exists  updated",
+			topic:      PushTopic{Name: "AccountUpdates", Query: "SELECT Id FROM Account", NotifyForOperationUpdate: true},
+			queryResp:  `{"totalSize":1,"done":true,"records":[{"Id":"0IFxx0000000002","Name":"AccountUpdates"}]}`,
+			wantId:     "0IFxx0000000002",
+			wantMethod: http.MethodPatch,
+			wantPath:   "/services/data/v55.0/sobjects/PushTopic/0IFxx0000000002",
+		},
+		{
+			name:    "name too long  error",
+			topic:   PushTopic{Name: "AccountUpdatesForTheSalesTeam", Query: "SELECT Id FROM Account"},
+			wantErr: "push topic name AccountUpdatesForTheSalesTeam is longer than 25 characters",
+		},
+		{
+			name:    "no query  error",
+			topic:   PushTopic{Name: "AccountUpdates"},
+			wantErr: "push topic query needs to be provided",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written map[string]any
+			var writeMethod, writePath string
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodGet {
+					assert.Contains(t, req.URL.Query().Get("q"), "WHERE Name = 'AccountUpdates'")
+					return newResponse(http.StatusOK, tt.queryResp), nil
+				}
+				writeMethod, writePath = req.Method, req.URL.Path
+				b, _ := io.ReadAll(req.Body)
+				_ = json.Unmarshal(b, &written)
+				if req.Method == http.MethodPost {
+					return newResponse(http.StatusCreated, `{"id":"0IFxx0000000001","success":true}`), nil
+				}
+				return newResponse(http.StatusNoContent, ""), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := EnsurePushTopic(context.Background(), h, tt.topic)
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, err, tt.wantErr)
+				client.AssertNotCalled(t, "Do")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantId, got)
+			assert.Equal(t, tt.wantMethod, writeMethod)
+			assert.Equal(t, tt.wantPath, writePath)
+			assert.Equal(t, float64(55), written["ApiVersion"])
+			assert.Equal(t, false, written["NotifyForOperationCreate"])
+			assert.NotContains(t, written, "Id")
+		})
+	}
+}
+
+func TestPushTopic_Channel(t *testing.T) {
+	assert.Equal(t, "/topic/AccountUpdates", PushTopic{Name: "AccountUpdates"}.Channel())
+}
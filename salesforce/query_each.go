@@ -0,0 +1,217 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// QueryEach runs a query and calls fn with each record as it is decoded, following nextRecordsUrl until every page
+// has been read. Each page is read before its records are decoded one at a time, so only the page's json and a single
+// record are held in memory, suiting large queries of wide objects. As a page's request has finished before fn is
+// called, fn may send requests of its own, even through a RequestQueue with a single worker, and the Query timeout
+// doesn't include the time spent in fn. Returning an error from fn stops the query and returns that error.
+// WithPrefetch fetches each page while the previous is processed, holding up to two decoded pages in memory instead
+func QueryEach[E any](ctx context.Context, h *RequestHelper, q string, fn func(E) error, opts ...RequestOption) error {
+	reqUrl, err := h.dataUrl(ctx, "/query?q="+url.QueryEscape(q))
+	if err != nil {
+		return err
+	}
+	if newRequestOptions(opts).prefetch {
+		return queryEachPrefetch(ctx, h, reqUrl, q, fn)
+	}
+	for {
+		next, err := queryEachPage(ctx, h, reqUrl, q, fn)
+		if err != nil || len(next) == 0 {
+			return err
+		}
+		if reqUrl, err = h.nextRecordsUrl(ctx, next); err != nil {
+			return err
+		}
+	}
+}
+
+// queryPage a page of a query fetched in the background
+type queryPage[E any] struct {
+	resp *QueryResponse[E]
+	err  error
+}
+
+// queryEachPrefetch calls fn with each record of a page once it has been fetched, while the next page is fetched in
+// the background
+func queryEachPrefetch[E any](ctx context.Context, h *RequestHelper, reqUrl, q string, fn func(E) error) error {
+	// cancels the page being fetched ahead when fn returns an error
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fetch := func(reqUrl, q string) <-chan queryPage[E] {
+		page := make(chan queryPage[E], 1)
+		go func() {
+			resp, err := query[E](ctx, h, reqUrl, q)
+			page <- queryPage[E]{resp: resp, err: err}
+		}()
+		return page
+	}
+
+	pending := fetch(reqUrl, q)
+	for pending != nil {
+		page := <-pending
+		if page.err != nil {
+			return page.err
+		}
+		pending = nil
+		if !page.resp.Done && len(page.resp.NextRecordsUrl) > 0 {
+			nextUrl, err := h.nextRecordsUrl(ctx, page.resp.NextRecordsUrl)
+			if err != nil {
+				return err
+			}
+			pending = fetch(nextUrl, page.resp.NextRecordsUrl)
+		}
+		for _, record := range page.resp.Records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// queryEachPage reads a single page then passes its records to fn, returning the nextRecordsUrl when there are more
+// pages
+func queryEachPage[E any](ctx context.Context, h *RequestHelper, reqUrl, q string, fn func(E) error) (string, error) {
+	body, err := readQueryPage(ctx, h, reqUrl, q)
+	if err != nil {
+		return "", err
+	}
+	if h.codec != nil {
+		return codecEachPage(h, body, fn)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if err = expectDelim(dec, '{'); err != nil {
+		return "", err
+	}
+	var next string
+	done := true
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("unable to parse response body: %w", err)
+		}
+		switch key {
+		case "records":
+			if err = decodeEach(dec, fn); err != nil {
+				return "", err
+			}
+		case "nextRecordsUrl":
+			err = dec.Decode(&next)
+		case "done":
+			err = dec.Decode(&done)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return "", fmt.Errorf("unable to parse response body: %w", err)
+		}
+	}
+	if done {
+		return "", nil
+	}
+	return next, nil
+}
+
+// codecPage a page of a query with its records left undecoded
+type codecPage struct {
+	Done           bool              `json:"done"`
+	NextRecordsUrl string            `json:"nextRecordsUrl"`
+	Records        []json.RawMessage `json:"records"`
+}
+
+// codecEachPage passes the records of a page to fn, split and decoded one at a time by the JsonCodec of h, so
+// encoding/json never parses them
+func codecEachPage[E any](h *RequestHelper, body []byte, fn func(E) error) (string, error) {
+	var page codecPage
+	if err := h.unmarshal(body, &page); err != nil {
+		return "", fmt.Errorf("unable to parse response body: %w", err)
+	}
+	for i, raw := range page.Records {
+		var record E
+		if err := h.unmarshal(raw, &record); err != nil {
+			return "", fmt.Errorf("unable to parse response body: %w", err)
+		}
+		// each record is released once decoded
+		page.Records[i] = nil
+		if err := fn(record); err != nil {
+			return "", err
+		}
+	}
+	if page.Done {
+		return "", nil
+	}
+	return page.NextRecordsUrl, nil
+}
+
+// readQueryPage reads the body of a page of a query, closing it to release the request's RequestQueue worker and
+// timeout before its records are processed
+func readQueryPage(ctx context.Context, h *RequestHelper, reqUrl, q string) ([]byte, error) {
+	req, err := h.newRequest(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, newQueryError(resp, q)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response body: %w", err)
+	}
+	return body, nil
+}
+
+// decodeEach decodes the json array at the decoder's position one element at a time, calling fn with each
+func decodeEach[E any](dec *json.Decoder, fn func(E) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("unable to parse response body: %w", err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("unable to parse response body: expected array, got %v", tok)
+	}
+	for dec.More() {
+		var record E
+		if err = dec.Decode(&record); err != nil {
+			return fmt.Errorf("unable to parse response body: %w", err)
+		}
+		if err = fn(record); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectDelim reads the next token, which must be delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("unable to parse response body: %w", err)
+	}
+	if tok != delim {
+		return fmt.Errorf("unable to parse response body: expected %v, got %v", delim, tok)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryEach(t *testing.T) {
+	pagedClient := func() *HttpClientMock {
+		client := new(HttpClientMock)
+		client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(req.URL.Path, "/query/01gxx-2000") {
+				return newResponse(200, `{"totalSize":3,"done":true,"records":[{"foo":"c"}]}`), nil
+			}
+			return newResponse(200, `{"totalSize":3,"done":false,"nextRecordsUrl":"/services/data/v55.0/query/01gxx-2000","records":[{"foo":"a"},{"foo":"b"}]}`), nil
+		})
+		return client
+	}
+	stopErr := fmt.Errorf("stop")
+
+	tests := []struct {
+		name      string
+		client    *HttpClientMock
+		fn        func(got *[]string) func(recordStub) error
+		opts      []RequestOption
+		want      []string
+		wantCalls int
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "multiple pages  every record streamed",
+			client: pagedClient(),
+			fn: func(got *[]string) func(recordStub) error {
+				return func(r recordStub) error {
+					*got = append(*got, r.Foo)
+					return nil
+				}
+			},
+			want:      []string{"a", "b", "c"},
+			wantCalls: 2,
+			wantErr:   assert.NoError,
+		},
+		{
+			name:   "multiple pages with prefetch  every record streamed",
+			client: pagedClient(),
+			fn: func(got *[]string) func(recordStub) error {
+				return func(r recordStub) error {
+					*got = append(*got, r.Foo)
+					return nil
+				}
+			},
+			opts:      []RequestOption{WithPrefetch()},
+			want:      []string{"a", "b", "c"},
+			wantCalls: 2,
+			wantErr:   assert.NoError,
+		},
+		{
+			name:   "fn returns error  query stopped",
+			client: pagedClient(),
+			fn: func(got *[]string) func(recordStub) error {
+				return func(r recordStub) error {
+					*got = append(*got, r.Foo)
+					return stopErr
+				}
+			},
+			want:      []string{"a"},
+			wantCalls: 1,
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, stopErr, i...)
+			},
+		},
+		{
+			name:      "400 status code  QueryError returned",
+			client:    newHttpClientMock(newResponse(400, `[]`), nil),
+			wantCalls: 1,
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorAs(t, err, &QueryError{}, i...)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewRequestHelper(tt.client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			var got []string
+			fn := func(recordStub) error { return nil }
+			if tt.fn != nil {
+				fn = tt.fn(&got)
+			}
+			err := QueryEach(context.Background(), h, "SELECT Foo FROM Account", fn, tt.opts...)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+			tt.client.AssertNumberOfCalls(t, "Do", tt.wantCalls)
+		})
+	}
+}
+
+func TestQueryEach_WithPrefetch(t *testing.T) {
+	nextRequested := make(chan struct{})
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/query/01gxx-2000") {
+			close(nextRequested)
+			return newResponse(200, `{"totalSize":2,"done":true,"records":[{"foo":"b"}]}`), nil
+		}
+		return newResponse(200, `{"totalSize":2,"done":false,"nextRecordsUrl":"/services/data/v55.0/query/01gxx-2000","records":[{"foo":"a"}]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	var got []string
+	err := QueryEach(context.Background(), h, "SELECT Foo FROM Account", func(r recordStub) error {
+		if r.Foo == "a" {
+			// the next page is requested while the first is still being processed
+			select {
+			case <-nextRequested:
+			case <-time.After(time.Second):
+				return fmt.Errorf("next page not prefetched")
+			}
+		}
+		got = append(got, r.Foo)
+		return nil
+	}, WithPrefetch())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, got)
+}
+
+func TestQueryEach_RequestQueue(t *testing.T) {
+	q, _ := NewRequestQueue(RequestQueueParams{Workers: 1})
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case req.Method == http.MethodPatch:
+			return newResponse(http.StatusNoContent, ``), nil
+		case strings.HasSuffix(req.URL.Path, "/query/01gxx-2000"):
+			return newResponse(200, `{"totalSize":3,"done":true,"records":[{"foo":"c"}]}`), nil
+		}
+		return newResponse(200, `{"totalSize":3,"done":false,"nextRecordsUrl":"/services/data/v55.0/query/01gxx-2000","records":[{"foo":"a"},{"foo":"b"}]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	h.SetRequestQueue(q)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var got []string
+	err := QueryEach(ctx, h, "SELECT Foo FROM Account", func(r recordStub) error {
+		// the page's worker is released before its records are processed, so the only worker is free for the patch
+		if _, err := Patch(ctx, h, "Account", "001xx0000000001", map[string]any{"Foo": r.Foo}); err != nil {
+			return err
+		}
+		got = append(got, r.Foo)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, got)
+	assert.Equal(t, 0, q.running)
+}
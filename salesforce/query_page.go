@@ -0,0 +1,56 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxQueryOffset the largest OFFSET salesforce accepts in a SOQL query
+const maxQueryOffset = 2000
+
+// Page a page of a query's records, fetched with LIMIT and OFFSET by QueryPage
+type Page[E any] struct {
+	Records []E
+	// Page the number of the page, from 1
+	Page     int
+	PageSize int
+	// HasMore whether there is a page after this one
+	HasMore bool
+}
+
+// QueryPage fetches page, numbered from 1, of pageSize records of a query, adding LIMIT and OFFSET to q, for UI style
+// paging of small result sets
+// - q must not have its own LIMIT or OFFSET, and should have an ORDER BY so pages are stable
+// - salesforce caps OFFSET at 2000, an error is returned for pages past it, use QueryEach or QueryMore to read further
+func QueryPage[E any](ctx context.Context, h *RequestHelper, q string, page, pageSize int) (*Page[E], error) {
+	if page < 1 || pageSize < 1 {
+		return nil, fmt.Errorf("page and pageSize must be at least 1")
+	}
+	for _, keyword := range []string{"LIMIT", "OFFSET"} {
+		if _, after := cutTopLevel(q, keyword); len(after) > 0 {
+			return nil, fmt.Errorf("query must not have a %s, QueryPage adds one", keyword)
+		}
+	}
+	offset := (page - 1) * pageSize
+	if offset > maxQueryOffset {
+		return nil, fmt.Errorf("page %d of %d records needs an OFFSET of %d, past salesforce's maximum of %d, use QueryEach "+
+			"or QueryMore to read further", page, pageSize, offset, maxQueryOffset)
+	}
+
+	// one more record than the page is fetched, to tell whether there is another page
+	q = fmt.Sprintf("%s LIMIT %d", strings.TrimSpace(q), pageSize+1)
+	if offset > 0 {
+		q += fmt.Sprintf(" OFFSET %d", offset)
+	}
+	records, err := queryAll[E](ctx, h, q)
+	if err != nil {
+		return nil, err
+	}
+	p := &Page[E]{Records: records, Page: page, PageSize: pageSize}
+	if len(records) > pageSize {
+		p.Records = records[:pageSize]
+		p.HasMore = true
+	}
+	return p, nil
+}
@@ -0,0 +1,90 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+)
+
+func TestQueryPage(t *testing.T) {
+	type account struct {
+		Id string
+	}
+	tests := []struct {
+		name     string
+		q        string
+		page     int
+		pageSize int
+		wantQ    string
+		resp     string
+		want     *Page[account]
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "first page with more  limit without offset",
+			q:        "SELECT Id FROM Account ORDER BY Name",
+			page:     1,
+			pageSize: 2,
+			wantQ:    "SELECT Id FROM Account ORDER BY Name LIMIT 3",
+			resp:     `{"totalSize":3,"done":true,"records":[{"Id":"001xx0000000001"},{"Id":"001xx0000000002"},{"Id":"001xx0000000003"}]}`,
+			want:     &Page[account]{Records: []account{{Id: "001xx0000000001"}, {Id: "001xx0000000002"}}, Page: 1, PageSize: 2, HasMore: true},
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "last page  offset applied",
+			q:        "SELECT Id FROM Account ORDER BY Name",
+			page:     3,
+			pageSize: 2,
+			wantQ:    "SELECT Id FROM Account ORDER BY Name LIMIT 3 OFFSET 4",
+			resp:     `{"totalSize":1,"done":true,"records":[{"Id":"001xx0000000005"}]}`,
+			want:     &Page[account]{Records: []account{{Id: "001xx0000000005"}}, Page: 3, PageSize: 2},
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "offset at cap  allowed",
+			q:        "SELECT Id FROM Account",
+			page:     21,
+			pageSize: 100,
+			wantQ:    "SELECT Id FROM Account LIMIT 101 OFFSET 2000",
+			resp:     `{"totalSize":0,"done":true,"records":[]}`,
+			want:     &Page[account]{Records: []account{}, Page: 21, PageSize: 100},
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "offset past cap  error returned",
+			q:        "SELECT Id FROM Account",
+			page:     22,
+			pageSize: 100,
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "query has limit  error returned",
+			q:        "SELECT Id FROM Account LIMIT 10",
+			page:     1,
+			pageSize: 5,
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "page 0  error returned",
+			q:        "SELECT Id FROM Account",
+			pageSize: 5,
+			wantErr:  assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, tt.wantQ, req.URL.Query().Get("q"))
+				return newResponse(http.StatusOK, tt.resp), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := QueryPage[account](context.Background(), h, tt.q, tt.page, tt.pageSize)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
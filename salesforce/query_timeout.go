@@ -0,0 +1,53 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cenkalti/backoff/v4"
+	"net/url"
+)
+
+const (
+	// defaultQueryBatchSize and minQueryBatchSize the default and smallest number of records of a query's first page
+	defaultQueryBatchSize = 2000
+	minQueryBatchSize     = 200
+)
+
+// queryRetryingTimeouts runs q, retrying it with a smaller batch size, and narrowed when o has a QueryNarrower, each
+// time it fails with QUERY_TIMEOUT until the retries of o are used up, waiting between retries when h has a
+// BackoffPolicy
+func queryRetryingTimeouts[E any](ctx context.Context, h *RequestHelper, q string, o requestOptions) (*QueryResponse[E], error) {
+	batchSize := defaultQueryBatchSize
+	var b backoff.BackOff
+	if h.backoffPolicy != nil {
+		b = h.backoffPolicy.BackOff()
+	}
+	for attempt := 0; ; attempt++ {
+		reqUrl, err := h.dataUrl(ctx, "/query?q="+url.QueryEscape(q))
+		if err != nil {
+			return nil, err
+		}
+		var opts []RequestOption
+		if attempt > 0 {
+			opts = append(opts, WithHeader("Sforce-Query-Options", fmt.Sprintf("batchSize=%d", batchSize)))
+		}
+		resp, err := query[E](ctx, h, reqUrl, q, opts...)
+
+		var queryErr QueryError
+		if err == nil || attempt >= o.queryTimeoutRetries || !errors.As(err, &queryErr) || !queryErr.Timeout() {
+			return resp, err
+		}
+		if b != nil {
+			if wait := b.NextBackOff(); wait == backoff.Stop || !sleepContext(ctx, wait) {
+				return resp, err
+			}
+		}
+		batchSize = max(batchSize/2, minQueryBatchSize)
+		if o.narrowQuery != nil {
+			if q, err = o.narrowQuery(q, attempt+1); err != nil {
+				return nil, fmt.Errorf("unable to narrow query after QUERY_TIMEOUT: %w", err)
+			}
+		}
+	}
+}
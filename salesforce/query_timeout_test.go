@@ -0,0 +1,128 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+const queryTimeoutBody = `[{"message":"Your query request was running for too long.","errorCode":"QUERY_TIMEOUT"}]`
+
+func TestQuery_WithQueryTimeoutRetry(t *testing.T) {
+	type attempt struct {
+		query     string
+		batchSize string
+	}
+	tests := []struct {
+		name     string
+		retries  int
+		narrow   QueryNarrower
+		statuses []int
+		want     []attempt
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "timeout then success  retried with smaller batch size",
+			retries:  3,
+			statuses: []int{http.StatusBadRequest, http.StatusOK},
+			want:     []attempt{{"q", ""}, {"q", "batchSize=1000"}},
+			wantErr:  assert.NoError,
+		},
+		{
+			name:    "narrow hook  retried with narrowed query",
+			retries: 3,
+			narrow: func(q string, attempt int) (string, error) {
+				return q + "!", nil
+			},
+			statuses: []int{http.StatusBadRequest, http.StatusBadRequest, http.StatusOK},
+			want:     []attempt{{"q", ""}, {"q!", "batchSize=1000"}, {"q!!", "batchSize=500"}},
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "retries used up  QueryError returned",
+			retries:  1,
+			statuses: []int{http.StatusBadRequest, http.StatusBadRequest, http.StatusOK},
+			want:     []attempt{{"q", ""}, {"q", "batchSize=1000"}},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var queryErr QueryError
+				return assert.ErrorAs(t, err, &queryErr, i...) && assert.True(t, queryErr.Timeout(), i...)
+			},
+		},
+		{
+			name:    "narrow hook error  returned",
+			retries: 3,
+			narrow: func(q string, attempt int) (string, error) {
+				return "", errors.New("range too small")
+			},
+			statuses: []int{http.StatusBadRequest, http.StatusOK},
+			want:     []attempt{{"q", ""}},
+			wantErr:  assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []attempt
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				got = append(got, attempt{req.URL.Query().Get("q"), req.Header.Get("Sforce-Query-Options")})
+				if tt.statuses[len(got)-1] != http.StatusOK {
+					return newResponse(http.StatusBadRequest, queryTimeoutBody), nil
+				}
+				return newResponse(http.StatusOK, `{"totalSize":0,"done":true,"records":[]}`), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			_, err := Query[map[string]any](context.Background(), h, "q", WithQueryTimeoutRetry(tt.retries, tt.narrow))
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestQuery_ErrorCode(t *testing.T) {
+	h, _ := NewRequestHelper(newHttpClientMock(newResponse(http.StatusBadRequest, `[{"message":"bad","errorCode":"MALFORMED_QUERY"}]`), nil),
+		newTokenGetterMock("token", nil), "https://org", 55)
+
+	_, err := Query[map[string]any](context.Background(), h, "q")
+
+	var queryErr QueryError
+	if assert.ErrorAs(t, err, &queryErr) {
+		assert.Equal(t, "MALFORMED_QUERY", queryErr.ErrorCode())
+		assert.False(t, queryErr.Timeout())
+	}
+}
+
+func TestDecodeRestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+		want []restError
+	}{
+		{
+			name: "error body  errors returned",
+			resp: newResponse(http.StatusBadRequest, `[{"message":"bad","errorCode":"MALFORMED_QUERY"}]`),
+			want: []restError{{Message: "bad", ErrorCode: "MALFORMED_QUERY"}},
+		},
+		{
+			name: "no content  body not read",
+			resp: &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(nil)},
+		},
+		{
+			name: "no body  none returned",
+			resp: &http.Response{StatusCode: http.StatusBadRequest, Body: http.NoBody, ContentLength: -1},
+		},
+		{
+			name: "not json  none returned",
+			resp: newResponse(http.StatusBadRequest, "<html>"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decodeRestErrors(tt.resp))
+		})
+	}
+}
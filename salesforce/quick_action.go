@@ -0,0 +1,94 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// QuickAction a quick action of an object, listed by ListQuickActions
+type QuickAction struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	// Type e.g. Create, Update or LogACall
+	Type string `json:"type"`
+}
+
+// QuickActionDescribe the target and layout of a quick action
+type QuickActionDescribe struct {
+	Name               string `json:"name"`
+	Label              string `json:"label"`
+	Type               string `json:"type"`
+	TargetSobjectType  string `json:"targetSobjectType"`
+	TargetParentField  string `json:"targetParentField"`
+	TargetRecordTypeId string `json:"targetRecordTypeId"`
+	// Layout the fields of the action layout, as configured by admins
+	Layout json.RawMessage `json:"layout"`
+}
+
+// QuickActionResult the outcome of invoking a quick action
+type QuickActionResult struct {
+	Id          string     `json:"id"`
+	Success     bool       `json:"success"`
+	Created     bool       `json:"created"`
+	ContextId   string     `json:"contextId"`
+	FeedItemIds []string   `json:"feedItemIds"`
+	Errors      []ApiError `json:"errors"`
+}
+
+func quickActionPath(object, action string) string {
+	return fmt.Sprintf("/sobjects/%s/quickActions/%s", url.PathEscape(object), url.PathEscape(action))
+}
+
+// ListQuickActions lists the quick actions of an object, e.g. Account
+func ListQuickActions(ctx context.Context, h *RequestHelper, object string) ([]QuickAction, error) {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/sobjects/%s/quickActions", url.PathEscape(object)))
+	if err != nil {
+		return nil, err
+	}
+	actions, err := getJson[[]QuickAction](ctx, h, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	return *actions, nil
+}
+
+// DescribeQuickAction fetches the target object and layout of a quick action, e.g. Account and Account.NewCase
+func DescribeQuickAction(ctx context.Context, h *RequestHelper, object, action string) (*QuickActionDescribe, error) {
+	reqUrl, err := h.dataUrl(ctx, quickActionPath(object, action)+"/describe")
+	if err != nil {
+		return nil, err
+	}
+	return getJson[QuickActionDescribe](ctx, h, reqUrl)
+}
+
+// GetQuickActionDefaults fetches the default values of the record a quick action creates from the record contextId,
+// decoded as E, the predefined values and formulas configured by admins
+func GetQuickActionDefaults[E any](ctx context.Context, h *RequestHelper, object, action, contextId string) (*E, error) {
+	reqUrl, err := h.dataUrl(ctx, quickActionPath(object, action)+"/defaultValues/"+url.PathEscape(contextId))
+	if err != nil {
+		return nil, err
+	}
+	return getJson[E](ctx, h, reqUrl)
+}
+
+// InvokeQuickAction invokes a quick action on the record contextId with record's fields, salesforce applies the
+// action's predefined field values to fields record doesn't set. A QuickActionResult is returned even when salesforce
+// reports the action failed, along with an error
+func InvokeQuickAction(ctx context.Context, h *RequestHelper, object, action, contextId string, record any) (*QuickActionResult, error) {
+	reqUrl, err := h.dataUrl(ctx, quickActionPath(object, action))
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]any{"contextId": contextId, "record": record}
+	result, err := sendJson[QuickActionResult](ctx, h, http.MethodPost, reqUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return result, fmt.Errorf("salesforce quick action %s failed: %v", action, result.Errors)
+	}
+	return result, nil
+}
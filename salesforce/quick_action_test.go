@@ -0,0 +1,62 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestInvokeQuickAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    string
+		want    *QuickActionResult
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "action succeeds  result returned",
+			resp:    `{"id":"500xx0000000001","success":true,"created":true,"contextId":"001xx000003DGb2AAG","errors":[]}`,
+			want:    &QuickActionResult{Id: "500xx0000000001", Success: true, Created: true, ContextId: "001xx000003DGb2AAG", Errors: []ApiError{}},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "action fails  result and error returned",
+			resp:    `{"success":false,"errors":[{"statusCode":"REQUIRED_FIELD_MISSING","message":"Subject"}]}`,
+			want:    &QuickActionResult{Errors: []ApiError{{StatusCode: "REQUIRED_FIELD_MISSING", Message: "Subject"}}},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, http.MethodPost, req.Method)
+				assert.Equal(t, "/services/data/v55.0/sobjects/Account/quickActions/Account.NewCase", req.URL.Path)
+				b, _ := io.ReadAll(req.Body)
+				assert.JSONEq(t, `{"contextId":"001xx000003DGb2AAG","record":{"Subject":"Help"}}`, string(b))
+				return newResponse(http.StatusCreated, tt.resp), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := InvokeQuickAction(context.Background(), h, "Account", "Account.NewCase", "001xx000003DGb2AAG", map[string]any{"Subject": "Help"})
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetQuickActionDefaults(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/services/data/v55.0/sobjects/Account/quickActions/Account.NewCase/defaultValues/001xx000003DGb2AAG"
+	})).Return(newResponse(http.StatusOK, `{"attributes":{"type":"Case"},"foo":"default"}`), nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := GetQuickActionDefaults[recordStub](context.Background(), h, "Account", "Account.NewCase", "001xx000003DGb2AAG")
+	assert.NoError(t, err)
+	assert.Equal(t, "default", got.Foo)
+	assert.Equal(t, "Case", got.Attributes.Type)
+}
@@ -0,0 +1,90 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// systemFields the fields salesforce sets itself, which are never updateable, so are stripped from Patch and Upsert
+// bodies e.g. when a struct decoded from a query is sent back
+var systemFields = []string{
+	"attributes",
+	"Id",
+	"IsDeleted",
+	"CreatedDate",
+	"CreatedById",
+	"LastModifiedDate",
+	"LastModifiedById",
+	"SystemModstamp",
+	"LastActivityDate",
+	"LastViewedDate",
+	"LastReferencedDate",
+}
+
+// readOnlyFields the json names of the fields of struct t tagged sf:"readonly", including those of embedded structs
+func readOnlyFields(t reflect.Type) []string {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+		if f.Anonymous && len(name) == 0 {
+			fields = append(fields, readOnlyFields(f.Type)...)
+			continue
+		}
+		if len(name) == 0 {
+			name = f.Name
+		}
+		for _, opt := range sfTagOptions(f) {
+			if opt == "readonly" {
+				fields = append(fields, name)
+			}
+		}
+	}
+	return fields
+}
+
+// stripReadOnly removes the fields of record tagged sf:"readonly" from its marshaled body, along with the system
+// fields when the body is an update. body is returned unchanged when it has none of them
+func stripReadOnly(record any, body []byte, update bool) ([]byte, error) {
+	strip := readOnlyFields(reflect.TypeOf(record))
+	if update {
+		strip = append(strip, systemFields...)
+	}
+	return stripFields(body, strip)
+}
+
+// stripFields removes the fields named strip from the marshaled object body, body is returned unchanged when it has
+// none of them
+func stripFields(body []byte, strip []string) ([]byte, error) {
+	if len(strip) == 0 {
+		return body, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		// not an object, left for salesforce to reject
+		return body, nil
+	}
+	stripped := false
+	for _, name := range strip {
+		if _, ok := fields[name]; ok {
+			delete(fields, name)
+			stripped = true
+		}
+	}
+	if !stripped {
+		return body, nil
+	}
+	return json.Marshal(fields)
+}
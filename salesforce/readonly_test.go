@@ -0,0 +1,77 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+type readOnlyAudit struct {
+	CreatedDate string `json:"CreatedDate,omitempty"`
+	Legacy      string `json:"Legacy_Id__c,omitempty" sf:"readonly"`
+}
+
+type readOnlyAccount struct {
+	Attributes Attributes `json:"attributes"`
+	Id         string     `json:"Id"`
+	Name       string     `json:"Name"`
+	Score      float64    `json:"Score__c" sf:"readonly"`
+	readOnlyAudit
+}
+
+func TestStripReadOnly(t *testing.T) {
+	account := readOnlyAccount{
+		Attributes:    Attributes{Type: "Account", Url: "/services/data/v55.0/sobjects/Account/001xx0000000001"},
+		Id:            "001xx0000000001",
+		Name:          "Acme",
+		Score:         87,
+		readOnlyAudit: readOnlyAudit{CreatedDate: "2024-01-31T09:30:00.000+0000", Legacy: "A-1"},
+	}
+	tests := []struct {
+		name     string
+		send     func(h *RequestHelper) error
+		wantBody string
+	}{
+		{
+			name: "patch  system and tagged fields stripped",
+			send: func(h *RequestHelper) error {
+				_, err := Patch(context.Background(), h, "Account", "001xx0000000001", account)
+				return err
+			},
+			wantBody: `{"Name":"Acme"}`,
+		},
+		{
+			name: "patch map  system fields stripped",
+			send: func(h *RequestHelper) error {
+				_, err := Patch(context.Background(), h, "Account", "001xx0000000001", map[string]any{"Id": "001xx0000000001", "Name": "Acme"})
+				return err
+			},
+			wantBody: `{"Name":"Acme"}`,
+		},
+		{
+			name: "post  tagged fields stripped",
+			send: func(h *RequestHelper) error {
+				_, err := Post(context.Background(), h, "Account", account)
+				return err
+			},
+			wantBody: `{"attributes":{"type":"Account","url":"/services/data/v55.0/sobjects/Account/001xx0000000001"},
+				"Id":"001xx0000000001","Name":"Acme","CreatedDate":"2024-01-31T09:30:00.000+0000"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				b, _ := io.ReadAll(req.Body)
+				assert.JSONEq(t, tt.wantBody, string(b))
+				return newResponse(http.StatusCreated, `{"id":"001xx0000000001","success":true}`), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			assert.NoError(t, tt.send(h))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// RecentItem a record recently viewed or referenced by the current user
+type RecentItem struct {
+	Attributes Attributes `json:"attributes"`
+	Id         string     `json:"Id"`
+	Name       string     `json:"Name"`
+}
+
+// ListView a list view of an object
+type ListView struct {
+	Id            string `json:"id"`
+	Label         string `json:"label"`
+	DeveloperName string `json:"developerName"`
+	SObjectType   string `json:"sobjectType"`
+	// ResultsUrl the url of the list view's records
+	ResultsUrl     string `json:"resultsUrl"`
+	SoqlCompatible bool   `json:"soqlCompatible"`
+}
+
+type objectBasicInfo struct {
+	RecentItems []RecentItem `json:"recentItems"`
+}
+
+type listViewsResponse struct {
+	ListViews []ListView `json:"listviews"`
+}
+
+// ListRecentItems lists the records most recently viewed or referenced by the current user, across all objects
+// - limit caps the records returned, salesforce's default of 200 is used when 0
+func ListRecentItems(ctx context.Context, h *RequestHelper, limit int) ([]RecentItem, error) {
+	path := "/recent"
+	if limit > 0 {
+		path += fmt.Sprintf("?limit=%d", limit)
+	}
+	reqUrl, err := h.dataUrl(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	items, err := getJson[[]RecentItem](ctx, h, reqUrl)
+	if err != nil || items == nil {
+		return nil, err
+	}
+	return *items, nil
+}
+
+// ListRecentObjectItems lists the records of an object most recently viewed or referenced by the current user
+func ListRecentObjectItems(ctx context.Context, h *RequestHelper, object string) ([]RecentItem, error) {
+	reqUrl, err := h.dataUrl(ctx, "/sobjects/"+url.PathEscape(object))
+	if err != nil {
+		return nil, err
+	}
+	info, err := getJson[objectBasicInfo](ctx, h, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	return info.RecentItems, nil
+}
+
+// ListRecentListViews lists the list views of an object most recently used by the current user
+func ListRecentListViews(ctx context.Context, h *RequestHelper, object string) ([]ListView, error) {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/sobjects/%s/listviews/recent", url.PathEscape(object)))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := getJson[listViewsResponse](ctx, h, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	return resp.ListViews, nil
+}
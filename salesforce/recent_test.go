@@ -0,0 +1,85 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+)
+
+func TestListRecentItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		resp    *http.Response
+		want    []RecentItem
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:  "recent items  returned",
+			limit: 2,
+			resp: newResponse(http.StatusOK, `[{"attributes":{"type":"Account","url":"/services/data/v55.0/sobjects/Account/001xx0000000001"},
+				"Id":"001xx0000000001","Name":"Acme"},{"attributes":{"type":"Case","url":"/services/data/v55.0/sobjects/Case/500xx0000000001"},
+				"Id":"500xx0000000001","Name":"00001001"}]`),
+			want: []RecentItem{
+				{Attributes: Attributes{Type: "Account", Url: "/services/data/v55.0/sobjects/Account/001xx0000000001"}, Id: "001xx0000000001", Name: "Acme"},
+				{Attributes: Attributes{Type: "Case", Url: "/services/data/v55.0/sobjects/Case/500xx0000000001"}, Id: "500xx0000000001", Name: "00001001"},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "unauthorised  error returned",
+			limit:   2,
+			resp:    newResponse(http.StatusUnauthorized, ""),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/recent", req.URL.Path)
+				assert.Equal(t, "limit=2", req.URL.RawQuery)
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := ListRecentItems(context.Background(), h, tt.limit)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListRecentObjectItems(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/services/data/v55.0/sobjects/Account", req.URL.Path)
+		return newResponse(http.StatusOK, `{"objectDescribe":{"name":"Account"},"recentItems":[{"attributes":{"type":"Account",
+			"url":"/services/data/v55.0/sobjects/Account/001xx0000000001"},"Id":"001xx0000000001","Name":"Acme"}]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := ListRecentObjectItems(context.Background(), h, "Account")
+	assert.NoError(t, err)
+	assert.Equal(t, []RecentItem{{Attributes: Attributes{Type: "Account", Url: "/services/data/v55.0/sobjects/Account/001xx0000000001"},
+		Id: "001xx0000000001", Name: "Acme"}}, got)
+}
+
+func TestListRecentListViews(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/services/data/v55.0/sobjects/Account/listviews/recent", req.URL.Path)
+		return newResponse(http.StatusOK, `{"done":true,"listviews":[{"developerName":"RecentlyViewedAccounts","id":"00Bxx0000000001",
+			"label":"Recently Viewed Accounts","resultsUrl":"/services/data/v55.0/sobjects/Account/listviews/00Bxx0000000001/results",
+			"soqlCompatible":true,"sobjectType":"Account"}],"size":1}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := ListRecentListViews(context.Background(), h, "Account")
+	assert.NoError(t, err)
+	assert.Equal(t, []ListView{{Id: "00Bxx0000000001", Label: "Recently Viewed Accounts", DeveloperName: "RecentlyViewedAccounts",
+		SObjectType: "Account", ResultsUrl: "/services/data/v55.0/sobjects/Account/listviews/00Bxx0000000001/results",
+		SoqlCompatible: true}}, got)
+}
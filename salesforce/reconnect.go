@@ -0,0 +1,90 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cenkalti/backoff/v4"
+	"time"
+)
+
+const (
+	defaultReconnectInitial    = time.Second
+	defaultReconnectMax        = time.Minute
+	defaultReconnectMultiplier = 2
+	defaultReconnectMaxRetries = 10
+)
+
+// ReconnectBackOff the backoff between the reconnects of an event subscription run, following p. A zero field of p takes
+// the reconnect default: 1 second initially, doubling up to 1 minute with equal jitter so many subscribers don't
+// reconnect in step, and giving up after 10 consecutive failed reconnects however long they took. Reset it once an
+// event is delivered, so only consecutive failures count
+func ReconnectBackOff(p BackoffPolicy) backoff.BackOff {
+	if p.Initial <= 0 {
+		p.Initial = defaultReconnectInitial
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = defaultReconnectMax
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = defaultReconnectMultiplier
+	}
+	if p.MaxElapsed == 0 {
+		p.MaxElapsed = -1
+	}
+	if p.MaxRetries == 0 {
+		p.MaxRetries = defaultReconnectMaxRetries
+	}
+	if p.Jitter == JitterNone {
+		p.Jitter = JitterEqual
+	}
+	return p.BackOff()
+}
+
+// SubscriptionError the terminal error of an event subscription run, returned once it has stopped reconnecting, so a
+// supervisor can restart the subscriber, e.g. by exiting the process
+type SubscriptionError struct {
+	// Subscription the subscribed channel
+	Subscription string
+	// Attempts the consecutive failed connection attempts, 0 when the handler failed or credentials were rejected
+	Attempts int
+	Err      error
+}
+
+func (e SubscriptionError) Error() string {
+	if e.Attempts > 0 {
+		return fmt.Sprintf("subscription to %s stopped after %d attempts: %s", e.Subscription, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("subscription to %s stopped: %s", e.Subscription, e.Err)
+}
+
+func (e SubscriptionError) Unwrap() error {
+	return e.Err
+}
+
+// TokenRefresher a TokenGetter which can replace a token salesforce rejected, e.g. one revoked before it expired.
+// TokenCache implements it
+type TokenRefresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// Refresh fetches a new token, replacing the cached one
+func (tc TokenCache) Refresh(ctx context.Context) error {
+	_, err := tc.store(ctx)
+	return err
+}
+
+// RefreshToken refreshes the token of tg after salesforce rejected it, when tg is a TokenRefresher. An AuthError
+// rejecting the credentials is returned as a SubscriptionError, as reconnecting can't succeed
+func RefreshToken(ctx context.Context, tg TokenGetter, subscription string) error {
+	r, ok := tg.(TokenRefresher)
+	if !ok {
+		return nil
+	}
+	err := r.Refresh(ctx)
+	var authErr AuthError
+	if errors.As(err, &authErr) && authErr.rejectsCredentials() {
+		return SubscriptionError{Subscription: subscription, Err: err}
+	}
+	return nil
+}
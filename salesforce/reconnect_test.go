@@ -0,0 +1,89 @@
+package salesforce
+
+import (
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestReconnectBackOff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  BackoffPolicy
+		attempt int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "first attempt  initial delay",
+			policy:  BackoffPolicy{Initial: time.Second, MaxInterval: time.Minute},
+			attempt: 1,
+			wantMin: 500 * time.Millisecond,
+			wantMax: time.Second,
+		},
+		{
+			name:    "third attempt  doubled twice",
+			policy:  BackoffPolicy{Initial: time.Second, MaxInterval: time.Minute},
+			attempt: 3,
+			wantMin: 2 * time.Second,
+			wantMax: 4 * time.Second,
+		},
+		{
+			name:    "many attempts  capped at max",
+			policy:  BackoffPolicy{Initial: time.Second, MaxInterval: 10 * time.Second, MaxRetries: -1},
+			attempt: 50,
+			wantMin: 5 * time.Second,
+			wantMax: 10 * time.Second,
+		},
+		{
+			name:    "zero value  defaults",
+			attempt: 10,
+			wantMin: 30 * time.Second,
+			wantMax: time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := ReconnectBackOff(tt.policy)
+			var got time.Duration
+			for i := 0; i < tt.attempt; i++ {
+				got = b.NextBackOff()
+			}
+			assert.GreaterOrEqual(t, got, tt.wantMin)
+			assert.LessOrEqual(t, got, tt.wantMax)
+		})
+	}
+}
+
+func TestReconnectBackOff_MaxRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   BackoffPolicy
+		attempts int
+		wantStop bool
+	}{
+		{name: "default  10 retries", attempts: 10, wantStop: false},
+		{name: "default  stops after 10", attempts: 11, wantStop: true},
+		{name: "max retries  stops after 2", policy: BackoffPolicy{MaxRetries: 2}, attempts: 3, wantStop: true},
+		{name: "unlimited  never stops", policy: BackoffPolicy{MaxRetries: -1}, attempts: 1000, wantStop: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := ReconnectBackOff(tt.policy)
+			var got time.Duration
+			for i := 0; i < tt.attempts; i++ {
+				got = b.NextBackOff()
+			}
+			assert.Equal(t, tt.wantStop, got == backoff.Stop)
+		})
+	}
+}
+
+func TestReconnectBackOff_Reset(t *testing.T) {
+	b := ReconnectBackOff(BackoffPolicy{MaxRetries: 1})
+	assert.NotEqual(t, backoff.Stop, b.NextBackOff())
+	assert.Equal(t, backoff.Stop, b.NextBackOff())
+	b.Reset()
+	assert.NotEqual(t, backoff.Stop, b.NextBackOff())
+}
@@ -0,0 +1,48 @@
+package salesforce
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ClassicRecordUrl the classic view url of a record, which salesforce redirects to Lightning for Lightning users
+func ClassicRecordUrl(baseUrl, id string) string {
+	return strings.TrimSuffix(baseUrl, "/") + "/" + url.PathEscape(id)
+}
+
+// LightningRecordUrl the Lightning Experience view url of a record, baseUrl is the org's My Domain url, e.g.
+// https://org.my.salesforce.com, which is mapped to its lightning.force.com domain
+func LightningRecordUrl(baseUrl, object, id string) (string, error) {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return "", fmt.Errorf("invalid base url: %w", err)
+	}
+	host := u.Hostname()
+	switch {
+	case strings.HasSuffix(host, ".lightning.force.com"):
+	case strings.HasSuffix(host, ".my.salesforce.com"):
+		host = strings.TrimSuffix(host, ".my.salesforce.com") + ".lightning.force.com"
+	default:
+		return "", fmt.Errorf("base url must be a my domain url, e.g. https://org.my.salesforce.com: %s", baseUrl)
+	}
+	return fmt.Sprintf("https://%s/lightning/r/%s/%s/view", host, url.PathEscape(object), url.PathEscape(id)), nil
+}
+
+// ParseRecordUrl returns the object name and id of a REST API record url, e.g. Attributes.Url
+// /services/data/v55.0/sobjects/Account/001xx000003DGb2AAG
+func ParseRecordUrl(recordUrl string) (object, id string, err error) {
+	u, err := url.Parse(recordUrl)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid record url: %w", err)
+	}
+	_, rest, ok := strings.Cut(u.Path, "/sobjects/")
+	if !ok {
+		return "", "", fmt.Errorf("not a salesforce record url: %s", recordUrl)
+	}
+	object, id, ok = strings.Cut(rest, "/")
+	if !ok || len(object) == 0 || len(id) == 0 || strings.Contains(id, "/") {
+		return "", "", fmt.Errorf("not a salesforce record url: %s", recordUrl)
+	}
+	return object, id, nil
+}
@@ -0,0 +1,97 @@
+package salesforce
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestClassicRecordUrl(t *testing.T) {
+	assert.Equal(t, "https://org.my.salesforce.com/001xx000003DGb2AAG", ClassicRecordUrl("https://org.my.salesforce.com/", "001xx000003DGb2AAG"))
+}
+
+func TestLightningRecordUrl(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseUrl string
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "my domain  lightning url returned",
+			baseUrl: "https://org.my.salesforce.com",
+			want:    "https://org.lightning.force.com/lightning/r/Account/001xx000003DGb2AAG/view",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "sandbox my domain  lightning url returned",
+			baseUrl: "https://org--uat.sandbox.my.salesforce.com",
+			want:    "https://org--uat.sandbox.lightning.force.com/lightning/r/Account/001xx000003DGb2AAG/view",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "lightning domain  lightning url returned",
+			baseUrl: "https://org.lightning.force.com",
+			want:    "https://org.lightning.force.com/lightning/r/Account/001xx000003DGb2AAG/view",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "instance url  error returned",
+			baseUrl: "https://na1.salesforce.com",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LightningRecordUrl(tt.baseUrl, "Account", "001xx000003DGb2AAG")
+			if !tt.wantErr(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRecordUrl(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordUrl  string
+		wantObject string
+		wantId     string
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "attributes url  object and id returned",
+			recordUrl:  "/services/data/v55.0/sobjects/Account/001xx000003DGb2AAG",
+			wantObject: "Account",
+			wantId:     "001xx000003DGb2AAG",
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "absolute url  object and id returned",
+			recordUrl:  "https://org.my.salesforce.com/services/data/v55.0/sobjects/Custom__c/a01xx000003DGb2AAG",
+			wantObject: "Custom__c",
+			wantId:     "a01xx000003DGb2AAG",
+			wantErr:    assert.NoError,
+		},
+		{
+			name:      "no id  error returned",
+			recordUrl: "/services/data/v55.0/sobjects/Account",
+			wantErr:   assert.Error,
+		},
+		{
+			name:      "not a record url  error returned",
+			recordUrl: "/services/data/v55.0/query",
+			wantErr:   assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object, id, err := ParseRecordUrl(tt.recordUrl)
+			if !tt.wantErr(t, err) {
+				return
+			}
+			assert.Equal(t, tt.wantObject, object)
+			assert.Equal(t, tt.wantId, id)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package salesforce
+
+import (
+	"context"
+	"sync"
+)
+
+// ReplayStore persists the replay id of the last event handled by each subscription, so an event subscriber resumes
+// where it left off after a restart rather than missing or reprocessing events
+type ReplayStore interface {
+	// Load returns the last saved replay id of subscription, nil when none has been saved
+	Load(ctx context.Context, subscription string) ([]byte, error)
+	Save(ctx context.Context, subscription string, replayId []byte) error
+}
+
+// MemoryReplayStore a ReplayStore held in memory, replay ids survive reconnects but not restarts
+type MemoryReplayStore struct {
+	mu        sync.RWMutex
+	replayIds map[string][]byte
+}
+
+func NewMemoryReplayStore() *MemoryReplayStore {
+	return &MemoryReplayStore{replayIds: map[string][]byte{}}
+}
+
+func (s *MemoryReplayStore) Load(_ context.Context, subscription string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.replayIds[subscription], nil
+}
+
+func (s *MemoryReplayStore) Save(_ context.Context, subscription string, replayId []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replayIds[subscription] = append([]byte{}, replayId...)
+	return nil
+}
@@ -0,0 +1,24 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMemoryReplayStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryReplayStore()
+
+	got, err := s.Load(ctx, "/event/Order__e")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	replayId := []byte{1, 2}
+	assert.NoError(t, s.Save(ctx, "/event/Order__e", replayId))
+	replayId[0] = 9
+
+	got, err = s.Load(ctx, "/event/Order__e")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2}, got)
+}
@@ -0,0 +1,190 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ReportSummary a report listed by ListReports
+type ReportSummary struct {
+	Id           string `json:"id"`
+	Name         string `json:"name"`
+	Url          string `json:"url"`
+	DescribeUrl  string `json:"describeUrl"`
+	InstancesUrl string `json:"instancesUrl"`
+}
+
+// ReportFilter a filter on a report column, e.g. {Column: "ACCOUNT.NAME", Operator: "equals", Value: "Acme"}
+type ReportFilter struct {
+	Column   string `json:"column"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// ReportMetadata the definition of a report
+type ReportMetadata struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// ReportFormat TABULAR, SUMMARY, MATRIX or MULTI_BLOCK
+	ReportFormat string `json:"reportFormat"`
+	// DetailColumns the api names of the detail row columns, e.g. ACCOUNT.NAME
+	DetailColumns       []string       `json:"detailColumns"`
+	Aggregates          []string       `json:"aggregates"`
+	ReportFilters       []ReportFilter `json:"reportFilters"`
+	ReportBooleanFilter string         `json:"reportBooleanFilter,omitempty"`
+}
+
+// ReportColumn the label and data type of a report column
+type ReportColumn struct {
+	Label    string `json:"label"`
+	DataType string `json:"dataType"`
+}
+
+// ReportExtendedMetadata the labels and data types of a report's columns, by api name
+type ReportExtendedMetadata struct {
+	DetailColumnInfo    map[string]ReportColumn `json:"detailColumnInfo"`
+	AggregateColumnInfo map[string]ReportColumn `json:"aggregateColumnInfo"`
+}
+
+// ReportDescribe the metadata of a report
+type ReportDescribe struct {
+	ReportMetadata         ReportMetadata         `json:"reportMetadata"`
+	ReportExtendedMetadata ReportExtendedMetadata `json:"reportExtendedMetadata"`
+}
+
+// ReportCell a value of a report, label is the formatted value
+type ReportCell struct {
+	Label string `json:"label"`
+	Value any    `json:"value"`
+}
+
+// ReportFact the aggregates, and detail rows, of a grouping
+type ReportFact struct {
+	Aggregates []ReportCell `json:"aggregates"`
+	Rows       []struct {
+		DataCells []ReportCell `json:"dataCells"`
+	} `json:"rows"`
+}
+
+// ReportGrouping a group of a summary or matrix report, its key locates its facts in the fact map
+type ReportGrouping struct {
+	Key       string           `json:"key"`
+	Label     string           `json:"label"`
+	Value     any              `json:"value"`
+	Groupings []ReportGrouping `json:"groupings"`
+}
+
+// ReportGroupings the groupings down or across a report
+type ReportGroupings struct {
+	Groupings []ReportGrouping `json:"groupings"`
+}
+
+// ReportResult the results of a report run
+type ReportResult struct {
+	// AllData false when the detail rows were truncated, the synchronous api returns up to 2,000
+	AllData         bool                  `json:"allData"`
+	HasDetailRows   bool                  `json:"hasDetailRows"`
+	FactMap         map[string]ReportFact `json:"factMap"`
+	GroupingsDown   ReportGroupings       `json:"groupingsDown"`
+	GroupingsAcross ReportGroupings       `json:"groupingsAcross"`
+	ReportDescribe
+}
+
+// ReportRow a detail row of a report
+type ReportRow struct {
+	// Groupings the labels of the groupings down then across the row is in, empty for a tabular report
+	Groupings []string
+	// Cells the cells by detail column api name, e.g. ACCOUNT.NAME
+	Cells map[string]ReportCell
+}
+
+// reportGroupKey a leaf grouping, the fact map key part and labels of its grouping path
+type reportGroupKey struct {
+	key    string
+	labels []string
+}
+
+// leafGroupings the leaf groupings in order, depth first, or the grand total T when there are no groupings
+func leafGroupings(groupings []ReportGrouping, labels []string) []reportGroupKey {
+	if len(groupings) == 0 && len(labels) == 0 {
+		return []reportGroupKey{{key: "T"}}
+	}
+	var keys []reportGroupKey
+	for _, g := range groupings {
+		path := append(append([]string{}, labels...), g.Label)
+		if len(g.Groupings) == 0 {
+			keys = append(keys, reportGroupKey{key: g.Key, labels: path})
+			continue
+		}
+		keys = append(keys, leafGroupings(g.Groupings, path)...)
+	}
+	return keys
+}
+
+// Rows decodes the detail rows of the fact map, in grouping order, with their cells keyed by column
+func (r ReportResult) Rows() []ReportRow {
+	columns := r.ReportMetadata.DetailColumns
+	var rows []ReportRow
+	for _, down := range leafGroupings(r.GroupingsDown.Groupings, nil) {
+		for _, across := range leafGroupings(r.GroupingsAcross.Groupings, nil) {
+			fact, ok := r.FactMap[down.key+"!"+across.key]
+			if !ok {
+				continue
+			}
+			groupings := append(append([]string{}, down.labels...), across.labels...)
+			for _, row := range fact.Rows {
+				cells := make(map[string]ReportCell, len(columns))
+				for i, cell := range row.DataCells {
+					if i < len(columns) {
+						cells[columns[i]] = cell
+					}
+				}
+				rows = append(rows, ReportRow{Groupings: groupings, Cells: cells})
+			}
+		}
+	}
+	return rows
+}
+
+// ListReports lists the reports the user recently viewed
+func ListReports(ctx context.Context, h *RequestHelper) ([]ReportSummary, error) {
+	reqUrl, err := h.dataUrl(ctx, "/analytics/reports")
+	if err != nil {
+		return nil, err
+	}
+	reports, err := getJson[[]ReportSummary](ctx, h, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	return *reports, nil
+}
+
+// DescribeReport fetches the metadata of a report, its columns, groupings and filters
+func DescribeReport(ctx context.Context, h *RequestHelper, id string) (*ReportDescribe, error) {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/analytics/reports/%s/describe", url.PathEscape(id)))
+	if err != nil {
+		return nil, err
+	}
+	return getJson[ReportDescribe](ctx, h, reqUrl)
+}
+
+// RunReport runs a report synchronously, with its detail rows, see ReportResult.Rows. filters replace the report's
+// saved filters for this run. Detail rows beyond 2,000 are truncated, see ReportResult.AllData, and runs over 2
+// minutes time out, run such reports with RunReportAsync
+func RunReport(ctx context.Context, h *RequestHelper, id string, filters ...ReportFilter) (*ReportResult, error) {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/analytics/reports/%s?includeDetails=true", url.PathEscape(id)))
+	if err != nil {
+		return nil, err
+	}
+	if len(filters) == 0 {
+		return getJson[ReportResult](ctx, h, reqUrl)
+	}
+	return sendJson[ReportResult](ctx, h, http.MethodPost, reqUrl, reportFiltersBody(filters))
+}
+
+// reportFiltersBody the request body setting the filters of a report run
+func reportFiltersBody(filters []ReportFilter) map[string]any {
+	return map[string]any{"reportMetadata": map[string]any{"reportFilters": filters}}
+}
@@ -0,0 +1,99 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Report instance statuses
+const (
+	ReportInstanceNew     = "New"
+	ReportInstanceRunning = "Running"
+	ReportInstanceSuccess = "Success"
+	ReportInstanceError   = "Error"
+)
+
+// defaultReportPollInterval the wait between polls of a report instance when no interval is given
+const defaultReportPollInterval = 2 * time.Second
+
+// ReportInstance an asynchronous run of a report
+type ReportInstance struct {
+	Id             string `json:"id"`
+	Status         string `json:"status"`
+	RequestDate    string `json:"requestDate"`
+	CompletionDate string `json:"completionDate"`
+	OwnerId        string `json:"ownerId"`
+	Url            string `json:"url"`
+	HasDetailRows  bool   `json:"hasDetailRows"`
+}
+
+// ReportInstanceResult a report instance, with its results once its status is ReportInstanceSuccess
+type ReportInstanceResult struct {
+	Attributes ReportInstance `json:"attributes"`
+	ReportResult
+}
+
+// RunReportAsync queues an asynchronous run of a report with its detail rows, for reports too large or slow to run
+// synchronously. filters replace the report's saved filters for this run. Poll for the results with
+// GetReportInstance or WaitForReport
+func RunReportAsync(ctx context.Context, h *RequestHelper, id string, filters ...ReportFilter) (*ReportInstance, error) {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/analytics/reports/%s/instances?includeDetails=true", url.PathEscape(id)))
+	if err != nil {
+		return nil, err
+	}
+	var body any
+	if len(filters) > 0 {
+		body = reportFiltersBody(filters)
+	}
+	return sendJson[ReportInstance](ctx, h, http.MethodPost, reqUrl, body)
+}
+
+// ListReportInstances lists the asynchronous runs of a report, retained for 24 hours
+func ListReportInstances(ctx context.Context, h *RequestHelper, id string) ([]ReportInstance, error) {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/analytics/reports/%s/instances", url.PathEscape(id)))
+	if err != nil {
+		return nil, err
+	}
+	instances, err := getJson[[]ReportInstance](ctx, h, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	return *instances, nil
+}
+
+// GetReportInstance fetches the status of an asynchronous report run, with its results once it has succeeded
+func GetReportInstance(ctx context.Context, h *RequestHelper, id, instanceId string) (*ReportInstanceResult, error) {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/analytics/reports/%s/instances/%s", url.PathEscape(id), url.PathEscape(instanceId)))
+	if err != nil {
+		return nil, err
+	}
+	return getJson[ReportInstanceResult](ctx, h, reqUrl)
+}
+
+// WaitForReport polls an asynchronous report run every interval, 2 seconds when 0, until it completes, returning its
+// results, or an error if the run failed or ctx, or the Poll timeout of h, is done first
+func WaitForReport(ctx context.Context, h *RequestHelper, id, instanceId string, interval time.Duration) (*ReportResult, error) {
+	if interval <= 0 {
+		interval = defaultReportPollInterval
+	}
+	ctx, cancel := withTimeout(ctx, h.timeouts.Poll)
+	defer cancel()
+	for {
+		instance, err := GetReportInstance(ctx, h, id, instanceId)
+		if err != nil {
+			return nil, err
+		}
+		switch instance.Attributes.Status {
+		case ReportInstanceSuccess:
+			return &instance.ReportResult, nil
+		case ReportInstanceError:
+			return nil, fmt.Errorf("salesforce report %s instance %s failed", id, instanceId)
+		}
+		if !sleepContext(ctx, interval) {
+			return nil, ctx.Err()
+		}
+	}
+}
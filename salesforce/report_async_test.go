@@ -0,0 +1,69 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWaitForReport(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []string
+		wantRows  int
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "instance running then succeeds  results returned",
+			responses: []string{
+				`{"attributes":{"id":"0LGxx0000000001","status":"New"}}`,
+				`{"attributes":{"id":"0LGxx0000000001","status":"Running"}}`,
+				`{"attributes":{"id":"0LGxx0000000001","status":"Success"},"factMap":{"T!T":{"rows":[{"dataCells":[{"label":"Acme","value":"Acme"}]}]}},"reportMetadata":{"detailColumns":["ACCOUNT.NAME"]}}`,
+			},
+			wantRows: 1,
+			wantErr:  assert.NoError,
+		},
+		{
+			name:      "instance fails  error",
+			responses: []string{`{"attributes":{"id":"0LGxx0000000001","status":"Error"}}`},
+			wantErr:   assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/analytics/reports/00Oxx0000000001/instances/0LGxx0000000001", req.URL.Path)
+				polls++
+				return newResponse(http.StatusOK, tt.responses[polls-1]), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := WaitForReport(context.Background(), h, "00Oxx0000000001", "0LGxx0000000001", time.Millisecond)
+			tt.wantErr(t, err)
+			assert.Equal(t, len(tt.responses), polls)
+			if got != nil {
+				assert.Len(t, got.Rows(), tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestRunReportAsync(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "https://org/services/data/v55.0/analytics/reports/00Oxx0000000001/instances?includeDetails=true", req.URL.String())
+		return newResponse(http.StatusOK, `{"id":"0LGxx0000000001","status":"New","url":"/services/data/v55.0/analytics/reports/00Oxx0000000001/instances/0LGxx0000000001"}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := RunReportAsync(context.Background(), h, "00Oxx0000000001")
+	assert.NoError(t, err)
+	assert.Equal(t, "0LGxx0000000001", got.Id)
+	assert.Equal(t, ReportInstanceNew, got.Status)
+}
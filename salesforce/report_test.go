@@ -0,0 +1,104 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+const summaryReportStub = `{
+	"allData": true,
+	"hasDetailRows": true,
+	"factMap": {
+		"0!T": {"aggregates": [{"label": "2", "value": 2}], "rows": [
+			{"dataCells": [{"label": "Acme", "value": "001xx0000000001"}, {"label": "£10.00", "value": 10}]},
+			{"dataCells": [{"label": "Globex", "value": "001xx0000000002"}, {"label": "£5.00", "value": 5}]}
+		]},
+		"1!T": {"aggregates": [{"label": "1", "value": 1}], "rows": [
+			{"dataCells": [{"label": "Initech", "value": "001xx0000000003"}, {"label": "£1.00", "value": 1}]}
+		]},
+		"T!T": {"aggregates": [{"label": "3", "value": 3}], "rows": []}
+	},
+	"groupingsDown": {"groupings": [
+		{"key": "0", "label": "Retail", "value": "Retail", "groupings": []},
+		{"key": "1", "label": "Energy", "value": "Energy", "groupings": []}
+	]},
+	"groupingsAcross": {"groupings": []},
+	"reportMetadata": {"id": "00Oxx0000000001", "name": "Accounts", "reportFormat": "SUMMARY",
+		"detailColumns": ["ACCOUNT.NAME", "SALES"], "reportFilters": []},
+	"reportExtendedMetadata": {"detailColumnInfo": {"ACCOUNT.NAME": {"label": "Account Name", "dataType": "string"}}}
+}`
+
+func TestRunReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		filters    []ReportFilter
+		wantMethod string
+		wantBody   string
+	}{
+		{
+			name:       "no filters  report run with saved filters",
+			wantMethod: http.MethodGet,
+		},
+		{
+			name:       "filters  report run with filters",
+			filters:    []ReportFilter{{Column: "ACCOUNT.NAME", Operator: "equals", Value: "Acme"}},
+			wantMethod: http.MethodPost,
+			wantBody:   `{"reportMetadata":{"reportFilters":[{"column":"ACCOUNT.NAME","operator":"equals","value":"Acme"}]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, tt.wantMethod, req.Method)
+				assert.Equal(t, "https://org/services/data/v55.0/analytics/reports/00Oxx0000000001?includeDetails=true", req.URL.String())
+				if req.Body != nil {
+					b, _ := io.ReadAll(req.Body)
+					assert.JSONEq(t, tt.wantBody, string(b))
+				}
+				return newResponse(http.StatusOK, summaryReportStub), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := RunReport(context.Background(), h, "00Oxx0000000001", tt.filters...)
+			assert.NoError(t, err)
+			assert.True(t, got.AllData)
+			assert.Equal(t, "Account Name", got.ReportExtendedMetadata.DetailColumnInfo["ACCOUNT.NAME"].Label)
+
+			rows := got.Rows()
+			assert.Len(t, rows, 3)
+			assert.Equal(t, ReportRow{
+				Groupings: []string{"Retail"},
+				Cells: map[string]ReportCell{
+					"ACCOUNT.NAME": {Label: "Acme", Value: "001xx0000000001"},
+					"SALES":        {Label: "£10.00", Value: float64(10)},
+				},
+			}, rows[0])
+			assert.Equal(t, []string{"Energy"}, rows[2].Groupings)
+			assert.Equal(t, "Initech", rows[2].Cells["ACCOUNT.NAME"].Label)
+		})
+	}
+}
+
+func TestReportResult_Rows_Tabular(t *testing.T) {
+	var r ReportResult
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"factMap": {"T!T": {"rows": [{"dataCells": [{"label": "Acme", "value": "Acme"}]}]}},
+		"reportMetadata": {"detailColumns": ["ACCOUNT.NAME"]}
+	}`), &r))
+	assert.Equal(t, []ReportRow{{Groupings: []string{}, Cells: map[string]ReportCell{"ACCOUNT.NAME": {Label: "Acme", Value: "Acme"}}}}, r.Rows())
+}
+
+func TestListReports(t *testing.T) {
+	client := newHttpClientMock(newResponse(http.StatusOK, `[{"id":"00Oxx0000000001","name":"Accounts","url":"/services/data/v55.0/analytics/reports/00Oxx0000000001"}]`), nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := ListReports(context.Background(), h)
+	assert.NoError(t, err)
+	assert.Equal(t, []ReportSummary{{Id: "00Oxx0000000001", Name: "Accounts", Url: "/services/data/v55.0/analytics/reports/00Oxx0000000001"}}, got)
+}
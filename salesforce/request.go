@@ -5,9 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/cenkalti/backoff/v4"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 type TokenGetter interface {
@@ -18,19 +23,56 @@ type HttpClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// InstanceUrlGetter supplies the url of the salesforce instance, i.e. instance_url from the token response
+type InstanceUrlGetter interface {
+	InstanceUrl(ctx context.Context) (string, error)
+}
+
+// InstanceTokenGetter supplies both the auth token and the instance url it was issued for, e.g. TokenCache
+type InstanceTokenGetter interface {
+	TokenGetter
+	InstanceUrlGetter
+}
+
 // RequestHelper a helper struct for sending requests to salesforce
 // for more on this see https://ellogroup.atlassian.net/wiki/spaces/EP/pages/13402137/Salesforce+Package
 type RequestHelper struct {
-	tokenGetter TokenGetter
-	client      HttpClient
-	baseUrl     string
-	apiVersion  int
+	tokenGetter       TokenGetter
+	instanceUrlGetter InstanceUrlGetter
+	client            HttpClient
+	baseUrl           string
+	apiVersion        int
+	metrics           Metrics
+	// dataPath the path of the REST API root, defaults to /services/data
+	dataPath string
+	// sitePath the path of an Experience Cloud site, prefixed to every request path
+	sitePath string
+	// maxResponseSize the largest response body read in bytes, unlimited when 0
+	maxResponseSize int64
+	// backoffPolicy the waits between retries of transient failures, requests aren't retried when nil
+	backoffPolicy *BackoffPolicy
+	// codec the JsonCodec of request and response bodies, encoding/json when nil
+	codec JsonCodec
+	// newDecoder streams response bodies, set by SetJsonDecoder
+	newDecoder func(r io.Reader) JsonDecoder
+	// queue the RequestQueue requests wait in for a worker, sent straight away when nil
+	queue *RequestQueue
+	// timeouts the time allowed for each kind of operation, see SetTimeouts
+	timeouts Timeouts
+	// describes the ObjectDescribe of each object validated against, by name
+	describes sync.Map
 }
 
+const defaultDataPath = "/services/data"
+
 func NewRequestHelper(client HttpClient, tg TokenGetter, baseUrl string, apiVersion int) (*RequestHelper, error) {
 	if len(baseUrl) == 0 {
 		return nil, fmt.Errorf("baseUrl needs to be provided")
 	}
+	baseUrl, err := normalizeBaseUrl(baseUrl)
+	if err != nil {
+		return nil, err
+	}
 	if apiVersion <= 0 {
 		return nil, fmt.Errorf("salesfore apiVersion needs to be provided")
 	}
@@ -45,100 +87,480 @@ func NewRequestHelper(client HttpClient, tg TokenGetter, baseUrl string, apiVers
 	}, nil
 }
 
+// normalizeBaseUrl checks baseUrl is the root of a salesforce instance e.g. https://org.my.salesforce.com, stripping
+// any trailing slash, so misconfiguration fails on startup rather than as a 404 on every request
+func normalizeBaseUrl(baseUrl string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(baseUrl))
+	if err != nil {
+		return "", fmt.Errorf("invalid baseUrl: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("invalid baseUrl %q: scheme must be https, e.g. https://org.my.salesforce.com", baseUrl)
+	}
+	if len(u.Host) == 0 {
+		return "", fmt.Errorf("invalid baseUrl %q: host needs to be provided", baseUrl)
+	}
+	if len(u.RawQuery) > 0 || len(u.Fragment) > 0 {
+		return "", fmt.Errorf("invalid baseUrl %q: must not have a query or fragment", baseUrl)
+	}
+	host := strings.ToLower(u.Hostname())
+	if strings.HasSuffix(host, ".lightning.force.com") || strings.HasSuffix(host, ".visual.force.com") {
+		return "", fmt.Errorf("invalid baseUrl %q: %s is a Lightning UI domain, use the org's my.salesforce.com domain "+
+			"e.g. https://%s.my.salesforce.com", baseUrl, host, strings.Split(host, ".")[0])
+	}
+	if strings.Contains(u.Path, "/services/") {
+		return "", fmt.Errorf("invalid baseUrl %q: must be the instance root, without /services/data", baseUrl)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	return u.String(), nil
+}
+
+// NewRequestHelperWithInstanceUrl creates a RequestHelper which sends requests to the instance url returned by the
+// token endpoint, rather than a separately configured baseUrl
+func NewRequestHelperWithInstanceUrl(client HttpClient, tg InstanceTokenGetter, apiVersion int) (*RequestHelper, error) {
+	if apiVersion <= 0 {
+		return nil, fmt.Errorf("salesfore apiVersion needs to be provided")
+	}
+	if tg == nil {
+		return nil, fmt.Errorf("tokenGetter needs to be provided")
+	}
+	return &RequestHelper{
+		tokenGetter:       tg,
+		instanceUrlGetter: tg,
+		client:            client,
+		apiVersion:        apiVersion,
+	}, nil
+}
+
+// SetMetrics records a count and latency for every request sent to salesforce
+func (h *RequestHelper) SetMetrics(m Metrics) *RequestHelper {
+	h.metrics = m
+	return h
+}
+
+// SetMaxResponseSize limits the response bodies read to n bytes, a ResponseTooLargeError is returned for larger
+// responses rather than reading them into memory, e.g. to protect a Lambda from an unexpectedly large query. 0 removes
+// the limit
+func (h *RequestHelper) SetMaxResponseSize(n int64) *RequestHelper {
+	h.maxResponseSize = n
+	return h
+}
+
+// resolveBaseUrl returns the configured baseUrl, falling back to the instance url when one is not configured
+func (h *RequestHelper) resolveBaseUrl(ctx context.Context) (string, error) {
+	if len(h.baseUrl) > 0 || h.instanceUrlGetter == nil {
+		return h.baseUrl, nil
+	}
+	instanceUrl, err := h.instanceUrlGetter.InstanceUrl(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to get salesforce instance url: %w", err)
+	}
+	return instanceUrl, nil
+}
+
+// SetSitePath sends requests under an Experience Cloud site path e.g. /mycommunity/services/data, for guest and
+// community user integrations
+func (h *RequestHelper) SetSitePath(path string) *RequestHelper {
+	h.sitePath = strings.TrimRight("/"+strings.Trim(path, "/"), "/")
+	return h
+}
+
+// instanceUrl returns the url of a path relative to the instance root, or the Experience Cloud site if one is set,
+// e.g. /services/oauth2/userinfo
+func (h *RequestHelper) instanceUrl(ctx context.Context, path string) (string, error) {
+	baseUrl, err := h.resolveBaseUrl(ctx)
+	if err != nil {
+		return "", err
+	}
+	return baseUrl + h.sitePath + path, nil
+}
+
+// SetDataPath changes the path of the REST API root from /services/data, for requests sent through an API gateway
+// which maps its own path onto salesforce's e.g. /salesforce/services/data
+func (h *RequestHelper) SetDataPath(path string) *RequestHelper {
+	h.dataPath = "/" + strings.Trim(path, "/")
+	return h
+}
+
+// servicesDataPath the path of the REST API root, /services/data unless set with SetDataPath
+func (h *RequestHelper) servicesDataPath() string {
+	if len(h.dataPath) == 0 {
+		return defaultDataPath
+	}
+	return h.dataPath
+}
+
+// dataUrl returns the url of a path relative to the versioned REST API root, e.g. /services/data/v55.0
+func (h *RequestHelper) dataUrl(ctx context.Context, path string) (string, error) {
+	return h.instanceUrl(ctx, fmt.Sprintf("%s/v%d.0%s", h.servicesDataPath(), h.apiVersion, path))
+}
+
+// newRequest creates a request to reqUrl with the auth token and json content type headers set
+func (h *RequestHelper) newRequest(ctx context.Context, method, reqUrl string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, reqUrl, body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create salesforce request: %w", err)
+	}
+
+	tokenCtx, cancel := withTimeout(ctx, h.timeouts.Token)
+	defer cancel()
+	token, err := h.tokenGetter.Get(tokenCtx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create salesforce auth token: %w", err)
+	}
+	req.Header = http.Header{
+		"Content-Type":  {"application/json"},
+		"Authorization": {"Bearer " + token},
+	}
+	return req, nil
+}
+
+// do sends req with the http client, recording the request metrics, retrying transient failures when h has a
+// BackoffPolicy
+func (h *RequestHelper) do(req *http.Request) (*http.Response, error) {
+	if h.backoffPolicy == nil || !retryable(req) {
+		return h.doOnce(req)
+	}
+	b := h.backoffPolicy.BackOff()
+	for {
+		resp, err := h.doOnce(req)
+		if !transientFailure(resp, err) {
+			return resp, err
+		}
+		wait := b.NextBackOff()
+		if wait == backoff.Stop {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if !sleepContext(req.Context(), wait) {
+			return nil, req.Context().Err()
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, fmt.Errorf("unable to create salesforce request: %w", err)
+			}
+		}
+	}
+}
+
+// doOnce sends req with the http client once, recording the request metrics. A request sent through a RequestQueue
+// holds its worker until the response body is closed
+func (h *RequestHelper) doOnce(req *http.Request) (*http.Response, error) {
+	ctx, cancelTimeout := withTimeout(req.Context(), h.requestTimeout(req))
+	cancel := cancelTimeout
+	// a long poll would hold a worker until events arrive, so isn't queued
+	if h.queue != nil && !longPoll(req) {
+		release, err := h.queue.acquire(ctx, priorityOf(ctx))
+		if err != nil {
+			cancelTimeout()
+			return nil, err
+		}
+		cancel = sync.OnceFunc(func() {
+			cancelTimeout()
+			release()
+		})
+	}
+	start := time.Now()
+	resp, err := h.client.Do(req.WithContext(ctx))
+	recordResponseMeta(ctx, resp, time.Since(start))
+	if err != nil || resp.Body == nil {
+		cancel()
+	} else {
+		resp.Body = cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	}
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	if h.metrics != nil {
+		tags := map[string]string{"method": req.Method, "status": status}
+		h.metrics.Count(MetricRequest, 1, tags)
+		h.metrics.Timing(MetricRequestLatency, time.Since(start), tags)
+	}
+	if err != nil || h.maxResponseSize <= 0 {
+		return resp, err
+	}
+	if resp.ContentLength > h.maxResponseSize {
+		resp.Body.Close()
+		return nil, ResponseTooLargeError{Limit: h.maxResponseSize}
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: h.maxResponseSize, limit: h.maxResponseSize}
+	return resp, nil
+}
+
 type QueryError struct {
 	queryUsed  string
 	statusCode int
+	// errorCode the salesforce error code of the response, e.g. QUERY_TIMEOUT, when it has one
+	errorCode string
 }
 
 func (q QueryError) Error() string {
+	if len(q.errorCode) > 0 {
+		return fmt.Sprintf("error querying salesforce - status code: %v, error code: %v, query: %v", q.statusCode, q.errorCode, q.queryUsed)
+	}
 	return fmt.Sprintf("error querying salesforce - status code: %v, query: %v", q.statusCode, q.queryUsed)
 }
 
+// ErrorCode the salesforce error code of the failed query, e.g. MALFORMED_QUERY, empty when the response had none
+func (q QueryError) ErrorCode() string {
+	return q.errorCode
+}
+
+// Timeout whether the query failed as it took too long, e.g. filtering on an unindexed field of a large object
+func (q QueryError) Timeout() bool {
+	return q.errorCode == "QUERY_TIMEOUT"
+}
+
+// newQueryError a QueryError for a failed query response, with the error code of its body when it has one
+func newQueryError(resp *http.Response, q string) QueryError {
+	e := QueryError{statusCode: resp.StatusCode, queryUsed: q}
+	if restErrs := decodeRestErrors(resp); len(restErrs) > 0 {
+		e.errorCode = restErrs[0].ErrorCode
+	}
+	return e
+}
+
+// decodeRestErrors the errors in the body of a failed response, none when the response has no body, i.e. a
+// ContentLength of 0, or it can't be parsed. The error code is only extra detail, so it never fails the caller
+func decodeRestErrors(resp *http.Response) []restError {
+	if resp.Body == nil || resp.Body == http.NoBody || resp.ContentLength == 0 {
+		return nil
+	}
+	var restErrs []restError
+	if json.NewDecoder(resp.Body).Decode(&restErrs) != nil {
+		return nil
+	}
+	return restErrs
+}
+
 // Query salesforce in a generic way
 // - uses the baseUrl, tokenGetter and http client on RequestHelper to query salesforce
 // - QueryError returned if status code != 200 with status code of response
-func Query[E any](ctx context.Context, h *RequestHelper, q string) (*QueryResponse[E], error) {
-	reqUrl := fmt.Sprintf("%s/services/data/v%d.0/query?q=%s", h.baseUrl, h.apiVersion, url.QueryEscape(q))
-	req, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+// - WithQueryTimeoutRetry retries a query which fails with QUERY_TIMEOUT
+func Query[E any](ctx context.Context, h *RequestHelper, q string, opts ...RequestOption) (*QueryResponse[E], error) {
+	o := newRequestOptions(opts)
+	reqUrl, err := h.dataUrl(ctx, "/query?q="+url.QueryEscape(q))
 	if err != nil {
-		return nil, fmt.Errorf("unable to create salesforce request: %w", err)
+		return nil, err
+	}
+	if o.queryTimeoutRetries == 0 {
+		return query[E](ctx, h, reqUrl, q)
 	}
+	return queryRetryingTimeouts[E](ctx, h, q, o)
+}
 
-	token, err := h.tokenGetter.Get(ctx)
+// QueryMore fetches the next page of a query, or of a truncated subquery, from its nextRecordsUrl
+func QueryMore[E any](ctx context.Context, h *RequestHelper, nextRecordsUrl string) (*QueryResponse[E], error) {
+	if len(nextRecordsUrl) == 0 {
+		return nil, fmt.Errorf("next records url needs to be provided")
+	}
+	reqUrl, err := h.nextRecordsUrl(ctx, nextRecordsUrl)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create salesforce auth token: %w", err)
+		return nil, err
 	}
-	req.Header = http.Header{
-		"Content-Type":  {"application/json"},
-		"Authorization": {"Bearer " + token},
+	return query[E](ctx, h, reqUrl, nextRecordsUrl)
+}
+
+// nextRecordsUrl the url of a query's next page, nextRecordsUrl is relative to /services/data which may be mapped
+// elsewhere by SetDataPath
+func (h *RequestHelper) nextRecordsUrl(ctx context.Context, nextRecordsUrl string) (string, error) {
+	return h.instanceUrl(ctx, h.servicesDataPath()+strings.TrimPrefix(nextRecordsUrl, defaultDataPath))
+}
+
+// query sends a query request to reqUrl, q is used in the QueryError. WithHeader sets headers on the request
+func query[E any](ctx context.Context, h *RequestHelper, reqUrl, q string, opts ...RequestOption) (*QueryResponse[E], error) {
+	req, err := h.newRequest(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return nil, err
 	}
+	newRequestOptions(opts).setHeaders(req)
 
-	resp, err := h.client.Do(req)
+	resp, err := h.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		return nil, QueryError{statusCode: resp.StatusCode, queryUsed: q}
+		return nil, newQueryError(resp, q)
 	}
-	resBody, err := io.ReadAll(resp.Body)
+
+	// decoded from the body directly, as reading it first doubles the memory used by large pages, unless a JsonCodec
+	// is set without a JsonDecoder
+	var parsedResp *QueryResponse[E]
+	if err = h.decode(resp.Body, &parsedResp); err != nil {
+		return nil, err
+	}
+	return parsedResp, nil
+}
+
+// Get fetches a single record by id
+// - uses the baseUrl, tokenGetter and http client on RequestHelper
+// - fields limits the fields returned, all fields are returned when none are given
+func Get[E any](ctx context.Context, h *RequestHelper, name, id string, fields ...string) (*E, error) {
+	path := fmt.Sprintf("/sobjects/%s/%s", name, id)
+	if len(fields) > 0 {
+		path += "?fields=" + url.QueryEscape(strings.Join(fields, ","))
+	}
+	reqUrl, err := h.dataUrl(ctx, path)
 	if err != nil {
 		return nil, err
 	}
+	return getJson[E](ctx, h, reqUrl)
+}
+
+// Exists whether the record with id exists, and is visible to the running user, fetching only its Id
+func Exists(ctx context.Context, h *RequestHelper, name, id string) (bool, error) {
+	if !ValidId(id) {
+		return false, fmt.Errorf("invalid id %q", id)
+	}
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/sobjects/%s/%s?fields=Id", name, id))
+	if err != nil {
+		return false, err
+	}
+	req, err := h.newRequest(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := h.do(req)
+	if err != nil {
+		return false, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
 	defer resp.Body.Close()
 
-	var parsedResp *QueryResponse[E]
-	if err = json.Unmarshal(resBody, &parsedResp); err != nil {
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+}
+
+// getJson sends a get request to reqUrl, decoding the json response as E
+func getJson[E any](ctx context.Context, h *RequestHelper, reqUrl string) (*E, error) {
+	req, err := h.newRequest(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse response body: %w", err)
+	}
+
+	var parsedResp *E
+	if err = h.unmarshal(resBody, &parsedResp); err != nil {
 		return nil, err
 	}
 	return parsedResp, nil
 }
 
+// sendJson sends a request to reqUrl with body, when not nil, as json, decoding the json response as E
+func sendJson[E any](ctx context.Context, h *RequestHelper, method, reqUrl string, body any) (*E, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := h.marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := h.newRequest(ctx, method, reqUrl, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+
+	var parsedResp *E
+	if err = h.decode(resp.Body, &parsedResp); err != nil {
+		return nil, fmt.Errorf("unable to parse response body: %w", err)
+	}
+	return parsedResp, nil
+}
+
 // Post sends a post request to salesforce to create an object
 // - uses the baseUrl, tokenGetter and http client on RequestHelper
 // - returns the id of the newly created object
-func Post(ctx context.Context, h *RequestHelper, name string, record any) (string, error) {
-	reqUrl := fmt.Sprintf("%s/services/data/v%d.0/sobjects/%s", h.baseUrl, h.apiVersion, name)
-
-	reqBody, err := json.Marshal(record)
+// - WithValidation checks the record against the object's describe before it is sent, and WithWritableFieldsOnly drops
+// the fields the running user can't create
+// - WithHeader and WithAutoAssign set headers, e.g. to run an assignment rule on a created Case
+// - a CreateError with the errors salesforce reported is returned when the record isn't created
+func Post(ctx context.Context, h *RequestHelper, name string, record any, opts ...RequestOption) (string, error) {
+	o := newRequestOptions(opts)
+	reqUrl, err := h.dataUrl(ctx, "/sobjects/"+name)
 	if err != nil {
-		return "", fmt.Errorf("unable to create salesforce payload: %w", err)
+		return "", err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewReader(reqBody))
+	reqBody, err := h.marshal(record)
 	if err != nil {
-		return "", fmt.Errorf("unable to create salesforce request: %w", err)
+		return "", fmt.Errorf("unable to create salesforce payload: %w", err)
 	}
-	token, err := h.tokenGetter.Get(ctx)
+	reqBody, err = stripReadOnly(record, reqBody, false)
 	if err != nil {
-		return "", fmt.Errorf("unable to create salesforce auth token: %w", err)
+		return "", fmt.Errorf("unable to create salesforce payload: %w", err)
 	}
-	req.Header = http.Header{
-		"Content-Type":  {"application/json"},
-		"Authorization": {"Bearer " + token},
+	if reqBody, err = o.prepareBody(ctx, h, name, reqBody, false); err != nil {
+		return "", err
 	}
 
-	resp, err := h.client.Do(req)
+	req, err := h.newRequest(ctx, http.MethodPost, reqUrl, bytes.NewReader(reqBody))
+	if err != nil {
+		return "", err
+	}
+	o.setHeaders(req)
+
+	resp, err := h.do(req)
 	if err != nil {
 		return "", fmt.Errorf("unable to send request to salesforce: %w", err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return "", fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+		var errBody []byte
+		if resp.Body != nil {
+			errBody, _ = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+		return "", newCreateError(name, resp.StatusCode, errBody)
 	}
+	defer resp.Body.Close()
 
 	resBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", fmt.Errorf("unable to parse response body: %w", err)
 	}
-	defer resp.Body.Close()
 
 	var parsedResp *PostResponse
-	if err = json.Unmarshal(resBody, &parsedResp); err != nil {
+	if err = h.unmarshal(resBody, &parsedResp); err != nil {
 		return "", err
 	}
 
 	if !parsedResp.Success {
-		return "", fmt.Errorf("salesforce returns a failure result: %s", resBody)
+		return "", newCreateError(name, resp.StatusCode, resBody)
 	}
 
 	return parsedResp.Id, nil
@@ -147,32 +569,42 @@ func Post(ctx context.Context, h *RequestHelper, name string, record any) (strin
 // Patch sends a patch request to salesforce to update an object
 // - uses the baseUrl, tokenGetter and http client on RequestHelper to query salesforce
 // - returns the status code in the response, as patch requests could result in 200, 201 or 204
-func Patch(ctx context.Context, h *RequestHelper, name, id string, record any) (int, error) {
-	reqUrl := fmt.Sprintf("%s/services/data/v%d.0/sobjects/%s/%s", h.baseUrl, h.apiVersion, name, id)
+// - WithFieldMask limits the fields sent
+// - WithValidation checks the fields sent against the object's describe before they are sent, and
+// WithWritableFieldsOnly drops the fields the running user can't update
+// - WithHeader and WithAutoAssign set headers
+func Patch(ctx context.Context, h *RequestHelper, name, id string, record any, opts ...RequestOption) (int, error) {
+	o := newRequestOptions(opts)
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/sobjects/%s/%s", name, id))
+	if err != nil {
+		return 0, err
+	}
 
-	reqBody, err := json.Marshal(record)
+	reqBody, err := o.marshalRecord(h, record)
 	if err != nil {
 		return 0, fmt.Errorf("unable to create salesforce payload: %w", err)
 	}
-
-	req, err := http.NewRequest(http.MethodPatch, reqUrl, bytes.NewReader(reqBody))
+	reqBody, err = stripReadOnly(record, reqBody, true)
 	if err != nil {
-		return 0, fmt.Errorf("unable to create salesforce request: %w", err)
+		return 0, fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	if reqBody, err = o.prepareBody(ctx, h, name, reqBody, true); err != nil {
+		return 0, err
 	}
 
-	token, err := h.tokenGetter.Get(ctx)
+	req, err := h.newRequest(ctx, http.MethodPatch, reqUrl, bytes.NewReader(reqBody))
 	if err != nil {
-		return 0, fmt.Errorf("unable to create salesforce auth token: %w", err)
-	}
-	req.Header = http.Header{
-		"Content-Type":  {"application/json"},
-		"Authorization": {"Bearer " + token},
+		return 0, err
 	}
+	o.setHeaders(req)
 
-	resp, err := h.client.Do(req)
+	resp, err := h.do(req)
 	if err != nil {
 		return 0, fmt.Errorf("unable to send request to salesforce: %w", err)
 	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		return resp.StatusCode, fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
@@ -181,29 +613,84 @@ func Patch(ctx context.Context, h *RequestHelper, name, id string, record any) (
 	return resp.StatusCode, nil
 }
 
-// Delete sends a delete request to salesforce to delete an object
+// Upsert sends a patch request to salesforce to create or update an object by an external id field
 // - uses the baseUrl, tokenGetter and http client on RequestHelper
-func Delete(ctx context.Context, h *RequestHelper, name, id string) error {
-	reqUrl := fmt.Sprintf("%s/services/data/v%d.0/sobjects/%s/%s", h.baseUrl, h.apiVersion, name, id)
+// - the UpsertResponse Created field reports whether a new object was created
+// - WithFieldMask, WithValidation, WithWritableFieldsOnly and the header options apply as they do to Patch
+func Upsert(ctx context.Context, h *RequestHelper, name, extIdField, extId string, record any, opts ...RequestOption) (*UpsertResponse, error) {
+	o := newRequestOptions(opts)
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/sobjects/%s/%s/%s", name, extIdField, url.PathEscape(extId)))
+	if err != nil {
+		return nil, err
+	}
 
-	req, err := http.NewRequest(http.MethodDelete, reqUrl, nil)
+	reqBody, err := o.marshalRecord(h, record)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	reqBody, err = stripReadOnly(record, reqBody, true)
 	if err != nil {
-		return fmt.Errorf("unable to create salesforce request: %w", err)
+		return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	if reqBody, err = o.prepareBody(ctx, h, name, reqBody, true); err != nil {
+		return nil, err
 	}
 
-	token, err := h.tokenGetter.Get(ctx)
+	req, err := h.newRequest(ctx, http.MethodPatch, reqUrl, bytes.NewReader(reqBody))
 	if err != nil {
-		return fmt.Errorf("unable to create salesforce auth token: %w", err)
+		return nil, err
 	}
-	req.Header = http.Header{
-		"Content-Type":  {"application/json"},
-		"Authorization": {"Bearer " + token},
+	o.setHeaders(req)
+
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+	// older api versions respond to an update with no content
+	if resp.StatusCode == http.StatusNoContent {
+		return &UpsertResponse{Success: true}, nil
+	}
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse response body: %w", err)
+	}
+
+	var parsedResp *UpsertResponse
+	if err = h.unmarshal(resBody, &parsedResp); err != nil {
+		return nil, err
+	}
+	if !parsedResp.Success {
+		return nil, fmt.Errorf("salesforce returns a failure result: %s", resBody)
+	}
+	return parsedResp, nil
+}
+
+// Delete sends a delete request to salesforce to delete an object
+// - uses the baseUrl, tokenGetter and http client on RequestHelper
+func Delete(ctx context.Context, h *RequestHelper, name, id string) error {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/sobjects/%s/%s", name, id))
+	if err != nil {
+		return err
 	}
 
-	resp, err := h.client.Do(req)
+	req, err := h.newRequest(ctx, http.MethodDelete, reqUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.do(req)
 	if err != nil {
 		return fmt.Errorf("unable to send request to salesforce: %w", err)
 	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		return fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
@@ -0,0 +1,186 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Priority the class of a request sent through a RequestQueue
+type Priority int
+
+const (
+	// PriorityInteractive a customer facing request, the default, dispatched ahead of any batch request
+	PriorityInteractive Priority = iota
+	// PriorityBatch a background request, e.g. a backfill, dispatched when no interactive request is waiting and kept
+	// to the queue's batch workers
+	PriorityBatch
+)
+
+// ErrRequestQueueFull returned when a request can't wait for a worker as the RequestQueue already has its maximum
+// number of requests waiting
+var ErrRequestQueueFull = errors.New("salesforce request queue is full")
+
+type priorityKey struct{}
+
+// WithPriority marks the requests sent with ctx as priority p, for the RequestQueue of their RequestHelper
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// priorityOf the priority of the requests sent with ctx, PriorityInteractive unless set with WithPriority
+func priorityOf(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && p == PriorityBatch {
+		return PriorityBatch
+	}
+	return PriorityInteractive
+}
+
+// RequestQueueParams the size of a RequestQueue
+type RequestQueueParams struct {
+	// Workers the requests sent at once, required
+	Workers int
+	// BatchWorkers the batch requests sent at once, so some workers are always free for interactive requests,
+	// defaults to Workers-1, or 1 when Workers is 1
+	BatchWorkers int
+	// MaxQueued the requests waiting for a worker before ErrRequestQueueFull is returned, unlimited when 0
+	MaxQueued int
+}
+
+// RequestQueue a bounded pool of workers all the requests for an org are sent through, so background backfills
+// can't starve customer facing requests of the org's concurrency and API quota. Share one RequestQueue between every
+// RequestHelper of an org, e.g. those of per-user tokens
+type RequestQueue struct {
+	workers      int
+	batchWorkers int
+	maxQueued    int
+
+	mu           sync.Mutex
+	running      int
+	runningBatch int
+	waiting      [2][]*queueWaiter
+}
+
+// queueWaiter a request waiting for a worker, ready is closed once it has one
+type queueWaiter struct {
+	priority Priority
+	ready    chan struct{}
+}
+
+// NewRequestQueue creates a RequestQueue, set it on a RequestHelper with SetRequestQueue
+func NewRequestQueue(p RequestQueueParams) (*RequestQueue, error) {
+	if p.Workers <= 0 {
+		return nil, errors.New("request queue Workers needs to be provided")
+	}
+	batchWorkers := p.BatchWorkers
+	if batchWorkers <= 0 || batchWorkers > p.Workers {
+		batchWorkers = max(p.Workers-1, 1)
+	}
+	return &RequestQueue{workers: p.Workers, batchWorkers: batchWorkers, maxQueued: p.MaxQueued}, nil
+}
+
+// SetRequestQueue sends every request of h through q, each waits for a worker in its WithPriority class
+func (h *RequestHelper) SetRequestQueue(q *RequestQueue) *RequestHelper {
+	h.queue = q
+	return h
+}
+
+// Queued the number of requests waiting for a worker
+func (q *RequestQueue) Queued() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.waiting[PriorityInteractive]) + len(q.waiting[PriorityBatch])
+}
+
+// acquire waits for a worker for a request of priority p, returning the func releasing it, or an error if the queue
+// is full or ctx is done first
+func (q *RequestQueue) acquire(ctx context.Context, p Priority) (func(), error) {
+	release := func() { q.release(p) }
+
+	q.mu.Lock()
+	if q.canStart(p) && q.noneAhead(p) {
+		q.start(p)
+		q.mu.Unlock()
+		return release, nil
+	}
+	if q.maxQueued > 0 && len(q.waiting[PriorityInteractive])+len(q.waiting[PriorityBatch]) >= q.maxQueued {
+		q.mu.Unlock()
+		return nil, ErrRequestQueueFull
+	}
+	w := &queueWaiter{priority: p, ready: make(chan struct{})}
+	q.waiting[p] = append(q.waiting[p], w)
+	q.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return release, nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if q.remove(w) {
+			return nil, ctx.Err()
+		}
+		// a worker was handed over as ctx finished, pass it on
+		q.releaseLocked(p)
+		return nil, ctx.Err()
+	}
+}
+
+// release frees the worker of a request of priority p, handing it to the next waiting request
+func (q *RequestQueue) release(p Priority) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.releaseLocked(p)
+}
+
+// releaseLocked release with q.mu held
+func (q *RequestQueue) releaseLocked(p Priority) {
+	q.running--
+	if p == PriorityBatch {
+		q.runningBatch--
+	}
+	q.dispatch()
+}
+
+// canStart whether a worker is free for a request of priority p
+func (q *RequestQueue) canStart(p Priority) bool {
+	return q.running < q.workers && (p == PriorityInteractive || q.runningBatch < q.batchWorkers)
+}
+
+// noneAhead whether no request which would be dispatched before one of priority p is waiting
+func (q *RequestQueue) noneAhead(p Priority) bool {
+	if p == PriorityBatch {
+		return len(q.waiting[PriorityInteractive]) == 0 && len(q.waiting[PriorityBatch]) == 0
+	}
+	return len(q.waiting[PriorityInteractive]) == 0
+}
+
+func (q *RequestQueue) start(p Priority) {
+	q.running++
+	if p == PriorityBatch {
+		q.runningBatch++
+	}
+}
+
+// dispatch hands free workers to waiting requests, interactive requests first
+func (q *RequestQueue) dispatch() {
+	for _, p := range []Priority{PriorityInteractive, PriorityBatch} {
+		for len(q.waiting[p]) > 0 && q.canStart(p) {
+			w := q.waiting[p][0]
+			q.waiting[p] = q.waiting[p][1:]
+			q.start(p)
+			close(w.ready)
+		}
+	}
+}
+
+// remove removes w from the waiting requests, returning false if it had already been dispatched
+func (q *RequestQueue) remove(w *queueWaiter) bool {
+	for i, waiting := range q.waiting[w.priority] {
+		if waiting == w {
+			q.waiting[w.priority] = append(q.waiting[w.priority][:i], q.waiting[w.priority][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
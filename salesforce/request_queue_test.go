@@ -0,0 +1,169 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewRequestQueue(t *testing.T) {
+	tests := []struct {
+		name             string
+		p                RequestQueueParams
+		wantBatchWorkers int
+		wantErr          assert.ErrorAssertionFunc
+	}{
+		{name: "no workers  error returned", p: RequestQueueParams{}, wantErr: assert.Error},
+		{name: "default batch workers  one kept for interactive", p: RequestQueueParams{Workers: 4}, wantBatchWorkers: 3, wantErr: assert.NoError},
+		{name: "one worker  shared", p: RequestQueueParams{Workers: 1}, wantBatchWorkers: 1, wantErr: assert.NoError},
+		{name: "batch workers set  used", p: RequestQueueParams{Workers: 4, BatchWorkers: 2}, wantBatchWorkers: 2, wantErr: assert.NoError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := NewRequestQueue(tt.p)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantBatchWorkers, q.batchWorkers)
+		})
+	}
+}
+
+func TestRequestQueue_InteractiveFirst(t *testing.T) {
+	q, _ := NewRequestQueue(RequestQueueParams{Workers: 1})
+	ctx := context.Background()
+
+	release, err := q.acquire(ctx, PriorityBatch)
+	assert.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+	for i, p := range []Priority{PriorityBatch, PriorityInteractive} {
+		wg.Add(1)
+		go func(p Priority) {
+			defer wg.Done()
+			r, err := q.acquire(ctx, p)
+			assert.NoError(t, err)
+			mu.Lock()
+			order = append(order, p)
+			mu.Unlock()
+			r()
+		}(p)
+		assert.Eventually(t, func() bool { return q.Queued() == i+1 }, time.Second, time.Millisecond)
+	}
+	release()
+	wg.Wait()
+
+	assert.Equal(t, []Priority{PriorityInteractive, PriorityBatch}, order)
+}
+
+func TestRequestQueue_BatchWorkers(t *testing.T) {
+	q, _ := NewRequestQueue(RequestQueueParams{Workers: 2, MaxQueued: 1})
+	ctx := context.Background()
+
+	_, err := q.acquire(ctx, PriorityBatch)
+	assert.NoError(t, err)
+
+	// the second worker is kept for interactive requests
+	releaseInteractive, err := q.acquire(ctx, PriorityInteractive)
+	assert.NoError(t, err)
+	releaseInteractive()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = q.acquire(waitCtx, PriorityBatch)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, q.Queued())
+}
+
+func TestRequestQueue_Full(t *testing.T) {
+	q, _ := NewRequestQueue(RequestQueueParams{Workers: 1, MaxQueued: 1})
+	ctx := context.Background()
+
+	_, err := q.acquire(ctx, PriorityInteractive)
+	assert.NoError(t, err)
+	go q.acquire(ctx, PriorityInteractive)
+	assert.Eventually(t, func() bool { return q.Queued() == 1 }, time.Second, time.Millisecond)
+
+	_, err = q.acquire(ctx, PriorityInteractive)
+	assert.ErrorIs(t, err, ErrRequestQueueFull)
+}
+
+func TestRequestHelper_SetRequestQueue(t *testing.T) {
+	q, _ := NewRequestQueue(RequestQueueParams{Workers: 1})
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusOK, `{"Id":"001xx0000000001"}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	h.SetRequestQueue(q)
+	ctx := WithPriority(context.Background(), PriorityBatch)
+
+	for i := 0; i < 3; i++ {
+		_, err := Get[map[string]any](ctx, h, "Account", "001xx0000000001")
+		assert.NoError(t, err)
+	}
+
+	// every worker is released once its response is read
+	assert.Equal(t, 0, q.running)
+	assert.Equal(t, 0, q.runningBatch)
+}
+
+func TestRequestHelper_SetRequestQueue_PatchDelete(t *testing.T) {
+	q, _ := NewRequestQueue(RequestQueueParams{Workers: 2})
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusNoContent, ``), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	h.SetRequestQueue(q)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := Patch(ctx, h, "Account", "001xx0000000001", map[string]any{"Name": "Acme"})
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, Delete(ctx, h, "Account", "001xx0000000001"))
+		}()
+	}
+	wg.Wait()
+
+	// more requests than workers only finish when each releases its worker
+	client.AssertNumberOfCalls(t, "Do", 10)
+	assert.Equal(t, 0, q.running)
+}
+
+func TestRequestHelper_SetRequestQueue_LongPollNotQueued(t *testing.T) {
+	q, _ := NewRequestQueue(RequestQueueParams{Workers: 1})
+	release, err := q.acquire(context.Background(), PriorityInteractive)
+	assert.NoError(t, err)
+	defer release()
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusOK, `[]`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	h.SetRequestQueue(q)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://org/cometd/55.0/connect", http.NoBody)
+	assert.NoError(t, err)
+	resp, err := h.do(req)
+
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, 1, q.running)
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 )
 
 type recordStub struct {
@@ -24,6 +25,10 @@ type HttpClientMock struct {
 
 func (m *HttpClientMock) Do(req *http.Request) (*http.Response, error) {
 	args := m.Called(req)
+	// a func return builds the response from the request
+	if f, ok := args.Get(0).(func(*http.Request) (*http.Response, error)); ok {
+		return f(req)
+	}
 	r := args.Get(0).(*http.Response)
 	return r, args.Error(1)
 }
@@ -49,6 +54,25 @@ func newTokenGetterMock(tok string, err error) *TokenGetterMock {
 	return m
 }
 
+type MetricsMock struct {
+	mock.Mock
+}
+
+func (m *MetricsMock) Count(name string, value int64, tags map[string]string) {
+	m.Called(name, value, tags)
+}
+
+func (m *MetricsMock) Timing(name string, d time.Duration, tags map[string]string) {
+	m.Called(name, d, tags)
+}
+
+func newMetricsMock() *MetricsMock {
+	m := new(MetricsMock)
+	m.On("Count", mock.Anything, mock.Anything, mock.Anything)
+	m.On("Timing", mock.Anything, mock.Anything, mock.Anything)
+	return m
+}
+
 func TestNewRequestHelper(t *testing.T) {
 	type args struct {
 		tg         TokenGetter
@@ -65,17 +89,59 @@ func TestNewRequestHelper(t *testing.T) {
 			name: "successfully create RequestHelper",
 			args: args{
 				tg:         new(TokenGetterMock),
-				baseUrl:    "baseUrl",
+				baseUrl:    "https://org.my.salesforce.com",
 				apiVersion: 55,
 			},
 			want: &RequestHelper{
 				tokenGetter: new(TokenGetterMock),
 				client:      new(HttpClientMock),
-				baseUrl:     "baseUrl",
+				baseUrl:     "https://org.my.salesforce.com",
+				apiVersion:  55,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "trailing slash  stripped",
+			args: args{
+				tg:         new(TokenGetterMock),
+				baseUrl:    "https://org.my.salesforce.com/",
+				apiVersion: 55,
+			},
+			want: &RequestHelper{
+				tokenGetter: new(TokenGetterMock),
+				client:      new(HttpClientMock),
+				baseUrl:     "https://org.my.salesforce.com",
 				apiVersion:  55,
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "no scheme  return error",
+			args: args{
+				tg:         new(TokenGetterMock),
+				baseUrl:    "org.my.salesforce.com",
+				apiVersion: 55,
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "lightning domain  return error",
+			args: args{
+				tg:         new(TokenGetterMock),
+				baseUrl:    "https://org.lightning.force.com",
+				apiVersion: 55,
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "rest api path included  return error",
+			args: args{
+				tg:         new(TokenGetterMock),
+				baseUrl:    "https://org.my.salesforce.com/services/data/v55.0",
+				apiVersion: 55,
+			},
+			wantErr: assert.Error,
+		},
 		{
 			name: "cache nil  return error",
 			args: args{
@@ -624,3 +690,452 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+type InstanceTokenGetterMock struct {
+	TokenGetterMock
+}
+
+func (m *InstanceTokenGetterMock) InstanceUrl(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+func newInstanceTokenGetterMock(instanceUrl string, err error) *InstanceTokenGetterMock {
+	m := new(InstanceTokenGetterMock)
+	m.On("Get", mock.Anything).Return("token", nil)
+	m.On("InstanceUrl", mock.Anything).Return(instanceUrl, err)
+	return m
+}
+
+func TestRequestHelper_InstanceUrl(t *testing.T) {
+	tests := []struct {
+		name    string
+		tg      *InstanceTokenGetterMock
+		wantUrl string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "instance url returned, request sent to instance url",
+			tg:      newInstanceTokenGetterMock("https://org.my.salesforce.com", nil),
+			wantUrl: "https://org.my.salesforce.com/services/data/v55.0/sobjects/Account/id-123",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "instance url error, returns error",
+			tg:      newInstanceTokenGetterMock("", errors.New("token error")),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.URL.String() == tt.wantUrl
+			})).Return(&http.Response{StatusCode: 204}, nil)
+
+			h, err := NewRequestHelperWithInstanceUrl(client, tt.tg, 55)
+			assert.NoError(t, err)
+
+			tt.wantErr(t, Delete(context.Background(), h, "Account", "id-123"))
+		})
+	}
+}
+
+func TestRequestHelper_SetMetrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		client     *HttpClientMock
+		wantStatus string
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "response received, tagged with status code",
+			client:     newHttpClientMock(&http.Response{StatusCode: 204}, nil),
+			wantStatus: "204",
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "request failed, tagged as error",
+			client:     newHttpClientMock(nil, errors.New("connection reset")),
+			wantStatus: "error",
+			wantErr:    assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMetricsMock()
+			h, err := NewRequestHelper(tt.client, newTokenGetterMock("token", nil), "https://org", 55)
+			assert.NoError(t, err)
+			h.SetMetrics(m)
+
+			tt.wantErr(t, Delete(context.Background(), h, "Account", "id-123"))
+
+			tags := map[string]string{"method": http.MethodDelete, "status": tt.wantStatus}
+			m.AssertCalled(t, "Count", MetricRequest, int64(1), tags)
+			m.AssertCalled(t, "Timing", MetricRequestLatency, mock.Anything, tags)
+		})
+	}
+}
+
+func TestRequestHelper_SetDataPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantUrl string
+	}{
+		{
+			name:    "gateway path  request sent under gateway path",
+			path:    "/gateway/salesforce/services/data",
+			wantUrl: "https://gateway.internal/gateway/salesforce/services/data/v55.0/sobjects/Account/id-123",
+		},
+		{
+			name:    "path without leading slash, trailing slash  normalised",
+			path:    "sf/data/",
+			wantUrl: "https://gateway.internal/sf/data/v55.0/sobjects/Account/id-123",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.URL.String() == tt.wantUrl
+			})).Return(&http.Response{StatusCode: 204}, nil)
+
+			h, err := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://gateway.internal", 55)
+			assert.NoError(t, err)
+			h.SetDataPath(tt.path)
+
+			assert.NoError(t, Delete(context.Background(), h, "Account", "id-123"))
+		})
+	}
+}
+
+func TestRequestHelper_SetSitePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantUrl string
+	}{
+		{
+			name:    "site path  request sent under site",
+			path:    "/mycommunity",
+			wantUrl: "https://org.my.site.com/mycommunity/services/data/v55.0/sobjects/Account/id-123",
+		},
+		{
+			name:    "site path without leading slash, trailing slash  normalised",
+			path:    "mycommunity/",
+			wantUrl: "https://org.my.site.com/mycommunity/services/data/v55.0/sobjects/Account/id-123",
+		},
+		{
+			name:    "empty site path  request sent to instance root",
+			path:    "",
+			wantUrl: "https://org.my.site.com/services/data/v55.0/sobjects/Account/id-123",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.URL.String() == tt.wantUrl
+			})).Return(&http.Response{StatusCode: 204}, nil)
+
+			h, err := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org.my.site.com", 55)
+			assert.NoError(t, err)
+			h.SetSitePath(tt.path)
+
+			assert.NoError(t, Delete(context.Background(), h, "Account", "id-123"))
+		})
+	}
+}
+
+func TestRequestHelper_SetMaxResponseSize(t *testing.T) {
+	body := `{"totalSize":1,"done":true,"records":[{"foo":"bar"}]}`
+	isTooLarge := func(t assert.TestingT, err error, i ...interface{}) bool {
+		return assert.ErrorAs(t, err, &ResponseTooLargeError{}, i...)
+	}
+
+	tests := []struct {
+		name          string
+		max           int64
+		contentLength int64
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "no limit  response read",
+			max:     0,
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "body the size of the limit  response read",
+			max:     int64(len(body)),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "body larger than limit  ResponseTooLargeError returned",
+			max:     int64(len(body)) - 1,
+			wantErr: isTooLarge,
+		},
+		{
+			name:          "content length larger than limit  ResponseTooLargeError returned",
+			max:           10,
+			contentLength: 1 << 20,
+			wantErr:       isTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := newResponse(200, body)
+			resp.ContentLength = tt.contentLength
+			h, _ := NewRequestHelper(newHttpClientMock(resp, nil), newTokenGetterMock("token", nil), "https://org", 55)
+			h.SetMaxResponseSize(tt.max)
+
+			got, err := Query[recordStub](context.Background(), h, "SELECT Foo FROM Account")
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assert.Equal(t, "bar", got.Records[0].Foo)
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *HttpClientMock
+		fields  []string
+		wantUrl string
+		want    *recordStub
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "successful get request  record returned",
+			client: newHttpClientMock(&http.Response{Body: io.NopCloser(
+				bytes.NewReader([]byte(`{"attributes":{"type":"Account","url":"/services/data/v55.0/sobjects/Account/id-123"},"foo":"bar"}`))),
+				StatusCode: 200,
+			}, nil),
+			wantUrl: "https://org/services/data/v55.0/sobjects/Account/id-123",
+			want: &recordStub{
+				Attributes: Attributes{Type: "Account", Url: "/services/data/v55.0/sobjects/Account/id-123"},
+				Foo:        "bar",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "fields given  fields param added",
+			client: newHttpClientMock(&http.Response{Body: io.NopCloser(
+				bytes.NewReader([]byte(`{"foo":"bar"}`))),
+				StatusCode: 200,
+			}, nil),
+			fields:  []string{"Id", "Foo__c"},
+			wantUrl: "https://org/services/data/v55.0/sobjects/Account/id-123?fields=Id%2CFoo__c",
+			want:    &recordStub{Foo: "bar"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "404 status code  error returned",
+			client:  newHttpClientMock(&http.Response{Body: io.NopCloser(nil), StatusCode: 404}, nil),
+			wantUrl: "https://org/services/data/v55.0/sobjects/Account/id-123",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewRequestHelper(tt.client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := Get[recordStub](context.Background(), h, "Account", "id-123", tt.fields...)
+			if !tt.wantErr(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantUrl, tt.client.Calls[0].Arguments.Get(0).(*http.Request).URL.String())
+		})
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *HttpClientMock
+		want    *UpsertResponse
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "record created  created response returned",
+			client: newHttpClientMock(&http.Response{Body: io.NopCloser(
+				bytes.NewReader([]byte(`{"id":"id-123","success":true,"created":true}`))),
+				StatusCode: 201,
+			}, nil),
+			want:    &UpsertResponse{Id: "id-123", Success: true, Created: true},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "record updated  updated response returned",
+			client: newHttpClientMock(&http.Response{Body: io.NopCloser(
+				bytes.NewReader([]byte(`{"id":"id-123","success":true,"created":false}`))),
+				StatusCode: 200,
+			}, nil),
+			want:    &UpsertResponse{Id: "id-123", Success: true},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "record updated on older api version  no content",
+			client:  newHttpClientMock(&http.Response{Body: io.NopCloser(nil), StatusCode: 204}, nil),
+			want:    &UpsertResponse{Success: true},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "400 status code  error returned",
+			client:  newHttpClientMock(&http.Response{Body: io.NopCloser(nil), StatusCode: 400}, nil),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewRequestHelper(tt.client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := Upsert(context.Background(), h, "Account", "External_Id__c", "ext/1", recordStub{Foo: "bar"})
+			if !tt.wantErr(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			req := tt.client.Calls[0].Arguments.Get(0).(*http.Request)
+			assert.Equal(t, http.MethodPatch, req.Method)
+			assert.Equal(t, "https://org/services/data/v55.0/sobjects/Account/External_Id__c/ext%2F1", req.URL.String())
+		})
+	}
+}
+
+func TestPatch_WithFieldMask(t *testing.T) {
+	record := struct {
+		Name        string `json:"Name"`
+		Description string `json:"Description,omitempty"`
+		Amount      int    `json:"Amount"`
+	}{Name: "name", Amount: 10}
+
+	tests := []struct {
+		name     string
+		opts     []RequestOption
+		wantBody string
+	}{
+		{
+			name:     "no mask  whole record sent",
+			wantBody: `{"Name":"name","Amount":10}`,
+		},
+		{
+			name:     "mask  only masked fields sent",
+			opts:     []RequestOption{WithFieldMask("Amount")},
+			wantBody: `{"Amount":10}`,
+		},
+		{
+			name:     "masked field omitted by omitempty  sent as null",
+			opts:     []RequestOption{WithFieldMask("Name", "Description")},
+			wantBody: `{"Name":"name","Description":null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newHttpClientMock(&http.Response{StatusCode: 204, Body: io.NopCloser(nil)}, nil)
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			_, err := Patch(context.Background(), h, "Opportunity", "id-123", record, tt.opts...)
+			assert.NoError(t, err)
+
+			body, _ := io.ReadAll(client.Calls[0].Arguments.Get(0).(*http.Request).Body)
+			assert.JSONEq(t, tt.wantBody, string(body))
+		})
+	}
+}
+
+func TestPost_WithHeader(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []RequestOption
+		wantHeaders http.Header
+	}{
+		{
+			name:        "auto assign with active rule  header set",
+			opts:        []RequestOption{WithAutoAssign("")},
+			wantHeaders: http.Header{"Sforce-Auto-Assign": {"TRUE"}},
+		},
+		{
+			name:        "auto assign with rule id  header set",
+			opts:        []RequestOption{WithAutoAssign("01Qxx0000000001")},
+			wantHeaders: http.Header{"Sforce-Auto-Assign": {"01Qxx0000000001"}},
+		},
+		{
+			name: "several headers  all set",
+			opts: []RequestOption{WithCallOptions("client=ops-tool", "defaultNamespace=ns"), WithHeader("Sforce-Duplicate-Rule-Header", "allowSave=true")},
+			wantHeaders: http.Header{
+				"Sforce-Call-Options":          {"client=ops-tool, defaultNamespace=ns"},
+				"Sforce-Duplicate-Rule-Header": {"allowSave=true"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				for name := range tt.wantHeaders {
+					assert.Equal(t, tt.wantHeaders.Get(name), req.Header.Get(name), name)
+				}
+				assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+				return newResponse(http.StatusCreated, `{"id":"500xx0000000001","success":true}`), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			_, err := Post(context.Background(), h, "Case", map[string]string{"Subject": "Help"}, tt.opts...)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestExists(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		resp    *http.Response
+		want    bool
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "found  true returned",
+			id:      "001xx0000000001",
+			resp:    newResponse(http.StatusOK, `{"attributes":{"type":"Account"},"Id":"001xx0000000001"}`),
+			want:    true,
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "not found  false returned",
+			id:      "001xx0000000001",
+			resp:    newResponse(http.StatusNotFound, `[{"errorCode":"NOT_FOUND","message":"The requested resource does not exist"}]`),
+			want:    false,
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "server error  error returned",
+			id:      "001xx0000000001",
+			resp:    newResponse(http.StatusInternalServerError, ""),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "invalid id  error returned",
+			id:      "001?fields=Name",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/sobjects/Account/001xx0000000001", req.URL.Path)
+				assert.Equal(t, "fields=Id", req.URL.RawQuery)
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := Exists(context.Background(), h, "Account", tt.id)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
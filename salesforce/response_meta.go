@@ -0,0 +1,83 @@
+package salesforce
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseMeta the metadata of the responses to the requests sent with a context from WithResponseMeta, for logging
+// and API limit accounting. A call which sends several requests, e.g. paging through a query or retrying, records the
+// last response and the total duration
+type ResponseMeta struct {
+	mu sync.Mutex
+	// StatusCode and Header of the last response, 0 and nil when no response was received
+	StatusCode int
+	Header     http.Header
+	// Requests the requests sent
+	Requests int
+	// Duration the total time spent waiting on responses
+	Duration time.Duration
+	// ApiUsed and ApiLimit the org's API requests used in the last 24 hours and its limit, from the last
+	// Sforce-Limit-Info header, 0 when no response had one
+	ApiUsed  int
+	ApiLimit int
+}
+
+type responseMetaKey struct{}
+
+// WithResponseMeta records the metadata of the responses to the requests sent with the returned context in m, e.g.
+//
+//	var meta salesforce.ResponseMeta
+//	account, err := salesforce.Get[Account](salesforce.WithResponseMeta(ctx, &meta), h, "Account", id)
+//	log.Info("got account", zap.Int("status", meta.StatusCode), zap.Int("apiUsed", meta.ApiUsed))
+func WithResponseMeta(ctx context.Context, m *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, m)
+}
+
+// recordResponseMeta records resp in the ResponseMeta of ctx, when it has one
+func recordResponseMeta(ctx context.Context, resp *http.Response, d time.Duration) {
+	m, ok := ctx.Value(responseMetaKey{}).(*ResponseMeta)
+	if !ok || m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Requests++
+	m.Duration += d
+	if resp == nil {
+		return
+	}
+	m.StatusCode = resp.StatusCode
+	m.Header = resp.Header
+	if used, limit, ok := parseLimitInfo(resp.Header.Get("Sforce-Limit-Info")); ok {
+		m.ApiUsed, m.ApiLimit = used, limit
+	}
+}
+
+// parseLimitInfo the api usage of a Sforce-Limit-Info header, e.g. api-usage=25/15000
+func parseLimitInfo(info string) (int, int, bool) {
+	for _, part := range strings.Split(info, ",") {
+		usage, ok := strings.CutPrefix(strings.TrimSpace(part), "api-usage=")
+		if !ok {
+			continue
+		}
+		usedStr, limitStr, ok := strings.Cut(usage, "/")
+		if !ok {
+			return 0, 0, false
+		}
+		used, err := strconv.Atoi(usedStr)
+		if err != nil {
+			return 0, 0, false
+		}
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return 0, 0, false
+		}
+		return used, limit, true
+	}
+	return 0, 0, false
+}
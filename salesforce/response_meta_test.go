@@ -0,0 +1,54 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+)
+
+func TestWithResponseMeta(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		resp := newResponse(http.StatusOK, `{"Id":"001xx0000000001"}`)
+		resp.Header = http.Header{"Sforce-Limit-Info": {"api-usage=25/15000"}}
+		return resp, nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	var meta ResponseMeta
+
+	for i := 0; i < 2; i++ {
+		_, err := Get[map[string]any](WithResponseMeta(context.Background(), &meta), h, "Account", "001xx0000000001")
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, http.StatusOK, meta.StatusCode)
+	assert.Equal(t, "api-usage=25/15000", meta.Header.Get("Sforce-Limit-Info"))
+	assert.Equal(t, 2, meta.Requests)
+	assert.Equal(t, 25, meta.ApiUsed)
+	assert.Equal(t, 15000, meta.ApiLimit)
+}
+
+func TestParseLimitInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		info      string
+		wantUsed  int
+		wantLimit int
+		wantOk    bool
+	}{
+		{name: "api usage  parsed", info: "api-usage=25/15000", wantUsed: 25, wantLimit: 15000, wantOk: true},
+		{name: "several limits  api usage parsed", info: "per-app-api-usage=17/250(appName=sample), api-usage=25/15000", wantUsed: 25, wantLimit: 15000, wantOk: true},
+		{name: "empty  not ok", info: "", wantOk: false},
+		{name: "malformed  not ok", info: "api-usage=many", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used, limit, ok := parseLimitInfo(tt.info)
+			assert.Equal(t, tt.wantUsed, used)
+			assert.Equal(t, tt.wantLimit, limit)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package salesforce
+
+import (
+	"fmt"
+	"io"
+)
+
+// ResponseTooLargeError returned when a response body exceeds the RequestHelper's SetMaxResponseSize limit
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("salesforce response exceeds the maximum size of %d bytes", e.Limit)
+}
+
+// limitedBody a response body which returns a ResponseTooLargeError once more than remaining bytes are read
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ResponseTooLargeError{Limit: b.limit}
+	}
+	// read one byte past the limit, to tell a body of exactly the limit from a larger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), ResponseTooLargeError{Limit: b.limit}
+	}
+	return n, err
+}
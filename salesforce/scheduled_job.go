@@ -0,0 +1,84 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// recordDateTimeLayout the layout of datetime field values in salesforce responses, e.g. 2024-01-31T02:00:00.000+0000
+const recordDateTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// CronTriggerState the state of a scheduled job's CronTrigger
+type CronTriggerState string
+
+const (
+	CronTriggerWaiting       CronTriggerState = "WAITING"
+	CronTriggerAcquired      CronTriggerState = "ACQUIRED"
+	CronTriggerExecuting     CronTriggerState = "EXECUTING"
+	CronTriggerComplete      CronTriggerState = "COMPLETE"
+	CronTriggerError         CronTriggerState = "ERROR"
+	CronTriggerDeleted       CronTriggerState = "DELETED"
+	CronTriggerPaused        CronTriggerState = "PAUSED"
+	CronTriggerBlocked       CronTriggerState = "BLOCKED"
+	CronTriggerPausedBlocked CronTriggerState = "PAUSED_BLOCKED"
+)
+
+// CronTrigger the schedule of a scheduled job, e.g. a schedulable Apex class or a report run
+type CronTrigger struct {
+	Id             string `json:"Id"`
+	CronExpression string `json:"CronExpression"`
+	CronJobDetail  *struct {
+		Name string `json:"Name"`
+		// JobType e.g. 7 for scheduled Apex, 8 for a report run
+		JobType string `json:"JobType"`
+	} `json:"CronJobDetail"`
+	State CronTriggerState `json:"State"`
+	// NextFireTime and PreviousFireTime empty when the job won't run again or hasn't run yet
+	NextFireTime     string `json:"NextFireTime"`
+	PreviousFireTime string `json:"PreviousFireTime"`
+	StartTime        string `json:"StartTime"`
+	EndTime          string `json:"EndTime"`
+	TimesTriggered   int    `json:"TimesTriggered"`
+	TimeZoneSidKey   string `json:"TimeZoneSidKey"`
+	OwnerId          string `json:"OwnerId"`
+}
+
+// Name the name the job was scheduled with, empty when it has none
+func (c CronTrigger) Name() string {
+	if c.CronJobDetail == nil {
+		return ""
+	}
+	return c.CronJobDetail.Name
+}
+
+// NextFire the time the job next runs, false when it won't run again
+func (c CronTrigger) NextFire() (time.Time, bool) {
+	t, err := time.Parse(recordDateTimeLayout, c.NextFireTime)
+	return t, err == nil
+}
+
+// Healthy whether the job will run again and isn't overdue by more than grace at now, a job in the ERROR, PAUSED or
+// BLOCKED states or whose next run is long past isn't running as scheduled
+func (c CronTrigger) Healthy(now time.Time, grace time.Duration) bool {
+	switch c.State {
+	case CronTriggerError, CronTriggerDeleted, CronTriggerComplete, CronTriggerPaused, CronTriggerBlocked, CronTriggerPausedBlocked:
+		return false
+	}
+	next, ok := c.NextFire()
+	return ok && !now.After(next.Add(grace))
+}
+
+// ListScheduledJobs lists the CronTriggers of the org's scheduled jobs, soonest to run first
+func ListScheduledJobs(ctx context.Context, h *RequestHelper) ([]CronTrigger, error) {
+	return queryAll[CronTrigger](ctx, h, SelectFor[CronTrigger]("CronTrigger")+" ORDER BY NextFireTime NULLS LAST")
+}
+
+// GetScheduledJob gets the CronTrigger of the job scheduled with name, e.g. with System.schedule, ErrNoRows is
+// returned when there is none
+func GetScheduledJob(ctx context.Context, h *RequestHelper, name string) (*CronTrigger, error) {
+	if len(name) == 0 {
+		return nil, fmt.Errorf("scheduled job name needs to be provided")
+	}
+	return FindOne[CronTrigger](ctx, h, SelectFor[CronTrigger]("CronTrigger")+" WHERE CronJobDetail.Name = "+QuoteString(name))
+}
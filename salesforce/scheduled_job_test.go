@@ -0,0 +1,70 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCronTrigger_Healthy(t *testing.T) {
+	now := time.Date(2024, 1, 31, 2, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		trigger CronTrigger
+		want    bool
+	}{
+		{
+			name:    "waiting  next run due  healthy",
+			trigger: CronTrigger{State: CronTriggerWaiting, NextFireTime: "2024-01-31T03:00:00.000+0000"},
+			want:    true,
+		},
+		{
+			name:    "waiting  next run overdue within grace  healthy",
+			trigger: CronTrigger{State: CronTriggerWaiting, NextFireTime: "2024-01-31T01:58:00.000+0000"},
+			want:    true,
+		},
+		{
+			name:    "waiting  next run overdue  unhealthy",
+			trigger: CronTrigger{State: CronTriggerWaiting, NextFireTime: "2024-01-31T01:00:00.000+0000"},
+			want:    false,
+		},
+		{
+			name:    "error  unhealthy",
+			trigger: CronTrigger{State: CronTriggerError, NextFireTime: "2024-01-31T03:00:00.000+0000"},
+			want:    false,
+		},
+		{
+			name:    "no next run  unhealthy",
+			trigger: CronTrigger{State: CronTriggerWaiting},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.trigger.Healthy(now, 5*time.Minute))
+		})
+	}
+}
+
+func TestGetScheduledJob(t *testing.T) {
+	var gotQuery string
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		gotQuery = req.URL.Query().Get("q")
+		return newResponse(http.StatusOK, `{"totalSize":1,"done":true,"records":[{"Id":"08exx0000000001",`+
+			`"CronJobDetail":{"Name":"Nightly cleanup","JobType":"7"},"State":"WAITING","NextFireTime":"2024-01-31T02:00:00.000+0000"}]}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := GetScheduledJob(context.Background(), h, "Nightly cleanup")
+
+	assert.NoError(t, err)
+	assert.Contains(t, gotQuery, "FROM CronTrigger WHERE CronJobDetail.Name = 'Nightly cleanup'")
+	assert.Equal(t, "Nightly cleanup", got.Name())
+	next, ok := got.NextFire()
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 1, 31, 2, 0, 0, 0, time.UTC), next.UTC())
+}
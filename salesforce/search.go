@@ -0,0 +1,110 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SearchScope the fields a search term is matched against
+type SearchScope string
+
+const (
+	SearchAllFields   SearchScope = "ALL"
+	SearchNameFields  SearchScope = "NAME"
+	SearchEmailFields SearchScope = "EMAIL"
+	SearchPhoneFields SearchScope = "PHONE"
+)
+
+// SearchOptions a parameterized search, the term is sent as a parameter rather than interpolated into SOSL, so needs
+// no escaping
+type SearchOptions struct {
+	// Q the search term, required
+	Q  string      `json:"q"`
+	In SearchScope `json:"in,omitempty"`
+	// Fields the fields returned for every object, unless an object sets its own
+	Fields   []string       `json:"fields,omitempty"`
+	SObjects []SearchObject `json:"sobjects,omitempty"`
+	// OverallLimit the most records returned across all objects
+	OverallLimit int `json:"overallLimit,omitempty"`
+	// DefaultLimit the most records returned for each object, unless an object sets its own
+	DefaultLimit   int                  `json:"defaultLimit,omitempty"`
+	DataCategories []DataCategoryFilter `json:"dataCategories,omitempty"`
+}
+
+// SearchObject limits a search to an object, with its own fields, filter and limit
+type SearchObject struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields,omitempty"`
+	// Where a SOQL condition, e.g. Type = 'Customer'
+	Where   string `json:"where,omitempty"`
+	OrderBy string `json:"orderBy,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+}
+
+// DataCategoryFilter limits a search of knowledge articles to data categories
+type DataCategoryFilter struct {
+	GroupName string `json:"groupName"`
+	// Operator AT, ABOVE, BELOW or ABOVE_OR_BELOW
+	Operator   string   `json:"operator"`
+	Categories []string `json:"categories"`
+}
+
+type searchResponse[E any] struct {
+	SearchRecords []E `json:"searchRecords"`
+}
+
+// Search runs a parameterized search, returning the matching records decoded as E
+// - records of several objects may be returned, E can embed Attributes to tell them apart
+func Search[E any](ctx context.Context, h *RequestHelper, opts SearchOptions) ([]E, error) {
+	if len(strings.TrimSpace(opts.Q)) == 0 {
+		return nil, fmt.Errorf("search term needs to be provided")
+	}
+	reqUrl, err := h.dataUrl(ctx, "/parameterizedSearch")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := sendJson[searchResponse[E]](ctx, h, http.MethodPost, reqUrl, opts)
+	if err != nil {
+		return nil, err
+	}
+	return resp.SearchRecords, nil
+}
+
+// SearchSuggestion a record suggested for a partial search term
+type SearchSuggestion struct {
+	Attributes Attributes `json:"attributes"`
+	Id         string     `json:"Id"`
+	Name       string     `json:"Name"`
+}
+
+type suggestionsResponse struct {
+	AutoSuggestResults []SearchSuggestion `json:"autoSuggestResults"`
+}
+
+// SuggestRecords suggests records of objects whose name matches a partial term, e.g. for type-ahead lookups
+// - limit caps the suggestions returned, salesforce's default is used when 0
+func SuggestRecords(ctx context.Context, h *RequestHelper, term string, limit int, objects ...string) ([]SearchSuggestion, error) {
+	if len(strings.TrimSpace(term)) == 0 {
+		return nil, fmt.Errorf("search term needs to be provided")
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("at least one object needs to be provided")
+	}
+	params := url.Values{"q": {term}, "sobject": objects}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	reqUrl, err := h.dataUrl(ctx, "/search/suggestions?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := getJson[suggestionsResponse](ctx, h, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	return resp.AutoSuggestResults, nil
+}
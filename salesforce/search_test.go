@@ -0,0 +1,110 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	type account struct {
+		Attributes Attributes `json:"attributes"`
+		Id         string
+		Name       string
+	}
+	tests := []struct {
+		name     string
+		opts     SearchOptions
+		wantBody string
+		resp     *http.Response
+		want     []account
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "search  records returned",
+			opts: SearchOptions{
+				Q:        "O'Brien OR *",
+				In:       SearchNameFields,
+				SObjects: []SearchObject{{Name: "Account", Fields: []string{"Id", "Name"}, Where: "Type = 'Customer'", Limit: 5}},
+			},
+			wantBody: `{"q":"O'Brien OR *","in":"NAME","sobjects":[{"name":"Account","fields":["Id","Name"],"where":"Type = 'Customer'","limit":5}]}`,
+			resp: newResponse(http.StatusOK, `{"searchRecords":[{"attributes":{"type":"Account","url":"/services/data/v55.0/sobjects/Account/001xx0000000001"},
+				"Id":"001xx0000000001","Name":"O'Brien Ltd"}],"metadata":{}}`),
+			want: []account{{Attributes: Attributes{Type: "Account", Url: "/services/data/v55.0/sobjects/Account/001xx0000000001"},
+				Id: "001xx0000000001", Name: "O'Brien Ltd"}},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "no term  error returned",
+			opts:    SearchOptions{Q: " "},
+			wantErr: assert.Error,
+		},
+		{
+			name:     "bad request  error returned",
+			opts:     SearchOptions{Q: "a"},
+			wantBody: `{"q":"a"}`,
+			resp:     newResponse(http.StatusBadRequest, `[{"errorCode":"INVALID_SEARCH"}]`),
+			wantErr:  assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/parameterizedSearch", req.URL.Path)
+				b, _ := io.ReadAll(req.Body)
+				assert.JSONEq(t, tt.wantBody, string(b))
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := Search[account](context.Background(), h, tt.opts)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSuggestRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		term    string
+		objects []string
+		want    []SearchSuggestion
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "term  suggestions returned",
+			term:    "Acm",
+			objects: []string{"Account", "Contact"},
+			want: []SearchSuggestion{{Attributes: Attributes{Type: "Account", Url: "/services/data/v55.0/sobjects/Account/001xx0000000001"},
+				Id: "001xx0000000001", Name: "Acme"}},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "no objects  error returned",
+			term:    "Acm",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/search/suggestions", req.URL.Path)
+				assert.Equal(t, url.Values{"q": {"Acm"}, "sobject": {"Account", "Contact"}, "limit": {"5"}}, req.URL.Query())
+				return newResponse(http.StatusOK, `{"autoSuggestResults":[{"attributes":{"type":"Account",
+					"url":"/services/data/v55.0/sobjects/Account/001xx0000000001"},"Id":"001xx0000000001","Name":"Acme"}],"hasMoreResults":false}`), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := SuggestRecords(context.Background(), h, tt.term, 5, tt.objects...)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package salesforce
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateTimeLayout the SOQL datetime format, always in UTC
+const dateTimeLayout = "2006-01-02T15:04:05Z"
+
+// SOQL date literals, relative to the running user's time zone
+const (
+	Yesterday = "YESTERDAY"
+	Today     = "TODAY"
+	Tomorrow  = "TOMORROW"
+	LastWeek  = "LAST_WEEK"
+	ThisWeek  = "THIS_WEEK"
+	NextWeek  = "NEXT_WEEK"
+	LastMonth = "LAST_MONTH"
+	ThisMonth = "THIS_MONTH"
+	NextMonth = "NEXT_MONTH"
+	LastYear  = "LAST_YEAR"
+	ThisYear  = "THIS_YEAR"
+	NextYear  = "NEXT_YEAR"
+)
+
+// LastNDays the LAST_N_DAYS:n date literal, from n days ago up to and including today
+func LastNDays(n int) string {
+	return fmt.Sprintf("LAST_N_DAYS:%d", n)
+}
+
+// NextNDays the NEXT_N_DAYS:n date literal, from tomorrow up to n days ahead
+func NextNDays(n int) string {
+	return fmt.Sprintf("NEXT_N_DAYS:%d", n)
+}
+
+// DateLiteral formats t as a SOQL date, e.g. 2024-01-31, for comparison with date fields
+func DateLiteral(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+// DateTimeLiteral formats t as a SOQL datetime in UTC, e.g. 2024-01-31T09:30:00Z, for comparison with datetime fields
+func DateTimeLiteral(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// BoolLiteral formats b as a SOQL boolean
+func BoolLiteral(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+// Literal formats v as a SOQL literal for interpolation into a WHERE clause
+//   - strings, ids and anything else not listed are quoted and escaped
+//   - time.Time is a datetime and Date a date
+//   - nil is null
+func Literal(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return QuoteString(v)
+	case bool:
+		return BoolLiteral(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return DateTimeLiteral(v)
+	case Date:
+		if v.IsZero() {
+			return "null"
+		}
+		return DateLiteral(v.Time)
+	case fmt.Stringer:
+		return QuoteString(v.String())
+	}
+	return QuoteString(fmt.Sprint(v))
+}
+
+// InList formats values as a SOQL list for IN and NOT IN, e.g. ('a', 'b')
+func InList[T any](values ...T) string {
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = Literal(v)
+	}
+	return "(" + strings.Join(literals, ", ") + ")"
+}
+
+// soqlEscapes the characters SOQL strings require escaping
+var soqlEscapes = []string{`\`, `\\`, `'`, `\'`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "\b", `\b`, "\f", `\f`}
+
+var (
+	soqlEscaper = strings.NewReplacer(soqlEscapes...)
+	// likeEscaper escapes as soqlEscaper, plus the LIKE wildcards
+	likeEscaper = strings.NewReplacer(append([]string{`%`, `\%`, `_`, `\_`}, soqlEscapes...)...)
+)
+
+// QuoteString quotes s as a SOQL string, escaping quotes, backslashes and control characters, so user input can be
+// interpolated into a query safely
+func QuoteString(s string) string {
+	return "'" + soqlEscaper.Replace(s) + "'"
+}
+
+// EscapeLike escapes s for use within a quoted LIKE pattern, including the % and _ wildcards so they match literally.
+// The result is unquoted so wildcards can be added around it
+//
+//	q := "SELECT Id FROM Account WHERE Name LIKE '" + salesforce.EscapeLike(prefix) + "%'"
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,78 @@
+package salesforce
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLiteral(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{name: "string  quoted", v: "Acme", want: "'Acme'"},
+		{name: "string with quote  escaped", v: `O'Brien \ Co`, want: `'O\'Brien \\ Co'`},
+		{name: "bool  unquoted", v: true, want: "true"},
+		{name: "int  unquoted", v: 42, want: "42"},
+		{name: "float  unquoted", v: 1.5, want: "1.5"},
+		{name: "time  utc datetime", v: time.Date(2024, 1, 31, 10, 30, 0, 0, time.FixedZone("", 3600)), want: "2024-01-31T09:30:00Z"},
+		{name: "date  date", v: NewDate(2024, 1, 31), want: "2024-01-31"},
+		{name: "zero date  null", v: Date{}, want: "null"},
+		{name: "nil  null", v: nil, want: "null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Literal(tt.v))
+		})
+	}
+}
+
+func TestInList(t *testing.T) {
+	assert.Equal(t, "('001A', '001B')", InList("001A", "001B"))
+	assert.Equal(t, "(1, 2)", InList(1, 2))
+	assert.Equal(t, "()", InList[string]())
+}
+
+func TestDateLiterals(t *testing.T) {
+	assert.Equal(t, "LAST_N_DAYS:30", LastNDays(30))
+	assert.Equal(t, "NEXT_N_DAYS:7", NextNDays(7))
+	assert.Equal(t, "2024-01-31", DateLiteral(time.Date(2024, 1, 31, 23, 0, 0, 0, time.UTC)))
+}
+
+func TestQuoteString(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "plain  quoted", s: "Acme", want: `'Acme'`},
+		{name: "quotes  escaped", s: `O'Brien "Co"`, want: `'O\'Brien \"Co\"'`},
+		{name: "backslash quote injection  escaped", s: `a\' OR Name != '`, want: `'a\\\' OR Name != \''`},
+		{name: "newline  escaped", s: "a\nb", want: `'a\nb'`},
+		{name: "wildcards  unescaped", s: "50%_off", want: `'50%_off'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, QuoteString(tt.s))
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "plain  unchanged", s: "Acme", want: `Acme`},
+		{name: "wildcards  escaped", s: "50%_off", want: `50\%\_off`},
+		{name: "quote and backslash  escaped", s: `O'Brien\`, want: `O\'Brien\\`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EscapeLike(tt.s))
+		})
+	}
+}
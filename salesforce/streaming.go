@@ -0,0 +1,428 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cenkalti/backoff/v4"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ReplayNew only events published after subscribing
+	ReplayNew int64 = -1
+	// ReplayAll the earliest retained events, up to 72 hours old
+	ReplayAll int64 = -2
+)
+
+// StreamingSubscription a Streaming API channel to subscribe to
+type StreamingSubscription struct {
+	// Channel e.g. /topic/AccountUpdates, /event/Order_Shipped__e or /data/AccountChangeEvent
+	Channel string
+	// ReplayId the replay id to resume after, or ReplayNew or ReplayAll. Defaults to ReplayNew
+	ReplayId int64
+	// ReplayStore when set, the subscription resumes after the replay id it holds, falling back to ReplayId when there
+	// isn't one, and the replay id is saved after each batch of events is handled
+	ReplayStore ReplayStore
+	// Name the key of the subscription in ReplayStore, defaults to Channel
+	Name string
+}
+
+// name the key of the subscription in its ReplayStore
+func (s StreamingSubscription) name() string {
+	if len(s.Name) > 0 {
+		return s.Name
+	}
+	return s.Channel
+}
+
+// StreamingEvent a received event
+type StreamingEvent struct {
+	Channel string
+	// ReplayId the position of the event in the channel, to resume after it
+	ReplayId int64
+	// Type the type of a PushTopic event, e.g. created or updated
+	Type string
+	// CreatedDate when a PushTopic event was published, zero for other events
+	CreatedDate time.Time
+	// Payload the sobject of a PushTopic event, or the payload of a platform or change event
+	Payload json.RawMessage
+}
+
+// Decode decodes the payload into v
+func (e StreamingEvent) Decode(v any) error {
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("unable to decode salesforce streaming event %d: %w", e.ReplayId, err)
+	}
+	return nil
+}
+
+// StreamingHandler handles an event, returning an error stops the subscription
+type StreamingHandler func(ctx context.Context, e StreamingEvent) error
+
+// StreamingClient a Streaming API (CometD) client, for orgs without Pub/Sub API access. Requests are sent with the
+// RequestHelper's http client and token, the http client needs a timeout above the 110 second long poll
+type StreamingClient struct {
+	h       *RequestHelper
+	backoff BackoffPolicy
+}
+
+func NewStreamingClient(h *RequestHelper) *StreamingClient {
+	return &StreamingClient{h: h}
+}
+
+// SetBackoff sets the backoff between the reconnects of Run, see ReconnectBackOff for the defaults of its zero fields
+func (c *StreamingClient) SetBackoff(p BackoffPolicy) *StreamingClient {
+	c.backoff = p
+	return c
+}
+
+// count counts a subscription metric, when the RequestHelper records metrics
+func (c *StreamingClient) count(name, channel string) {
+	if c.h.metrics != nil {
+		c.h.metrics.Count(name, 1, map[string]string{"subscription": channel})
+	}
+}
+
+// streamingStatusError an unexpected response code from the CometD endpoint, so Run can tell a rejected token apart
+type streamingStatusError int
+
+func (e streamingStatusError) Error() string {
+	return fmt.Sprintf("unexpected salesforce response code: %d", int(e))
+}
+
+// unauthorized whether the CometD endpoint rejected the token, by response code or Bayeux error e.g. 401::Authentication invalid
+func unauthorized(err error) bool {
+	var statusErr streamingStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr == http.StatusUnauthorized
+	}
+	return strings.Contains(err.Error(), "401::")
+}
+
+// bayeuxMessage a Bayeux protocol message, both sent and received
+type bayeuxMessage struct {
+	Channel                  string          `json:"channel"`
+	Id                       string          `json:"id,omitempty"`
+	ClientId                 string          `json:"clientId,omitempty"`
+	Version                  string          `json:"version,omitempty"`
+	SupportedConnectionTypes []string        `json:"supportedConnectionTypes,omitempty"`
+	ConnectionType           string          `json:"connectionType,omitempty"`
+	Subscription             string          `json:"subscription,omitempty"`
+	Successful               bool            `json:"successful,omitempty"`
+	Error                    string          `json:"error,omitempty"`
+	Advice                   *bayeuxAdvice   `json:"advice,omitempty"`
+	Ext                      map[string]any  `json:"ext,omitempty"`
+	Data                     json.RawMessage `json:"data,omitempty"`
+}
+
+// bayeuxAdvice how the server advises the client to reconnect
+type bayeuxAdvice struct {
+	// Reconnect retry, handshake or none
+	Reconnect string `json:"reconnect"`
+	// Interval the milliseconds to wait before reconnecting
+	Interval int64 `json:"interval"`
+}
+
+// streamingEventData the data of an event message
+type streamingEventData struct {
+	Event struct {
+		ReplayId    int64  `json:"replayId"`
+		Type        string `json:"type"`
+		CreatedDate string `json:"createdDate"`
+	} `json:"event"`
+	SObject json.RawMessage `json:"sobject"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// cometdSession the state of one connection, the session is identified by its client id and cookies
+type cometdSession struct {
+	h        *RequestHelper
+	clientId string
+	cookies  map[string]*http.Cookie
+	msgId    int
+}
+
+// Subscribe handshakes, subscribes to a channel and long polls for events, delivering each to handler in order. It
+// blocks until ctx is done, the server refuses to reconnect or handler returns an error. When the session expires it
+// handshakes again and resubscribes after the last event delivered. With a ReplayStore, events are delivered at least
+// once: a batch interrupted part way is redelivered on resume
+func (c *StreamingClient) Subscribe(ctx context.Context, s StreamingSubscription, handler StreamingHandler) error {
+	if len(s.Channel) == 0 {
+		return fmt.Errorf("channel needs to be provided")
+	}
+	replayId := s.ReplayId
+	if replayId == 0 {
+		replayId = ReplayNew
+	}
+	if s.ReplayStore != nil {
+		stored, err := s.ReplayStore.Load(ctx, s.name())
+		if err != nil {
+			return fmt.Errorf("unable to load replay id of %s: %w", s.name(), err)
+		}
+		if len(stored) > 0 {
+			if replayId, err = strconv.ParseInt(string(stored), 10, 64); err != nil {
+				return fmt.Errorf("invalid replay id of %s: %w", s.name(), err)
+			}
+		}
+	}
+
+	sess := &cometdSession{h: c.h}
+	if err := sess.open(ctx, s.Channel, replayId); err != nil {
+		return err
+	}
+	// when the replay id was last saved, from the subscription starting until the first save
+	saved := time.Now()
+	for {
+		msgs, err := sess.send(ctx, bayeuxMessage{Channel: "/meta/connect", ClientId: sess.clientId, ConnectionType: "long-polling"})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if s.ReplayStore != nil && c.h.metrics != nil {
+			c.h.metrics.Timing(MetricSubscriptionReplayAge, time.Since(saved), map[string]string{"subscription": s.Channel})
+		}
+
+		var advice *bayeuxAdvice
+		delivered := false
+		for _, m := range msgs {
+			if m.Channel == "/meta/connect" {
+				advice = m.Advice
+				if !m.Successful {
+					// 403::Unknown client, the session expired
+					if !strings.HasPrefix(m.Error, "403::") && (advice == nil || advice.Reconnect != "handshake") {
+						return fmt.Errorf("salesforce streaming connect failed: %s", m.Error)
+					}
+					advice = &bayeuxAdvice{Reconnect: "handshake"}
+				}
+				continue
+			}
+			if m.Channel != s.Channel || len(m.Data) == 0 {
+				continue
+			}
+			e, err := newStreamingEvent(m)
+			if err != nil {
+				c.count(MetricSubscriptionDecodeFailure, s.Channel)
+				return err
+			}
+			c.count(MetricSubscriptionEvent, s.Channel)
+			if c.h.metrics != nil && !e.CreatedDate.IsZero() {
+				c.h.metrics.Timing(MetricSubscriptionLag, time.Since(e.CreatedDate), map[string]string{"subscription": s.Channel})
+			}
+			if err = handler(ctx, e); err != nil {
+				return fmt.Errorf("salesforce streaming handler for %s failed: %w", s.Channel, err)
+			}
+			replayId = e.ReplayId
+			delivered = true
+		}
+		if delivered && s.ReplayStore != nil {
+			if err = s.ReplayStore.Save(ctx, s.name(), []byte(strconv.FormatInt(replayId, 10))); err != nil {
+				return fmt.Errorf("unable to save replay id of %s: %w", s.name(), err)
+			}
+			saved = time.Now()
+		}
+
+		if advice == nil {
+			continue
+		}
+		switch advice.Reconnect {
+		case "none":
+			return fmt.Errorf("salesforce streaming server refused to reconnect to %s", s.Channel)
+		case "handshake":
+			if err = sess.open(ctx, s.Channel, replayId); err != nil {
+				return err
+			}
+		}
+		if advice.Interval > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(advice.Interval) * time.Millisecond):
+			}
+		}
+	}
+}
+
+// Run subscribes as Subscribe, reconnecting with exponential backoff, see SetBackoff, when the connection fails. A token
+// salesforce rejected is refreshed before reconnecting, and each reconnect resumes after the last event delivered, or
+// from the ReplayStore. Run returns ctx.Err() once ctx is done, otherwise a SubscriptionError once the handler fails,
+// the credentials are rejected or the backoff gives up
+func (c *StreamingClient) Run(ctx context.Context, s StreamingSubscription, handler StreamingHandler) error {
+	if len(s.Channel) == 0 {
+		return fmt.Errorf("channel needs to be provided")
+	}
+	b := ReconnectBackOff(c.backoff)
+	attempts := 0
+	for {
+		delivered := false
+		var handlerErr error
+		err := c.Subscribe(ctx, s, func(ctx context.Context, e StreamingEvent) error {
+			if handlerErr = handler(ctx, e); handlerErr != nil {
+				return handlerErr
+			}
+			s.ReplayId = e.ReplayId
+			delivered = true
+			return nil
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if handlerErr != nil {
+			return SubscriptionError{Subscription: s.Channel, Err: err}
+		}
+
+		if delivered {
+			attempts = 0
+			b.Reset()
+		}
+		attempts++
+		wait := b.NextBackOff()
+		if wait == backoff.Stop {
+			return SubscriptionError{Subscription: s.Channel, Attempts: attempts, Err: err}
+		}
+		c.count(MetricSubscriptionReconnect, s.Channel)
+		if unauthorized(err) {
+			if err = RefreshToken(ctx, c.h.tokenGetter, s.Channel); err != nil {
+				return err
+			}
+		}
+		if !sleepContext(ctx, wait) {
+			return ctx.Err()
+		}
+	}
+}
+
+func newStreamingEvent(m bayeuxMessage) (StreamingEvent, error) {
+	var data streamingEventData
+	if err := json.Unmarshal(m.Data, &data); err != nil {
+		return StreamingEvent{}, fmt.Errorf("unable to parse salesforce streaming event: %w", err)
+	}
+	payload := data.Payload
+	if len(data.SObject) > 0 {
+		payload = data.SObject
+	}
+	// the created date is informational, an unexpected format leaves it zero rather than failing the event
+	createdDate, _ := time.Parse(time.RFC3339, data.Event.CreatedDate)
+	return StreamingEvent{
+		Channel:     m.Channel,
+		ReplayId:    data.Event.ReplayId,
+		Type:        data.Event.Type,
+		CreatedDate: createdDate,
+		Payload:     payload,
+	}, nil
+}
+
+// open handshakes a new session and subscribes to channel from replayId with the replay extension
+func (s *cometdSession) open(ctx context.Context, channel string, replayId int64) error {
+	s.clientId = ""
+	s.cookies = map[string]*http.Cookie{}
+	msgs, err := s.send(ctx, bayeuxMessage{
+		Channel:                  "/meta/handshake",
+		Version:                  "1.0",
+		SupportedConnectionTypes: []string{"long-polling"},
+		Ext:                      map[string]any{"replay": true},
+	})
+	if err != nil {
+		return err
+	}
+	reply, err := bayeuxReply(msgs, "/meta/handshake")
+	if err != nil {
+		return err
+	}
+	s.clientId = reply.ClientId
+
+	msgs, err = s.send(ctx, bayeuxMessage{
+		Channel:      "/meta/subscribe",
+		ClientId:     s.clientId,
+		Subscription: channel,
+		Ext:          map[string]any{"replay": map[string]int64{channel: replayId}},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = bayeuxReply(msgs, "/meta/subscribe"); err != nil {
+		return fmt.Errorf("unable to subscribe to %s: %w", channel, err)
+	}
+	return nil
+}
+
+// bayeuxReply the successful reply on a meta channel
+func bayeuxReply(msgs []bayeuxMessage, channel string) (bayeuxMessage, error) {
+	for _, m := range msgs {
+		if m.Channel != channel {
+			continue
+		}
+		if !m.Successful {
+			return m, fmt.Errorf("salesforce streaming %s failed: %s", channel, m.Error)
+		}
+		return m, nil
+	}
+	return bayeuxMessage{}, fmt.Errorf("salesforce streaming %s failed: no reply", channel)
+}
+
+// send posts a message to the CometD endpoint with the session cookies, keeping any cookies set in the response
+func (s *cometdSession) send(ctx context.Context, msg bayeuxMessage) ([]bayeuxMessage, error) {
+	s.msgId++
+	msg.Id = strconv.Itoa(s.msgId)
+	body, err := json.Marshal([]bayeuxMessage{msg})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	reqUrl, err := s.h.instanceUrl(ctx, fmt.Sprintf("/cometd/%d.0", s.h.apiVersion))
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.h.newRequest(ctx, http.MethodPost, reqUrl, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range s.cookies {
+		req.AddCookie(c)
+	}
+
+	resp, err := s.h.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, streamingStatusError(resp.StatusCode)
+	}
+	for _, c := range resp.Cookies() {
+		s.cookies[c.Name] = c
+	}
+
+	var msgs []bayeuxMessage
+	if err = json.NewDecoder(resp.Body).Decode(&msgs); err != nil {
+		return nil, fmt.Errorf("unable to parse response body: %w", err)
+	}
+	return msgs, nil
+}
+
+// TypedStreamingHandler a StreamingHandler decoding each event's payload into the type registered for its channel, fn
+// receives a pointer to it, e.g. *OrderShipped. An event without a registered type stops the subscription
+func TypedStreamingHandler(r *EventRegistry, fn func(ctx context.Context, e StreamingEvent, v any) error) StreamingHandler {
+	return func(ctx context.Context, e StreamingEvent) error {
+		var header struct {
+			ChangeEventHeader struct {
+				EntityName string `json:"entityName"`
+			}
+		}
+		_ = json.Unmarshal(e.Payload, &header)
+		v, err := r.NewForEvent(e.Channel, header.ChangeEventHeader.EntityName)
+		if err != nil {
+			return err
+		}
+		if err = e.Decode(v); err != nil {
+			return err
+		}
+		return fn(ctx, e, v)
+	}
+}
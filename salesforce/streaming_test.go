@@ -0,0 +1,232 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cometdServerStub replies to each message in turn, recording the subscribe replay ids and connect cookies
+type cometdServerStub struct {
+	connects     [][]bayeuxMessage
+	subscribes   []int64
+	handshakes   int
+	cookieMisses int
+	// unauthorized the handshakes to reject before accepting the token
+	unauthorized int
+}
+
+func (s *cometdServerStub) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.Path != "/cometd/55.0" {
+		return nil, errors.New("unexpected path " + req.URL.Path)
+	}
+	var msgs []bayeuxMessage
+	b, _ := io.ReadAll(req.Body)
+	_ = json.Unmarshal(b, &msgs)
+	m := msgs[0]
+
+	var reply []bayeuxMessage
+	resp := newResponse(http.StatusOK, "")
+	switch m.Channel {
+	case "/meta/handshake":
+		if s.unauthorized > 0 {
+			s.unauthorized--
+			return newResponse(http.StatusUnauthorized, ""), nil
+		}
+		s.handshakes++
+		resp.Header = http.Header{"Set-Cookie": {"BAYEUX_BROWSER=session"}}
+		reply = []bayeuxMessage{{Channel: m.Channel, ClientId: "client", Successful: true}}
+	case "/meta/subscribe":
+		replay := m.Ext["replay"].(map[string]any)
+		s.subscribes = append(s.subscribes, int64(replay[m.Subscription].(float64)))
+		reply = []bayeuxMessage{{Channel: m.Channel, Subscription: m.Subscription, Successful: true}}
+	case "/meta/connect":
+		if _, err := req.Cookie("BAYEUX_BROWSER"); err != nil {
+			s.cookieMisses++
+		}
+		if len(s.connects) == 0 {
+			return nil, errors.New("no more replies")
+		}
+		reply, s.connects = s.connects[0], s.connects[1:]
+	}
+	body, _ := json.Marshal(reply)
+	resp.Body = io.NopCloser(strings.NewReader(string(body)))
+	return resp, nil
+}
+
+func newStreamingEventMessage(replayId int64, name string) bayeuxMessage {
+	data, _ := json.Marshal(map[string]any{
+		"event":   map[string]any{"replayId": replayId, "type": "updated", "createdDate": "2024-01-02T03:04:05.678Z"},
+		"sobject": map[string]any{"Name": name},
+	})
+	return bayeuxMessage{Channel: "/topic/Accounts", Data: data}
+}
+
+func TestStreamingClient_Subscribe(t *testing.T) {
+	server := &cometdServerStub{connects: [][]bayeuxMessage{
+		{newStreamingEventMessage(5, "a"), newStreamingEventMessage(6, "b"), {Channel: "/meta/connect", Successful: true}},
+		{{Channel: "/meta/connect", Error: "403::Unknown client", Advice: &bayeuxAdvice{Reconnect: "handshake"}}},
+		{newStreamingEventMessage(7, "stop"), {Channel: "/meta/connect", Successful: true}},
+	}}
+	h, _ := NewRequestHelper(server, newTokenGetterMock("token", nil), "https://org.my.salesforce.com", 55)
+	metrics := newMetricsMock()
+	h.SetMetrics(metrics)
+	store := NewMemoryReplayStore()
+	_ = store.Save(context.Background(), "accounts", []byte("4"))
+
+	var got []string
+	stopErr := errors.New("stop")
+	err := NewStreamingClient(h).Subscribe(context.Background(), StreamingSubscription{Channel: "/topic/Accounts", ReplayStore: store, Name: "accounts"},
+		func(ctx context.Context, e StreamingEvent) error {
+			var account struct{ Name string }
+			assert.NoError(t, e.Decode(&account))
+			assert.Equal(t, "updated", e.Type)
+			assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC), e.CreatedDate)
+			got = append(got, account.Name)
+			if account.Name == "stop" {
+				return stopErr
+			}
+			return nil
+		})
+
+	assert.ErrorIs(t, err, stopErr)
+	assert.Equal(t, []string{"a", "b", "stop"}, got)
+	assert.Equal(t, 2, server.handshakes)
+	assert.Equal(t, []int64{4, 6}, server.subscribes, "resubscribed after the last event delivered")
+	assert.Equal(t, 0, server.cookieMisses)
+	replayId, _ := store.Load(context.Background(), "accounts")
+	assert.Equal(t, "6", string(replayId))
+	tags := map[string]string{"subscription": "/topic/Accounts"}
+	metrics.AssertCalled(t, "Count", MetricSubscriptionEvent, int64(1), tags)
+	metrics.AssertCalled(t, "Timing", MetricSubscriptionLag, mock.Anything, tags)
+	metrics.AssertCalled(t, "Timing", MetricSubscriptionReplayAge, mock.Anything, tags)
+}
+
+func TestStreamingClient_Subscribe_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  HttpClient
+		channel string
+		wantErr string
+	}{
+		{
+			name:    "channel not set  error",
+			client:  &cometdServerStub{},
+			wantErr: "channel needs to be provided",
+		},
+		{
+			name:    "handshake not ok  error",
+			client:  newHttpClientMock(newResponse(http.StatusUnauthorized, ""), nil),
+			channel: "/topic/Accounts",
+			wantErr: "unexpected salesforce response code: 401",
+		},
+		{
+			name: "connect refused  error",
+			client: &cometdServerStub{connects: [][]bayeuxMessage{
+				{{Channel: "/meta/connect", Error: "400::bad", Advice: &bayeuxAdvice{Reconnect: "none"}}},
+			}},
+			channel: "/topic/Accounts",
+			wantErr: "salesforce streaming connect failed: 400::bad",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewRequestHelper(tt.client, newTokenGetterMock("token", nil), "https://org.my.salesforce.com", 55)
+			err := NewStreamingClient(h).Subscribe(context.Background(), StreamingSubscription{Channel: tt.channel},
+				func(context.Context, StreamingEvent) error { return nil })
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+type refreshingTokenStub struct {
+	refreshes int
+}
+
+func (s *refreshingTokenStub) Get(context.Context) (string, error) {
+	return "token", nil
+}
+
+func (s *refreshingTokenStub) Refresh(context.Context) error {
+	s.refreshes++
+	return nil
+}
+
+func TestStreamingClient_Run(t *testing.T) {
+	stopErr := errors.New("stop")
+	tests := []struct {
+		name           string
+		server         *cometdServerStub
+		wantSubscribes []int64
+		wantRefreshes  int
+		wantAttempts   int
+		wantErr        error
+	}{
+		{
+			name: "connection lost  reconnected after the last event until attempts exhausted",
+			server: &cometdServerStub{connects: [][]bayeuxMessage{
+				{newStreamingEventMessage(5, "a"), {Channel: "/meta/connect", Successful: true}},
+			}},
+			wantSubscribes: []int64{ReplayNew, 5, 5},
+			wantAttempts:   3,
+		},
+		{
+			name: "token rejected  refreshed and reconnected",
+			server: &cometdServerStub{unauthorized: 1, connects: [][]bayeuxMessage{
+				{newStreamingEventMessage(5, "stop"), {Channel: "/meta/connect", Successful: true}},
+			}},
+			wantSubscribes: []int64{ReplayNew},
+			wantRefreshes:  1,
+			wantErr:        stopErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tg := &refreshingTokenStub{}
+			h, _ := NewRequestHelper(tt.server, tg, "https://org.my.salesforce.com", 55)
+			c := NewStreamingClient(h).SetBackoff(BackoffPolicy{Initial: time.Millisecond, MaxRetries: 2})
+
+			err := c.Run(context.Background(), StreamingSubscription{Channel: "/topic/Accounts"},
+				func(ctx context.Context, e StreamingEvent) error {
+					if e.ReplayId == 5 && tt.wantErr != nil {
+						return tt.wantErr
+					}
+					return nil
+				})
+
+			var subErr SubscriptionError
+			assert.ErrorAs(t, err, &subErr)
+			assert.Equal(t, tt.wantAttempts, subErr.Attempts)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantSubscribes, tt.server.subscribes)
+			assert.Equal(t, tt.wantRefreshes, tg.refreshes)
+		})
+	}
+}
+
+func TestTypedStreamingHandler(t *testing.T) {
+	r := NewEventRegistry()
+	RegisterEvent[orderShippedEventStub](r, "/event/Order_Shipped__e")
+
+	var got any
+	handler := TypedStreamingHandler(r, func(ctx context.Context, e StreamingEvent, v any) error {
+		got = v
+		return nil
+	})
+
+	err := handler(context.Background(), StreamingEvent{Channel: "/event/Order_Shipped__e", Payload: json.RawMessage(`{"Order_Number__c":"ORD-1"}`)})
+	assert.NoError(t, err)
+	assert.Equal(t, &orderShippedEventStub{OrderNumber: "ORD-1"}, got)
+
+	err = handler(context.Background(), StreamingEvent{Channel: "/event/Other__e", Payload: json.RawMessage(`{}`)})
+	assert.EqualError(t, err, "no event type registered for /event/Other__e")
+}
@@ -0,0 +1,49 @@
+package salesforce
+
+import (
+	"context"
+)
+
+// SubqueryResult the records of a child relationship subquery within a parent record, e.g. the Contacts of
+//
+//	SELECT Id, (SELECT Id, Email FROM Contacts) FROM Account
+//
+// Salesforce returns null rather than an empty result when a parent has no children, which decodes to an empty
+// SubqueryResult
+type SubqueryResult[E any] struct {
+	TotalSize      int    `json:"totalSize"`
+	Done           bool   `json:"done"`
+	Records        []E    `json:"records"`
+	NextRecordsUrl string `json:"nextRecordsUrl,omitempty"`
+}
+
+// Truncated whether salesforce returned only the first page of child records, see All
+func (s SubqueryResult[E]) Truncated() bool {
+	return !s.Done && len(s.NextRecordsUrl) > 0
+}
+
+// All returns every child record, fetching the remaining pages when the result is truncated
+func (s SubqueryResult[E]) All(ctx context.Context, h *RequestHelper) ([]E, error) {
+	records := append([]E{}, s.Records...)
+	next := s.NextRecordsUrl
+	done := s.Done
+	for !done && len(next) > 0 {
+		page, err := QueryMore[E](ctx, h, next)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, page.Records...)
+		next, done = page.NextRecordsUrl, page.Done
+	}
+	return records, nil
+}
+
+// FlattenSubquery returns the child records of every parent, in order, e.g. all Contacts across queried Accounts. Only
+// the returned page of each subquery is included, use All for truncated subqueries
+func FlattenSubquery[P, C any](parents []P, children func(P) SubqueryResult[C]) []C {
+	var records []C
+	for _, p := range parents {
+		records = append(records, children(p).Records...)
+	}
+	return records
+}
@@ -0,0 +1,73 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+)
+
+type contactStub struct {
+	Email string `json:"Email"`
+}
+
+type accountStub struct {
+	Name     string                      `json:"Name"`
+	Contacts SubqueryResult[contactStub] `json:"Contacts"`
+}
+
+func TestFlattenSubquery(t *testing.T) {
+	body := `[
+		{"Name":"Acme","Contacts":{"totalSize":2,"done":true,"records":[{"Email":"a@acme.com"},{"Email":"b@acme.com"}]}},
+		{"Name":"Empty","Contacts":null},
+		{"Name":"Globex","Contacts":{"totalSize":1,"done":true,"records":[{"Email":"c@globex.com"}]}}
+	]`
+	var accounts []accountStub
+	assert.NoError(t, json.Unmarshal([]byte(body), &accounts))
+
+	got := FlattenSubquery(accounts, func(a accountStub) SubqueryResult[contactStub] { return a.Contacts })
+	assert.Equal(t, []contactStub{{Email: "a@acme.com"}, {Email: "b@acme.com"}, {Email: "c@globex.com"}}, got)
+	assert.False(t, accounts[1].Contacts.Truncated())
+}
+
+func TestSubqueryResult_All(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataPath string
+		wantUrl  string
+	}{
+		{
+			name:    "truncated subquery  next page fetched",
+			wantUrl: "https://org/services/data/v55.0/query/01gxx-2000",
+		},
+		{
+			name:     "data path set  next page fetched via data path",
+			dataPath: "/salesforce/data",
+			wantUrl:  "https://org/salesforce/data/v55.0/query/01gxx-2000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newHttpClientMock(newResponse(200, `{"totalSize":3,"done":true,"records":[{"Email":"c@acme.com"}]}`), nil)
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+			if len(tt.dataPath) > 0 {
+				h.SetDataPath(tt.dataPath)
+			}
+			s := SubqueryResult[contactStub]{
+				TotalSize:      3,
+				Records:        []contactStub{{Email: "a@acme.com"}, {Email: "b@acme.com"}},
+				NextRecordsUrl: "/services/data/v55.0/query/01gxx-2000",
+			}
+			assert.True(t, s.Truncated())
+
+			got, err := s.All(context.Background(), h)
+			assert.NoError(t, err)
+			assert.Equal(t, []contactStub{{Email: "a@acme.com"}, {Email: "b@acme.com"}, {Email: "c@acme.com"}}, got)
+			client.AssertCalled(t, "Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.URL.String() == tt.wantUrl
+			}))
+		})
+	}
+}
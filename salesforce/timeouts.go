@@ -0,0 +1,73 @@
+package salesforce
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Timeouts the time allowed for each kind of operation, applied as a context deadline so a single http.Client timeout
+// doesn't have to suit the slowest of them. A zero Timeout leaves the operation to the context and http client
+type Timeouts struct {
+	// Query a query request, including reading its page of records
+	Query time.Duration
+	// Write a POST, PATCH, PUT or DELETE request, including reading its response
+	Write time.Duration
+	// Poll the whole of a wait for an asynchronous job, e.g. WaitForReport
+	Poll time.Duration
+	// Token getting the auth token of a request, which may fetch a new one
+	Token time.Duration
+}
+
+// SetTimeouts sets the time allowed for queries, writes, polling and token fetches
+func (h *RequestHelper) SetTimeouts(t Timeouts) *RequestHelper {
+	h.timeouts = t
+	return h
+}
+
+// withTimeout returns ctx with a deadline d from now, or ctx unchanged when d is 0
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// requestTimeout the Timeouts which applies to req, 0 for requests without one, including the long polls of a
+// StreamingClient which are held open by salesforce
+func (h *RequestHelper) requestTimeout(req *http.Request) time.Duration {
+	if longPoll(req) {
+		return 0
+	}
+	switch req.Method {
+	case http.MethodGet:
+		if strings.HasSuffix(req.URL.Path, "/query") || strings.HasSuffix(req.URL.Path, "/queryAll") ||
+			strings.Contains(req.URL.Path, "/query/") || strings.Contains(req.URL.Path, "/queryAll/") {
+			return h.timeouts.Query
+		}
+		return 0
+	case http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+		return h.timeouts.Write
+	default:
+		return 0
+	}
+}
+
+// longPoll whether req is a StreamingClient long poll, which salesforce holds open until events arrive
+func longPoll(req *http.Request) bool {
+	return strings.Contains(req.URL.Path, "/cometd/")
+}
+
+// cancelBody a response body which cancels the context of its request once closed, so a request timeout covers
+// reading the body as well as sending the request, and a RequestQueue worker is held until the body is read
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,107 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRequestHelper_SetTimeouts(t *testing.T) {
+	timeouts := Timeouts{Query: time.Minute, Write: 2 * time.Minute, Token: 3 * time.Minute}
+	tests := []struct {
+		name   string
+		send   func(ctx context.Context, h *RequestHelper) error
+		want   time.Duration
+		status int
+		body   string
+	}{
+		{
+			name: "query  query timeout",
+			send: func(ctx context.Context, h *RequestHelper) error {
+				_, err := Query[map[string]any](ctx, h, "SELECT Id FROM Account")
+				return err
+			},
+			want:   time.Minute,
+			status: http.StatusOK,
+			body:   `{"totalSize":0,"done":true,"records":[]}`,
+		},
+		{
+			name: "post  write timeout",
+			send: func(ctx context.Context, h *RequestHelper) error {
+				_, err := Post(ctx, h, "Account", map[string]any{"Name": "Acme"})
+				return err
+			},
+			want:   2 * time.Minute,
+			status: http.StatusCreated,
+			body:   `{"id":"001xx0000000001","success":true}`,
+		},
+		{
+			name: "get  no timeout",
+			send: func(ctx context.Context, h *RequestHelper) error {
+				_, err := Get[map[string]any](ctx, h, "Account", "001xx0000000001")
+				return err
+			},
+			status: http.StatusOK,
+			body:   `{"Id":"001xx0000000001"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, gotToken time.Duration
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				if deadline, ok := req.Context().Deadline(); ok {
+					got = time.Until(deadline)
+				}
+				return newResponse(tt.status, tt.body), nil
+			})
+			tg := new(TokenGetterMock)
+			tg.On("Get", mock.Anything).Return("token", nil).Run(func(args mock.Arguments) {
+				if deadline, ok := args.Get(0).(context.Context).Deadline(); ok {
+					gotToken = time.Until(deadline)
+				}
+			})
+			h, _ := NewRequestHelper(client, tg, "https://org", 55)
+			h.SetTimeouts(timeouts)
+
+			assert.NoError(t, tt.send(context.Background(), h))
+			assert.InDelta(t, tt.want, got, float64(time.Second))
+			assert.InDelta(t, 3*time.Minute, gotToken, float64(time.Second))
+		})
+	}
+}
+
+func TestRequestHelper_SetTimeouts_TokenCache(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).
+		Run(func(mock.Arguments) { <-release }).
+		Return(newResponse(http.StatusOK, `{"access_token":"late"}`), nil)
+	tc, err := NewTokenCache(TokenParams{HttpClient: client, Credentials: provider, Introspect: IntrospectNever, Refresh: TokenRefreshOnDemand})
+	assert.NoError(t, err)
+	h, _ := NewRequestHelper(client, tc, "https://org", 55)
+	h.SetTimeouts(Timeouts{Token: 50 * time.Millisecond})
+
+	_, err = Query[map[string]any](context.Background(), h, "SELECT Id FROM Account")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitForReport_PollTimeout(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusOK, `{"attributes":{"status":"Running"}}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+	h.SetTimeouts(Timeouts{Poll: 10 * time.Millisecond})
+
+	_, err := WaitForReport(context.Background(), h, "00Oxx0000000001", "0LGxx0000000001", time.Millisecond)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
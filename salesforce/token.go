@@ -2,218 +2,566 @@ package salesforce
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/cenkalti/backoff/v4"
-	"github.com/ellogroup/ello-golang-cache/cache"
-	"github.com/ellogroup/ello-golang-cache/driver"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 )
 
 const tokenTtl = 1 * time.Hour
 const tokenCacheTtl = 58 * time.Minute
+const tokenExpiryMargin = 1 * time.Minute
+
+// tokenFetchTimeout bounds a shared token fetch, which carries on when the caller that started it gives up
+const tokenFetchTimeout = 2 * time.Minute
+
+const (
+	productionLoginUrl = "https://login.salesforce.com"
+	sandboxLoginUrl    = "https://test.salesforce.com"
+)
+
+var validate = validator.New()
+
+// LoginEnvironment the type of org authenticated against, which determines the login host and the JWT aud claim
+type LoginEnvironment string
+
+const (
+	// LoginEnvironmentAuto detects a sandbox from the credentials baseUrl e.g. *.sandbox.my.salesforce.com
+	LoginEnvironmentAuto       LoginEnvironment = ""
+	LoginEnvironmentProduction LoginEnvironment = "production"
+	LoginEnvironmentSandbox    LoginEnvironment = "sandbox"
+)
+
+// TokenRefreshMode how the token cache keeps its token fresh
+type TokenRefreshMode string
+
+const (
+	// TokenRefreshAsync refreshes the token in the background every CacheTtl, the default
+	TokenRefreshAsync TokenRefreshMode = ""
+	// TokenRefreshOnDemand fetches a new token on the first Get after the cached token is older than CacheTtl
+	TokenRefreshOnDemand TokenRefreshMode = "on-demand"
+)
+
+// IntrospectMode when an obtained token is checked against the introspect endpoint, which requires the client secret
+type IntrospectMode string
+
+const (
+	// IntrospectAlways introspects every token obtained, the default
+	IntrospectAlways IntrospectMode = ""
+	// IntrospectFirst introspects only the first token obtained, to validate the configuration
+	IntrospectFirst IntrospectMode = "first"
+	// IntrospectNever skips introspection, for connected apps without a client secret
+	IntrospectNever IntrospectMode = "never"
+)
 
 type TokenParams struct {
 	HttpClient HttpClient             `validate:"required"`
-	SMClient   *secretsmanager.Client `validate:"required"`
-	SMKey      string                 `validate:"required"`
-	Backoff    backoff.BackOff
+	SMClient   *secretsmanager.Client `validate:"required_without=Credentials"`
+	SMKey      string                 `validate:"required_without=Credentials"`
+	// Credentials optional, overrides SMClient and SMKey to source credentials from elsewhere e.g. VaultCredentials
+	Credentials CredentialsProvider
+	// Environment optional, production or sandbox, detected from the credentials baseUrl when not set
+	Environment LoginEnvironment `validate:"omitempty,oneof=production sandbox"`
+	// Introspect optional, defaults to introspecting every token
+	Introspect IntrospectMode `validate:"omitempty,oneof=first never"`
+	// SigningMethod optional, RS256 (default) for RSA keys or ES256 for EC P-256 keys
+	SigningMethod string `validate:"omitempty,oneof=RS256 ES256"`
+	// JwtTtl optional, how long the JWT assertion is valid for, defaults to 1 hour
+	JwtTtl time.Duration `validate:"gte=0"`
+	// IssuedAt optional, adds iat and nbf claims to the JWT assertion
+	IssuedAt bool
+	// ClockSkew optional, allowance for the local clock running ahead of salesforce, iat and nbf are backdated by it
+	// and exp extended by it
+	ClockSkew time.Duration `validate:"gte=0"`
+	// RequiredScopes optional, token fetches fail without retrying if any of these scopes are not granted
+	RequiredScopes []string
+	// VerifyIdToken optional, verifies the id_token returned with the openid scope and sets Token IdTokenClaims
+	VerifyIdToken bool
+	// CacheTtl optional, how long the token cache keeps a token before replacing it, defaults to 58 minutes
+	CacheTtl time.Duration `validate:"gte=0"`
+	// Refresh optional, async (default) or on-demand, ignored by NewTokenCacheWithDriver which is always on-demand
+	Refresh TokenRefreshMode `validate:"omitempty,oneof=on-demand"`
+	// RefreshAhead optional, how long before an introspected expiry the token cache fetches a new token, defaults to
+	// 1 minute
+	RefreshAhead time.Duration `validate:"gte=0"`
+	// Metrics optional, receives the token cache hit and refresh metrics
+	Metrics Metrics
+	// Backoff optional, the backoff between token fetch retries. A single instance is shared by every fetch, so prefer
+	// BackoffPolicy when fetches run concurrently, e.g. for several users
+	Backoff backoff.BackOff
+	// BackoffPolicy optional, the backoff between token fetch retries, each fetch gets its own BackOff from the policy
+	// so concurrent fetches don't share retry state. Takes precedence over Backoff
+	BackoffPolicy *BackoffPolicy
+	// Jitter optional, full or equal, randomises the waits between token fetch retries, replacing the default
+	// backoff's own randomisation, so many clients failing together don't retry together
+	Jitter Jitter `validate:"omitempty,oneof=full equal"`
 }
 
 type TokenFetcher struct {
-	httpClient HttpClient
-	cfg        tokenFetcherCfg
-	backoff    backoff.BackOff
+	httpClient     HttpClient
+	provider       CredentialsProvider
+	env            LoginEnvironment
+	introspectMode IntrospectMode
+	requiredScopes []string
+	verifyIdToken  bool
+	signingMethod  jwt.SigningMethod
+	jwtTtl         time.Duration
+	issuedAt       bool
+	clockSkew      time.Duration
+	newBackoff     func() backoff.BackOff
+
+	mu           sync.Mutex
+	cfg          *tokenFetcherCfg
+	introspected bool
+	jwks         map[string]*rsa.PublicKey
+	jwksFetched  time.Time
+	// fetches coalesces concurrent token fetches, e.g. a cold cache hit by many goroutines, and credential loads into one
+	fetches singleflight.Group
 }
 
 type tokenFetcherCfg struct {
-	BaseUrl          string `json:"baseUrl"`
-	Hostname         string `json:"hostname"`
-	Username         string `json:"username"`
-	ClientId         string `json:"clientId"`
-	ClientSecret     string `json:"clientSecret"`
-	PrivateKeyBase64 string `json:"privateKeyBase64"`
-	privateKey       []byte
+	Credentials
+	privateKey crypto.PrivateKey
 }
 
+// NewTokenFetcher creates a TokenFetcher, the credentials are not loaded until the first Fetch
+// and are reloaded whenever salesforce rejects them, so rotated secrets are picked up without a restart
 func NewTokenFetcher(p TokenParams) (*TokenFetcher, error) {
-	if err := validateTokenParams(p); err != nil {
+	if err := validate.Struct(p); err != nil {
 		return nil, err
 	}
 
-	cfgRaw, err := p.SMClient.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{
-		SecretId: aws.String(p.SMKey),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("unable to fetch credentials from secrets manager: %w", err)
+	provider := p.Credentials
+	if provider == nil {
+		provider = NewSecretsManagerCredentials(p.SMClient, p.SMKey)
 	}
 
-	cfg := tokenFetcherCfg{}
-	if err := json.Unmarshal([]byte(*cfgRaw.SecretString), &cfg); err != nil {
-		return nil, fmt.Errorf("unable to parse credentials from secrets manager: %w", err)
+	signingMethod := jwt.GetSigningMethod(p.SigningMethod)
+	if signingMethod == nil {
+		signingMethod = jwt.SigningMethodRS256
 	}
 
-	// Decode the PK
-	cfg.privateKey, err = base64.StdEncoding.DecodeString(cfg.PrivateKeyBase64)
-	if err != nil {
-		return nil, fmt.Errorf("unable to decode private key: %w", err)
+	jwtTtl := p.JwtTtl
+	if jwtTtl == 0 {
+		jwtTtl = tokenTtl
 	}
 
-	// Retry Backoff
-	b := p.Backoff
-	if b == nil {
+	// Retry Backoff, a BackOff holds the state of one fetch's retries so each fetch gets its own
+	var newBackoff func() backoff.BackOff
+	switch {
+	case p.BackoffPolicy != nil:
+		policy := *p.BackoffPolicy
+		if policy.Jitter == JitterNone {
+			policy.Jitter = p.Jitter
+		}
+		newBackoff = policy.BackOff
+	case p.Backoff != nil:
+		b := &lockedBackOff{b: WithJitter(p.Backoff, p.Jitter)}
+		newBackoff = func() backoff.BackOff { return b }
+	case p.Jitter != JitterNone:
+		newBackoff = BackoffPolicy{Jitter: p.Jitter}.BackOff
+	default:
 		// Default exponential backoff
-		b = backoff.NewExponentialBackOff()
+		newBackoff = func() backoff.BackOff { return backoff.NewExponentialBackOff() }
 	}
 
 	tf := &TokenFetcher{
-		httpClient: p.HttpClient,
-		cfg:        cfg,
-		backoff:    b,
+		httpClient:     p.HttpClient,
+		provider:       provider,
+		env:            p.Environment,
+		introspectMode: p.Introspect,
+		requiredScopes: p.RequiredScopes,
+		verifyIdToken:  p.VerifyIdToken,
+		signingMethod:  signingMethod,
+		jwtTtl:         jwtTtl,
+		issuedAt:       p.IssuedAt,
+		clockSkew:      p.ClockSkew,
+		newBackoff:     newBackoff,
 	}
 	return tf, nil
 }
 
-func validateTokenParams(p TokenParams) error {
-	validate := validator.New()
-	if err := validate.Struct(p); err != nil {
-		return err
+// config returns the loaded credentials, loading them from the provider if not already loaded. The provider may be slow,
+// e.g. Secrets Manager, so concurrent loads share one made outside mu, which is only held to publish the result
+func (tf *TokenFetcher) config(ctx context.Context) (*tokenFetcherCfg, error) {
+	tf.mu.Lock()
+	cfg := tf.cfg
+	tf.mu.Unlock()
+	if cfg != nil {
+		return cfg, nil
+	}
+
+	ch := tf.fetches.DoChan("credentials", func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tokenFetchTimeout)
+		defer cancel()
+		cfg, err := tf.loadConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tf.mu.Lock()
+		tf.cfg = cfg
+		tf.mu.Unlock()
+		return cfg, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("salesforce credentials load cancelled: %w", ctx.Err())
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*tokenFetcherCfg), nil
+	}
+}
+
+// loadConfig loads the credentials from the provider and parses the private key
+func (tf *TokenFetcher) loadConfig(ctx context.Context) (*tokenFetcherCfg, error) {
+	creds, err := tf.provider.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tokenFetcherCfg{Credentials: creds}
+
+	// Decode and parse the PK once per load rather than on every fetch, a malformed key won't fix itself so don't retry
+	keyPem, err := base64.StdEncoding.DecodeString(cfg.PrivateKeyBase64)
+	if err != nil {
+		return nil, backoff.Permanent(fmt.Errorf("unable to decode private key: %w", err))
+	}
+	key, err := parsePrivateKey(keyPem, cfg.PrivateKeyPassphrase)
+	if err != nil {
+		return nil, backoff.Permanent(fmt.Errorf("unable to parse private key: %w", err))
+	}
+	if err = checkSigningKey(tf.signingMethod, key); err != nil {
+		return nil, backoff.Permanent(err)
+	}
+	cfg.privateKey = key
+	return cfg, nil
+}
+
+// checkSigningKey ensures the private key is the right type for the signing method
+func checkSigningKey(method jwt.SigningMethod, key crypto.PrivateKey) error {
+	switch method {
+	case jwt.SigningMethodES256:
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return fmt.Errorf("private key must be an EC P-256 key for %s, got %T", method.Alg(), key)
+		}
+	default:
+		if _, ok := key.(*rsa.PrivateKey); !ok {
+			return fmt.Errorf("private key must be an RSA key for %s, got %T", method.Alg(), key)
+		}
 	}
 	return nil
 }
 
+// LoadCredentials loads the credentials and parses the private key now rather than on the first Fetch,
+// for services that want to fail at startup when the credentials are missing or malformed
+func (tf *TokenFetcher) LoadCredentials(ctx context.Context) error {
+	_, err := tf.config(ctx)
+	return err
+}
+
+// resetConfig drops the loaded credentials so they are re-read on the next attempt
+func (tf *TokenFetcher) resetConfig() {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	tf.cfg = nil
+}
+
 type tokenResponse struct {
-	Token string `json:"access_token"`
+	Token       string `json:"access_token"`
+	InstanceUrl string `json:"instance_url"`
+	Scope       string `json:"scope"`
+	IdToken     string `json:"id_token"`
 }
 
-func (tf TokenFetcher) Fetch(_ context.Context) (string, error) {
-	return backoff.RetryWithData[string](func() (string, error) {
-		tok, err := tf.generateJwt()
+type introspectResponse struct {
+	Active bool   `json:"active"`
+	Scope  string `json:"scope"`
+	Exp    int64  `json:"exp"`
+}
+
+// Token an access token along with the url of the instance it was issued for
+type Token struct {
+	AccessToken string
+	InstanceUrl string
+	// ExpiresAt zero when unknown, i.e. the token was not introspected
+	ExpiresAt time.Time
+	Scopes    []string
+	// IdToken the OpenID Connect id_token, only returned when the connected app has the openid scope
+	IdToken string
+	// IdTokenClaims the verified id_token claims, set when TokenParams VerifyIdToken is set
+	IdTokenClaims *IdTokenClaims
+}
+
+// expiring whether the token has expired, or will within margin
+func (t Token) expiring(margin time.Duration) bool {
+	return !t.ExpiresAt.IsZero() && time.Now().Add(margin).After(t.ExpiresAt)
+}
+
+// Fetch obtains a new access token
+func (tf *TokenFetcher) Fetch(ctx context.Context) (string, error) {
+	token, err := tf.FetchToken(ctx)
+	return token.AccessToken, err
+}
+
+// FetchToken obtains a new access token along with the instance url returned by the token endpoint
+// concurrent calls share the result of a single fetch, which isn't cancelled with the caller that started it and is
+// bounded by its own timeout, each caller still returns as soon as its own ctx is done
+func (tf *TokenFetcher) FetchToken(ctx context.Context) (Token, error) {
+	return tf.FetchTokenFor(ctx, "")
+}
+
+// FetchTokenFor obtains a new access token for username rather than the credentials username, so requests run as that
+// user with their sharing rules, the connected app must be pre-authorised for the user. An empty username is the
+// credentials username
+func (tf *TokenFetcher) FetchTokenFor(ctx context.Context, username string) (Token, error) {
+	ch := tf.fetches.DoChan("token:"+username, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tokenFetchTimeout)
+		defer cancel()
+		return tf.fetchToken(ctx, username)
+	})
+	select {
+	case <-ctx.Done():
+		return Token{}, fmt.Errorf("salesforce token fetch cancelled: %w", ctx.Err())
+	case res := <-ch:
+		return res.Val.(Token), res.Err
+	}
+}
+
+// fetchToken retries until a token is obtained, the backoff policy gives up or ctx is done
+func (tf *TokenFetcher) fetchToken(ctx context.Context, username string) (Token, error) {
+	return backoff.RetryWithData[Token](func() (Token, error) {
+		cfg, err := tf.config(ctx)
+		if err != nil {
+			return Token{}, err
+		}
+		tok, err := tf.generateJwt(cfg, username)
 		if err != nil {
-			return "", err
+			return Token{}, err
 		}
-		return tf.obtainToken(tok)
-	}, tf.backoff)
+		token, err := tf.obtainToken(ctx, cfg, tok)
+		var authErr AuthError
+		if errors.As(err, &authErr) && authErr.rejectsCredentials() {
+			// credentials may have been rotated, re-read them before the next attempt
+			tf.resetConfig()
+		}
+		return token, err
+	}, backoff.WithContext(tf.newBackoff(), ctx))
 }
 
-func (tf TokenFetcher) generateJwt() (string, error) {
-	j := jwt.New(jwt.GetSigningMethod("RS256"))
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(tf.cfg.privateKey)
-	if err != nil {
-		return "", fmt.Errorf("error parsing private key %w", err)
+// lockedBackOff guards a BackOff shared by concurrent fetches, TokenParams Backoff, which isn't safe for concurrent use
+type lockedBackOff struct {
+	mu sync.Mutex
+	b  backoff.BackOff
+}
+
+func (l *lockedBackOff) NextBackOff() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.b.NextBackOff()
+}
+
+func (l *lockedBackOff) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.b.Reset()
+}
+
+// generateJwt builds the JWT assertion for subject, the credentials username when empty
+func (tf *TokenFetcher) generateJwt(cfg *tokenFetcherCfg, subject string) (string, error) {
+	if len(subject) == 0 {
+		subject = cfg.Username
 	}
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Issuer:    cfg.ClientId,
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(now.Add(tf.jwtTtl + tf.clockSkew)),
+		ID:        uuid.New().String(),
+	}
+	if tf.issuedAt {
+		claims.IssuedAt = jwt.NewNumericDate(now.Add(-tf.clockSkew))
+		claims.NotBefore = jwt.NewNumericDate(now.Add(-tf.clockSkew))
+	}
+
+	j := jwt.New(tf.signingMethod)
 	j.Claims = struct {
 		jwt.RegisteredClaims
 		Aud string `json:"aud,omitempty"`
 	}{
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    tf.cfg.ClientId,
-			Subject:   tf.cfg.Username,
-			ExpiresAt: jwt.NewNumericDate(time.Now().Local().Add(tokenTtl)),
-			ID:        uuid.New().String(),
-		},
-		Aud: tf.cfg.Hostname,
-	}
-	tok, err := j.SignedString(key)
+		RegisteredClaims: claims,
+		Aud:              tf.audience(cfg),
+	}
+	tok, err := j.SignedString(cfg.privateKey)
 	if err != nil {
 		return "", fmt.Errorf("error generating salesforce token %w", err)
 	}
 	return tok, nil
 }
 
-func (tf TokenFetcher) obtainToken(tok string) (string, error) {
+// isSandbox whether the credentials are for a sandbox, either configured or detected from the baseUrl
+func (tf *TokenFetcher) isSandbox(cfg *tokenFetcherCfg) bool {
+	switch tf.env {
+	case LoginEnvironmentSandbox:
+		return true
+	case LoginEnvironmentProduction:
+		return false
+	}
+	host := strings.ToLower(cfg.BaseUrl)
+	return strings.Contains(host, "test.salesforce.com") || strings.Contains(host, ".sandbox.my.salesforce.com")
+}
+
+// audience the aud claim of the JWT, the configured hostname if set otherwise the login host for the environment
+func (tf *TokenFetcher) audience(cfg *tokenFetcherCfg) string {
+	if len(cfg.Hostname) > 0 {
+		return cfg.Hostname
+	}
+	if tf.isSandbox(cfg) {
+		return sandboxLoginUrl
+	}
+	return productionLoginUrl
+}
+
+// loginUrl the url the token is requested from, the configured baseUrl if set otherwise the login host for the environment
+func (tf *TokenFetcher) loginUrl(cfg *tokenFetcherCfg) string {
+	if len(cfg.BaseUrl) > 0 {
+		return strings.TrimSuffix(cfg.BaseUrl, "/")
+	}
+	if tf.isSandbox(cfg) {
+		return sandboxLoginUrl
+	}
+	return productionLoginUrl
+}
+
+func (tf *TokenFetcher) obtainToken(ctx context.Context, cfg *tokenFetcherCfg, tok string) (Token, error) {
 	data := url.Values{}
 	data.Add("assertion", tok)
 	data.Add("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
-	uri, _ := url.ParseRequestURI(fmt.Sprintf("%s/services/oauth2/token", tf.cfg.BaseUrl))
+	uri, _ := url.ParseRequestURI(fmt.Sprintf("%s/services/oauth2/token", tf.loginUrl(cfg)))
 	uri.RawQuery = data.Encode()
-	req, _ := http.NewRequest("POST", uri.String(), nil)
+	req, _ := http.NewRequestWithContext(ctx, "POST", uri.String(), nil)
 	req.Header = http.Header{
 		"Content-Type": {"application/x-www-form-urlencoded"},
 	}
 	resp, err := tf.httpClient.Do(req)
 	if err != nil {
-		return "", err
+		return Token{}, err
 	}
+	defer resp.Body.Close()
 
 	resBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return Token{}, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Token{}, newAuthError("token", resp.StatusCode, resBody)
 	}
-	defer resp.Body.Close()
 	var sfRes *tokenResponse
 	if err = json.Unmarshal(resBody, &sfRes); err != nil {
-		return "", err
+		return Token{}, err
+	}
+	if len(sfRes.Token) == 0 {
+		return Token{}, fmt.Errorf("salesforce token endpoint returned no access token: %s", resBody)
+	}
+	token := Token{AccessToken: sfRes.Token, InstanceUrl: sfRes.InstanceUrl, Scopes: strings.Fields(sfRes.Scope), IdToken: sfRes.IdToken}
+	if tf.verifyIdToken && len(sfRes.IdToken) > 0 {
+		if token.IdTokenClaims, err = tf.VerifyIdToken(ctx, sfRes.IdToken); err != nil {
+			return Token{}, err
+		}
+	}
+	if tf.shouldIntrospect() {
+		ir, err := tf.introspect(ctx, cfg, sfRes.Token)
+		if err != nil {
+			return Token{}, err
+		}
+		if !ir.Active {
+			return Token{}, fmt.Errorf("salesforce token introspected as inactive")
+		}
+		if ir.Exp > 0 {
+			token.ExpiresAt = time.Unix(ir.Exp, 0)
+		}
+		if len(ir.Scope) > 0 {
+			token.Scopes = strings.Fields(ir.Scope)
+		}
+		tf.markIntrospected()
+	}
+	if missing := missingScopes(token.Scopes, tf.requiredScopes); len(missing) > 0 {
+		return Token{}, backoff.Permanent(fmt.Errorf("salesforce token missing required scopes: %s", strings.Join(missing, ", ")))
+	}
+	return token, nil
+}
+
+func missingScopes(granted, required []string) []string {
+	var missing []string
+	for _, r := range required {
+		if !slices.Contains(granted, r) {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
+func (tf *TokenFetcher) shouldIntrospect() bool {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	switch tf.introspectMode {
+	case IntrospectNever:
+		return false
+	case IntrospectFirst:
+		return !tf.introspected
 	}
-	return tf.introspect(sfRes.Token)
+	return true
+}
+
+func (tf *TokenFetcher) markIntrospected() {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	tf.introspected = true
 }
 
-func (tf TokenFetcher) introspect(token string) (string, error) {
+func (tf *TokenFetcher) introspect(ctx context.Context, cfg *tokenFetcherCfg, token string) (introspectResponse, error) {
 	data := url.Values{}
 	data.Add("token", token)
 	data.Add("token_type_hint", "access_token")
-	data.Add("client_id", tf.cfg.ClientId)
-	data.Add("client_secret", tf.cfg.ClientSecret)
-	uri, _ := url.ParseRequestURI(fmt.Sprintf("%s/services/oauth2/introspect", tf.cfg.BaseUrl))
+	data.Add("client_id", cfg.ClientId)
+	data.Add("client_secret", cfg.ClientSecret)
+	uri, _ := url.ParseRequestURI(fmt.Sprintf("%s/services/oauth2/introspect", tf.loginUrl(cfg)))
 	uri.RawQuery = data.Encode()
-	req, _ := http.NewRequest("POST", uri.String(), nil)
+	req, _ := http.NewRequestWithContext(ctx, "POST", uri.String(), nil)
 	resp, err := tf.httpClient.Do(req)
 	if err != nil {
-		return "", err
-	}
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return "", fmt.Errorf("failed Call to introspect token: %v", resp)
+		return introspectResponse{}, err
 	}
 	defer resp.Body.Close()
-	return token, nil
-}
 
-type TokenCache struct {
-	c *cache.KeylessRecordCache[string]
-}
-
-// NewTokenCache creates a default implementation of a salesforce token cache
-// using async type of cache.KeylessRecordCache and storing in memory with driver.NewMemoryCache
-// with a ~1 hour TTL/refresh rate (slightly less to unsure token doesn't expire before cache becomes stale)
-// for more info see: https://ellogroup.atlassian.net/wiki/spaces/EP/pages/13402137/Salesforce+Package#TokenFetcher-and-TokenCache
-func NewTokenCache(p TokenParams) (*TokenCache, error) {
-	tf, err := NewTokenFetcher(p)
+	resBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return introspectResponse{}, err
 	}
-	return &TokenCache{
-		cache.NewKeylessRecordCacheAsync[string](
-			driver.NewMemoryCache[int, cache.RecordCacheItem[string]](),
-			tf,
-			tokenCacheTtl,
-		),
-	}, nil
-}
-func NewTokenCacheWithLogger(p TokenParams, log *zap.Logger) (*TokenCache, error) {
-	tf, err := NewTokenFetcher(p)
-	if err != nil {
-		return nil, err
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return introspectResponse{}, newAuthError("introspect", resp.StatusCode, resBody)
 	}
-	return &TokenCache{
-		cache.NewKeylessRecordCacheAsyncWithLogger[string](
-			driver.NewMemoryCache[int, cache.RecordCacheItem[string]](),
-			tf,
-			tokenCacheTtl,
-			log.Named("SalesforceTokenCache"),
-		),
-	}, nil
-}
-
-func (tc TokenCache) Get(ctx context.Context) (string, error) {
-	return tc.c.Get(ctx)
+	var ir introspectResponse
+	if err = json.Unmarshal(resBody, &ir); err != nil {
+		return introspectResponse{}, fmt.Errorf("unable to parse introspect response: %w", err)
+	}
+	return ir, nil
 }
@@ -0,0 +1,248 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"github.com/ellogroup/ello-golang-cache/cache"
+	"github.com/ellogroup/ello-golang-cache/driver"
+	"go.uber.org/zap"
+	"maps"
+	"sync"
+	"time"
+)
+
+type TokenCache struct {
+	d            driver.Cache[int, cache.RecordCacheItem[Token]]
+	tf           *TokenFetcher
+	ttl          time.Duration
+	refreshAhead time.Duration
+	metrics      Metrics
+	log          *zap.Logger
+	// stop cancels the background refresh of an async cache, a no-op when the token is fetched on demand
+	stop context.CancelFunc
+}
+
+// NewTokenCache creates a default implementation of a salesforce token cache
+// refreshing the token in the background, see KeepWarm, and storing in memory with driver.NewMemoryCache
+// with a ~1 hour TTL/refresh rate (slightly less to unsure token doesn't expire before cache becomes stale)
+// tokens with an introspected expiry are also refreshed on demand just before they expire
+// the TTL and refresh mode can be changed with TokenParams CacheTtl, Refresh and RefreshAhead
+// for more info see: https://ellogroup.atlassian.net/wiki/spaces/EP/pages/13402137/Salesforce+Package#TokenFetcher-and-TokenCache
+func NewTokenCache(p TokenParams) (*TokenCache, error) {
+	return newTokenCache(p, newMemoryTokenDriver(), zap.NewNop(), p.Refresh)
+}
+func NewTokenCacheWithLogger(p TokenParams, log *zap.Logger) (*TokenCache, error) {
+	return newTokenCache(p, newMemoryTokenDriver(), log, p.Refresh)
+}
+
+// NewTokenCacheWithDriver creates a salesforce token cache stored in the given driver, e.g. driver.NewRedisCacheDriver
+// or NewDynamoDBCacheDriver, so the token is shared across instances. The token is fetched on demand rather than
+// asynchronously, so an instance starting up reuses a token already in the driver instead of re-authenticating
+func NewTokenCacheWithDriver(p TokenParams, d driver.Cache[int, cache.RecordCacheItem[Token]], log *zap.Logger) (*TokenCache, error) {
+	if d == nil {
+		return nil, fmt.Errorf("cache driver needs to be provided")
+	}
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return newTokenCache(p, d, log, TokenRefreshOnDemand)
+}
+
+func newTokenCache(p TokenParams, d driver.Cache[int, cache.RecordCacheItem[Token]], log *zap.Logger, mode TokenRefreshMode) (*TokenCache, error) {
+	tf, err := NewTokenFetcher(p)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := p.CacheTtl
+	if ttl == 0 {
+		ttl = tokenCacheTtl
+	}
+	refreshAhead := p.RefreshAhead
+	if refreshAhead == 0 {
+		refreshAhead = tokenExpiryMargin
+	}
+
+	tc := &TokenCache{
+		d:            d,
+		tf:           tf,
+		ttl:          ttl,
+		refreshAhead: refreshAhead,
+		metrics:      p.Metrics,
+		log:          log.Named("SalesforceTokenCache"),
+		stop:         func() {},
+	}
+	// an async cache is kept warm until Close, the first fetch is made in the background so construction doesn't
+	// block on the network, a Get before it completes fetches with its own ctx
+	if mode != TokenRefreshOnDemand {
+		ctx, cancel := context.WithCancel(context.Background())
+		tc.stop = cancel
+		tc.KeepWarm(ctx)
+	}
+	return tc, nil
+}
+
+// Close stops the background refresh of an async cache, the cached token is still returned, and refreshed on demand
+func (tc TokenCache) Close() {
+	tc.stop()
+}
+
+// token returns the cached token, fetching a new one if there isn't a fresh one or it is about to expire. Another
+// goroutine, or instance sharing the driver, may have already replaced the token, so the driver is read once per call
+func (tc TokenCache) token(ctx context.Context) (Token, error) {
+	if item, ok := tc.d.Get(ctx, 0); ok && !item.IsStale(tc.ttl) && !item.V.expiring(tc.refreshAhead) {
+		tc.count(MetricTokenCacheHit)
+		return item.V, nil
+	}
+	tc.count(MetricTokenCacheMiss)
+	return tc.store(ctx)
+}
+
+func (tc TokenCache) count(name string) {
+	if tc.metrics != nil {
+		tc.metrics.Count(name, 1, nil)
+	}
+}
+
+// store fetches a new token and stores it in the driver
+func (tc TokenCache) store(ctx context.Context) (Token, error) {
+	token, err := tc.fetch(ctx)
+	if err != nil {
+		return Token{}, err
+	}
+	tc.d.Set(ctx, 0, cache.RecordCacheItem[Token]{V: token, T: time.Now()})
+	return token, nil
+}
+
+// fetch fetches a token, recording the refresh metrics
+func (tc TokenCache) fetch(ctx context.Context) (Token, error) {
+	start := time.Now()
+	token, err := tc.tf.FetchToken(ctx)
+	if tc.metrics != nil {
+		tc.metrics.Count(MetricTokenCacheRefresh, 1, nil)
+		tc.metrics.Timing(MetricTokenCacheRefreshLatency, time.Since(start), nil)
+		if err != nil {
+			tc.metrics.Count(MetricTokenCacheRefreshFailure, 1, nil)
+		}
+	}
+	return token, err
+}
+
+// Warm fetches a token if one isn't already cached, call at service startup so the first request doesn't wait on the
+// JWT exchange
+func (tc TokenCache) Warm(ctx context.Context) error {
+	_, err := tc.token(ctx)
+	return err
+}
+
+// KeepWarm refreshes the token in the background, RefreshAhead before it would expire or become stale, until ctx is
+// cancelled. Async caches are kept warm from construction until Close, call it for on-demand caches where otherwise the
+// request after expiry waits on the JWT exchange
+func (tc TokenCache) KeepWarm(ctx context.Context) {
+	go func() {
+		for {
+			if !sleepContext(ctx, time.Until(tc.refreshAt(ctx))) {
+				return
+			}
+			// on failure retries are exhausted, wait before trying again rather than spinning on a broken config
+			if _, err := tc.store(ctx); err != nil {
+				if ctx.Err() == nil {
+					tc.log.Warn("unable to refresh salesforce token", zap.Error(err))
+				}
+				if !sleepContext(ctx, tc.refreshAhead) {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// refreshAt when the cached token should be replaced, now if there isn't one
+func (tc TokenCache) refreshAt(ctx context.Context) time.Time {
+	item, ok := tc.d.Get(ctx, 0)
+	if !ok {
+		return time.Now()
+	}
+	at := item.T.Add(tc.ttl)
+	if !item.V.ExpiresAt.IsZero() && item.V.ExpiresAt.Before(at) {
+		at = item.V.ExpiresAt
+	}
+	return at.Add(-tc.refreshAhead)
+}
+
+func (tc TokenCache) Get(ctx context.Context) (string, error) {
+	token, err := tc.token(ctx)
+	return token.AccessToken, err
+}
+
+// InstanceUrl returns the instance_url returned alongside the cached token
+func (tc TokenCache) InstanceUrl(ctx context.Context) (string, error) {
+	token, err := tc.token(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(token.InstanceUrl) == 0 {
+		return "", fmt.Errorf("instance url not returned by salesforce token endpoint")
+	}
+	return token.InstanceUrl, nil
+}
+
+// sleepContext waits for d, returning false if ctx is cancelled first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// lockedCache guards a driver.Cache that isn't safe for concurrent use, i.e. driver.MemoryCache, as the token is read
+// by requests while being replaced by on demand and KeepWarm refreshes
+type lockedCache[K comparable, V any] struct {
+	mu sync.RWMutex
+	d  driver.Cache[K, V]
+}
+
+func newMemoryTokenDriver() *lockedCache[int, cache.RecordCacheItem[Token]] {
+	return &lockedCache[int, cache.RecordCacheItem[Token]]{d: driver.NewMemoryCache[int, cache.RecordCacheItem[Token]]()}
+}
+
+func (l *lockedCache[K, V]) Has(ctx context.Context, key K) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.d.Has(ctx, key)
+}
+
+func (l *lockedCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.d.Get(ctx, key)
+}
+
+// All returns a copy, driver.MemoryCache returns its own map which would otherwise be read outside the lock
+func (l *lockedCache[K, V]) All(ctx context.Context) map[K]V {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return maps.Clone(l.d.All(ctx))
+}
+
+func (l *lockedCache[K, V]) Set(ctx context.Context, key K, value V) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.d.Set(ctx, key, value)
+}
+
+func (l *lockedCache[K, V]) Delete(ctx context.Context, key K) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.d.Delete(ctx, key)
+}
+
+func (l *lockedCache[K, V]) Clear(ctx context.Context) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.d.Clear(ctx)
+}
@@ -0,0 +1,292 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/ellogroup/ello-golang-cache/cache"
+	"github.com/ellogroup/ello-golang-cache/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewTokenCacheWithDriver(t *testing.T) {
+	t.Run("token already in driver, reused without fetching", func(t *testing.T) {
+		d := driver.NewMemoryCache[int, cache.RecordCacheItem[Token]]()
+		d.Set(context.Background(), 0, cache.RecordCacheItem[Token]{V: Token{AccessToken: "shared", InstanceUrl: "https://org"}, T: time.Now()})
+		provider := new(CredentialsProviderMock)
+
+		tc, err := NewTokenCacheWithDriver(TokenParams{HttpClient: new(HttpClientMock), Credentials: provider}, d, nil)
+		assert.NoError(t, err)
+
+		got, err := tc.Get(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "shared", got)
+		provider.AssertNotCalled(t, "Credentials", mock.Anything)
+	})
+
+	t.Run("driver empty, token fetched and stored in driver", func(t *testing.T) {
+		d := driver.NewMemoryCache[int, cache.RecordCacheItem[Token]]()
+		provider := new(CredentialsProviderMock)
+		provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+		client := new(HttpClientMock)
+		client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"fetched"}`), nil).Once()
+
+		tc, err := NewTokenCacheWithDriver(TokenParams{HttpClient: client, Credentials: provider, Introspect: IntrospectNever}, d, nil)
+		assert.NoError(t, err)
+
+		got, err := tc.Get(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "fetched", got)
+		stored, ok := d.Get(context.Background(), 0)
+		assert.True(t, ok)
+		assert.Equal(t, "fetched", stored.V.AccessToken)
+	})
+
+	t.Run("driver nil, returns error", func(t *testing.T) {
+		_, err := NewTokenCacheWithDriver(TokenParams{HttpClient: new(HttpClientMock), Credentials: new(CredentialsProviderMock)}, nil, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewTokenCache_RefreshOnDemand(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	client := new(HttpClientMock)
+
+	tc, err := NewTokenCache(TokenParams{
+		HttpClient:  client,
+		Credentials: provider,
+		Introspect:  IntrospectNever,
+		CacheTtl:    15 * time.Minute,
+		Refresh:     TokenRefreshOnDemand,
+	})
+	assert.NoError(t, err)
+	client.AssertNotCalled(t, "Do", mock.Anything)
+
+	client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"fetched"}`), nil).Once()
+	got, err := tc.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "fetched", got)
+
+	got, err = tc.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "fetched", got)
+	client.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestNewTokenCache_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		p    TokenParams
+	}{
+		{
+			name: "negative cache ttl",
+			p:    TokenParams{HttpClient: new(HttpClientMock), Credentials: new(CredentialsProviderMock), CacheTtl: -time.Minute},
+		},
+		{
+			name: "unknown refresh mode",
+			p:    TokenParams{HttpClient: new(HttpClientMock), Credentials: new(CredentialsProviderMock), Refresh: "sometimes"},
+		},
+		{
+			name: "negative refresh ahead",
+			p:    TokenParams{HttpClient: new(HttpClientMock), Credentials: new(CredentialsProviderMock), RefreshAhead: -time.Minute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTokenCache(tt.p)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTokenCache_Metrics(t *testing.T) {
+	m := newMetricsMock()
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"fetched"}`), nil).Once()
+
+	tc, err := NewTokenCache(TokenParams{
+		HttpClient:  client,
+		Credentials: provider,
+		Introspect:  IntrospectNever,
+		Refresh:     TokenRefreshOnDemand,
+		Metrics:     m,
+	})
+	assert.NoError(t, err)
+
+	_, err = tc.Get(context.Background())
+	assert.NoError(t, err)
+	m.AssertNumberOfCalls(t, "Count", 2)
+	m.AssertCalled(t, "Count", MetricTokenCacheMiss, int64(1), map[string]string(nil))
+	m.AssertCalled(t, "Count", MetricTokenCacheRefresh, int64(1), map[string]string(nil))
+	m.AssertCalled(t, "Timing", MetricTokenCacheRefreshLatency, mock.Anything, map[string]string(nil))
+
+	_, err = tc.Get(context.Background())
+	assert.NoError(t, err)
+	m.AssertNumberOfCalls(t, "Count", 3)
+	m.AssertCalled(t, "Count", MetricTokenCacheHit, int64(1), map[string]string(nil))
+	m.AssertNotCalled(t, "Count", MetricTokenCacheRefreshFailure, mock.Anything, mock.Anything)
+}
+
+// countingDriver counts reads of the wrapped driver
+type countingDriver struct {
+	driver.Cache[int, cache.RecordCacheItem[Token]]
+	gets atomic.Int32
+	alls atomic.Int32
+}
+
+func newCountingDriver() *countingDriver {
+	return &countingDriver{Cache: newMemoryTokenDriver()}
+}
+
+func (d *countingDriver) Get(ctx context.Context, key int) (cache.RecordCacheItem[Token], bool) {
+	d.gets.Add(1)
+	return d.Cache.Get(ctx, key)
+}
+
+func (d *countingDriver) All(ctx context.Context) map[int]cache.RecordCacheItem[Token] {
+	d.alls.Add(1)
+	return d.Cache.All(ctx)
+}
+
+func TestTokenCache_SingleDriverRead(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"fetched"}`), nil).Once()
+	d := newCountingDriver()
+
+	tc, err := NewTokenCacheWithDriver(TokenParams{HttpClient: client, Credentials: provider, Introspect: IntrospectNever, Metrics: newMetricsMock()}, d, nil)
+	assert.NoError(t, err)
+
+	_, err = tc.Get(context.Background())
+	assert.NoError(t, err)
+	_, err = tc.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), d.gets.Load())
+}
+
+func TestTokenCache_Warm(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"warm"}`), nil).Once()
+
+	tc, err := NewTokenCache(TokenParams{HttpClient: client, Credentials: provider, Introspect: IntrospectNever, Refresh: TokenRefreshOnDemand})
+	assert.NoError(t, err)
+	client.AssertNotCalled(t, "Do", mock.Anything)
+
+	assert.NoError(t, tc.Warm(context.Background()))
+	client.AssertNumberOfCalls(t, "Do", 1)
+
+	got, err := tc.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "warm", got)
+	client.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestTokenCache_KeepWarm(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"first"}`), nil).Once()
+	client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"second"}`), nil).Once()
+
+	tc, err := NewTokenCache(TokenParams{
+		HttpClient:   client,
+		Credentials:  provider,
+		Introspect:   IntrospectNever,
+		Refresh:      TokenRefreshOnDemand,
+		CacheTtl:     time.Second + 100*time.Millisecond,
+		RefreshAhead: time.Second,
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tc.KeepWarm(ctx)
+
+	assert.Eventually(t, func() bool {
+		got, _ := tc.Get(context.Background())
+		return got == "second"
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+}
+
+func TestTokenCache_ContextDeadline(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(tc *TokenCache, ctx context.Context) error
+	}{
+		{
+			name: "get returns when ctx deadline passes",
+			call: func(tc *TokenCache, ctx context.Context) error {
+				_, err := tc.Get(ctx)
+				return err
+			},
+		},
+		{
+			name: "warm returns when ctx deadline passes",
+			call: func(tc *TokenCache, ctx context.Context) error {
+				return tc.Warm(ctx)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(CredentialsProviderMock)
+			provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+			release := make(chan struct{})
+			t.Cleanup(func() { close(release) })
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(isTokenRequest)).
+				Run(func(mock.Arguments) { <-release }).
+				Return(newResponse(200, `{"access_token":"late"}`), nil)
+
+			tc, err := NewTokenCache(TokenParams{HttpClient: client, Credentials: provider, Introspect: IntrospectNever, Refresh: TokenRefreshOnDemand})
+			assert.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			assert.ErrorIs(t, tt.call(tc, ctx), context.DeadlineExceeded)
+		})
+	}
+}
+
+func TestNewTokenCache_RefreshAsync(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	release := make(chan struct{})
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).
+		Run(func(mock.Arguments) { <-release }).
+		Return(func(*http.Request) (*http.Response, error) {
+			return newResponse(200, `{"access_token":"background"}`), nil
+		})
+
+	// construction mustn't wait on the first fetch
+	tc, err := NewTokenCache(TokenParams{
+		HttpClient:   client,
+		Credentials:  provider,
+		Introspect:   IntrospectNever,
+		CacheTtl:     200 * time.Millisecond,
+		RefreshAhead: 100 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		item, ok := tc.d.Get(context.Background(), 0)
+		return ok && item.V.AccessToken == "background"
+	}, time.Second, 10*time.Millisecond)
+
+	tc.Close()
+	time.Sleep(50 * time.Millisecond)
+	calls := len(client.Calls)
+	time.Sleep(300 * time.Millisecond)
+	client.AssertNumberOfCalls(t, "Do", calls)
+}
@@ -0,0 +1,567 @@
+package salesforce
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type CredentialsProviderMock struct {
+	mock.Mock
+}
+
+func (m *CredentialsProviderMock) Credentials(ctx context.Context) (Credentials, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(Credentials), args.Error(1)
+}
+
+func newTestCredentials(t *testing.T) Credentials {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return Credentials{
+		BaseUrl:          "https://login.salesforce.com",
+		Hostname:         "https://login.salesforce.com",
+		Username:         "user@example.com",
+		ClientId:         "client-id",
+		ClientSecret:     "client-secret",
+		PrivateKeyBase64: base64.StdEncoding.EncodeToString(keyPem),
+	}
+}
+
+func newResponse(statusCode int, body string) *http.Response {
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(body)), ContentLength: -1}
+}
+
+func isTokenRequest(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/services/oauth2/token")
+}
+
+func isIntrospectRequest(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/services/oauth2/introspect")
+}
+
+func TestNewTokenFetcher_LazyCredentials(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+
+	_, err := NewTokenFetcher(TokenParams{HttpClient: new(HttpClientMock), Credentials: provider})
+
+	assert.NoError(t, err)
+	provider.AssertNotCalled(t, "Credentials", mock.Anything)
+}
+
+func TestTokenFetcher_Fetch(t *testing.T) {
+	creds := newTestCredentials(t)
+
+	t.Run("credentials loaded once across fetches", func(t *testing.T) {
+		provider := new(CredentialsProviderMock)
+		provider.On("Credentials", mock.Anything).Return(creds, nil)
+		client := new(HttpClientMock)
+		for i := 0; i < 2; i++ {
+			client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"token"}`), nil).Once()
+			client.On("Do", mock.MatchedBy(isIntrospectRequest)).Return(newResponse(200, `{"active":true}`), nil).Once()
+		}
+
+		tf, _ := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider, Backoff: &backoff.StopBackOff{}})
+		for i := 0; i < 2; i++ {
+			got, err := tf.Fetch(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "token", got)
+		}
+		provider.AssertNumberOfCalls(t, "Credentials", 1)
+	})
+
+	t.Run("credentials rejected, credentials reloaded on retry", func(t *testing.T) {
+		provider := new(CredentialsProviderMock)
+		provider.On("Credentials", mock.Anything).Return(creds, nil)
+		client := new(HttpClientMock)
+		client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(400, `{"error":"invalid_grant"}`), nil).Once()
+		client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"token"}`), nil)
+		client.On("Do", mock.MatchedBy(isIntrospectRequest)).Return(newResponse(200, `{"active":true}`), nil)
+
+		tf, _ := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider, Backoff: &backoff.ZeroBackOff{}})
+		got, err := tf.Fetch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "token", got)
+		provider.AssertNumberOfCalls(t, "Credentials", 2)
+	})
+
+	t.Run("provider error, returns error", func(t *testing.T) {
+		provider := new(CredentialsProviderMock)
+		provider.On("Credentials", mock.Anything).Return(Credentials{}, errors.New("provider error"))
+
+		tf, _ := NewTokenFetcher(TokenParams{HttpClient: new(HttpClientMock), Credentials: provider, Backoff: &backoff.StopBackOff{}})
+		_, err := tf.Fetch(context.Background())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenFetcher_audience(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      LoginEnvironment
+		cfg      tokenFetcherCfg
+		wantAud  string
+		wantHost string
+	}{
+		{
+			name:     "hostname configured, hostname used for aud",
+			cfg:      tokenFetcherCfg{Credentials: Credentials{BaseUrl: "https://org.my.salesforce.com", Hostname: "https://custom.example.com"}},
+			wantAud:  "https://custom.example.com",
+			wantHost: "https://org.my.salesforce.com",
+		},
+		{
+			name:     "production baseUrl, production aud",
+			cfg:      tokenFetcherCfg{Credentials: Credentials{BaseUrl: "https://org.my.salesforce.com"}},
+			wantAud:  "https://login.salesforce.com",
+			wantHost: "https://org.my.salesforce.com",
+		},
+		{
+			name:     "sandbox my domain baseUrl, sandbox aud",
+			cfg:      tokenFetcherCfg{Credentials: Credentials{BaseUrl: "https://org--uat.sandbox.my.salesforce.com/"}},
+			wantAud:  "https://test.salesforce.com",
+			wantHost: "https://org--uat.sandbox.my.salesforce.com",
+		},
+		{
+			name:     "sandbox environment without baseUrl, sandbox login host",
+			env:      LoginEnvironmentSandbox,
+			wantAud:  "https://test.salesforce.com",
+			wantHost: "https://test.salesforce.com",
+		},
+		{
+			name:     "production environment overrides detection",
+			env:      LoginEnvironmentProduction,
+			cfg:      tokenFetcherCfg{Credentials: Credentials{BaseUrl: "https://test.salesforce.com"}},
+			wantAud:  "https://login.salesforce.com",
+			wantHost: "https://test.salesforce.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf := &TokenFetcher{env: tt.env}
+			assert.Equal(t, tt.wantAud, tf.audience(&tt.cfg))
+			assert.Equal(t, tt.wantHost, tf.loginUrl(&tt.cfg))
+		})
+	}
+}
+
+func TestTokenFetcher_IntrospectMode(t *testing.T) {
+	creds := newTestCredentials(t)
+	tests := []struct {
+		name               string
+		mode               IntrospectMode
+		wantIntrospectCall int
+	}{
+		{name: "always, introspected every fetch", mode: IntrospectAlways, wantIntrospectCall: 2},
+		{name: "first, introspected on first fetch only", mode: IntrospectFirst, wantIntrospectCall: 1},
+		{name: "never, not introspected", mode: IntrospectNever, wantIntrospectCall: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(CredentialsProviderMock)
+			provider.On("Credentials", mock.Anything).Return(creds, nil)
+			client := new(HttpClientMock)
+			for i := 0; i < 2; i++ {
+				client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"token"}`), nil).Once()
+				client.On("Do", mock.MatchedBy(isIntrospectRequest)).Return(newResponse(200, `{"active":true}`), nil).Once()
+			}
+
+			tf, _ := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider, Introspect: tt.mode, Backoff: &backoff.StopBackOff{}})
+			for i := 0; i < 2; i++ {
+				_, err := tf.Fetch(context.Background())
+				assert.NoError(t, err)
+			}
+
+			introspectCalls := 0
+			for _, call := range client.Calls {
+				if isIntrospectRequest(call.Arguments.Get(0).(*http.Request)) {
+					introspectCalls++
+				}
+			}
+			assert.Equal(t, tt.wantIntrospectCall, introspectCalls)
+		})
+	}
+}
+
+func TestTokenFetcher_FetchToken_Introspection(t *testing.T) {
+	creds := newTestCredentials(t)
+	tests := []struct {
+		name           string
+		tokenBody      string
+		introspectBody string
+		requiredScopes []string
+		want           Token
+		wantErr        assert.ErrorAssertionFunc
+	}{
+		{
+			name:           "introspected expiry and scopes, returned on token",
+			tokenBody:      `{"access_token":"token","instance_url":"https://org.my.salesforce.com","scope":"api"}`,
+			introspectBody: `{"active":true,"scope":"api refresh_token","exp":1900000000}`,
+			requiredScopes: []string{"api"},
+			want: Token{
+				AccessToken: "token",
+				InstanceUrl: "https://org.my.salesforce.com",
+				ExpiresAt:   time.Unix(1900000000, 0),
+				Scopes:      []string{"api", "refresh_token"},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:           "inactive token, returns error",
+			tokenBody:      `{"access_token":"token"}`,
+			introspectBody: `{"active":false}`,
+			wantErr:        assert.Error,
+		},
+		{
+			name:           "required scope missing, returns error",
+			tokenBody:      `{"access_token":"token","scope":"api"}`,
+			introspectBody: `{"active":true,"scope":"api"}`,
+			requiredScopes: []string{"api", "cdp_query_api"},
+			wantErr:        assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(CredentialsProviderMock)
+			provider.On("Credentials", mock.Anything).Return(creds, nil)
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, tt.tokenBody), nil).Once()
+			client.On("Do", mock.MatchedBy(isIntrospectRequest)).Return(newResponse(200, tt.introspectBody), nil).Once()
+
+			tf, _ := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider, RequiredScopes: tt.requiredScopes, Backoff: &backoff.StopBackOff{}})
+			got, err := tf.FetchToken(context.Background())
+			if !tt.wantErr(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestToken_expiring(t *testing.T) {
+	assert.False(t, Token{}.expiring(time.Minute))
+	assert.False(t, Token{ExpiresAt: time.Now().Add(time.Hour)}.expiring(time.Minute))
+	assert.True(t, Token{ExpiresAt: time.Now().Add(30 * time.Second)}.expiring(time.Minute))
+	assert.True(t, Token{ExpiresAt: time.Now().Add(10 * time.Minute)}.expiring(15*time.Minute))
+}
+
+func TestTokenFetcher_LoadCredentials(t *testing.T) {
+	valid := newTestCredentials(t)
+	tests := []struct {
+		name    string
+		creds   Credentials
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "valid key, no error", creds: valid, wantErr: assert.NoError},
+		{name: "key not base64, returns error", creds: Credentials{PrivateKeyBase64: "not base64!"}, wantErr: assert.Error},
+		{name: "key not a pem, returns error", creds: Credentials{PrivateKeyBase64: base64.StdEncoding.EncodeToString([]byte("not a pem"))}, wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(CredentialsProviderMock)
+			provider.On("Credentials", mock.Anything).Return(tt.creds, nil)
+
+			tf, _ := NewTokenFetcher(TokenParams{HttpClient: new(HttpClientMock), Credentials: provider})
+			tt.wantErr(t, tf.LoadCredentials(context.Background()))
+		})
+	}
+}
+
+func TestTokenFetcher_LoadCredentials_Concurrent(t *testing.T) {
+	release := make(chan struct{})
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).
+		Run(func(mock.Arguments) { <-release }).
+		Return(newTestCredentials(t), nil).Once()
+	tf, _ := NewTokenFetcher(TokenParams{HttpClient: new(HttpClientMock), Credentials: provider, Introspect: IntrospectFirst})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, tf.LoadCredentials(context.Background()))
+		}()
+	}
+	// a slow secret load doesn't hold the lock introspection checks wait on
+	assert.Eventually(t, func() bool { return tf.shouldIntrospect() }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	provider.AssertNumberOfCalls(t, "Credentials", 1)
+}
+
+func TestTokenFetcher_Fetch_MalformedKeyNotRetried(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(Credentials{PrivateKeyBase64: "bm90IGEgcGVt"}, nil)
+
+	tf, _ := NewTokenFetcher(TokenParams{HttpClient: new(HttpClientMock), Credentials: provider, Backoff: &backoff.ZeroBackOff{}})
+	_, err := tf.Fetch(context.Background())
+
+	assert.Error(t, err)
+	provider.AssertNumberOfCalls(t, "Credentials", 1)
+}
+
+func TestTokenFetcher_SigningMethod(t *testing.T) {
+	rsaCreds := newTestCredentials(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDer, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecCreds := rsaCreds
+	ecCreds.PrivateKeyBase64 = base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDer}))
+
+	tests := []struct {
+		name          string
+		signingMethod string
+		creds         Credentials
+		wantAlg       string
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{name: "default with rsa key, signed with RS256", creds: rsaCreds, wantAlg: "RS256", wantErr: assert.NoError},
+		{name: "ES256 with ec key, signed with ES256", signingMethod: "ES256", creds: ecCreds, wantAlg: "ES256", wantErr: assert.NoError},
+		{name: "ES256 with rsa key, returns error", signingMethod: "ES256", creds: rsaCreds, wantErr: assert.Error},
+		{name: "RS256 with ec key, returns error", signingMethod: "RS256", creds: ecCreds, wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(CredentialsProviderMock)
+			provider.On("Credentials", mock.Anything).Return(tt.creds, nil)
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{"access_token":"token"}`), nil).Once()
+
+			tf, err := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider, SigningMethod: tt.signingMethod, Introspect: IntrospectNever, Backoff: &backoff.StopBackOff{}})
+			assert.NoError(t, err)
+
+			_, err = tf.Fetch(context.Background())
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assertion := client.Calls[0].Arguments.Get(0).(*http.Request).URL.Query().Get("assertion")
+			parsed, _, err := jwt.NewParser().ParseUnverified(assertion, jwt.MapClaims{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAlg, parsed.Method.Alg())
+		})
+	}
+}
+
+func TestTokenFetcher_generateJwt_Claims(t *testing.T) {
+	creds := newTestCredentials(t)
+	tests := []struct {
+		name      string
+		p         TokenParams
+		wantExp   time.Duration
+		wantIat   bool
+		wantNbfAt time.Duration
+	}{
+		{name: "defaults, 1 hour exp without iat", p: TokenParams{}, wantExp: time.Hour},
+		{name: "custom ttl, exp set from ttl", p: TokenParams{JwtTtl: 3 * time.Minute}, wantExp: 3 * time.Minute},
+		{
+			name:      "issued at with clock skew, iat and nbf backdated and exp extended",
+			p:         TokenParams{JwtTtl: 3 * time.Minute, IssuedAt: true, ClockSkew: 30 * time.Second},
+			wantExp:   3*time.Minute + 30*time.Second,
+			wantIat:   true,
+			wantNbfAt: -30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(CredentialsProviderMock)
+			provider.On("Credentials", mock.Anything).Return(creds, nil)
+			tt.p.HttpClient = new(HttpClientMock)
+			tt.p.Credentials = provider
+
+			tf, err := NewTokenFetcher(tt.p)
+			assert.NoError(t, err)
+			cfg, err := tf.config(context.Background())
+			assert.NoError(t, err)
+
+			now := time.Now()
+			tok, err := tf.generateJwt(cfg, "")
+			assert.NoError(t, err)
+
+			claims := jwt.RegisteredClaims{}
+			_, _, err = jwt.NewParser().ParseUnverified(tok, &claims)
+			assert.NoError(t, err)
+			assert.WithinDuration(t, now.Add(tt.wantExp), claims.ExpiresAt.Time, 2*time.Second)
+			if !tt.wantIat {
+				assert.Nil(t, claims.IssuedAt)
+				assert.Nil(t, claims.NotBefore)
+				return
+			}
+			assert.WithinDuration(t, now.Add(tt.wantNbfAt), claims.IssuedAt.Time, 2*time.Second)
+			assert.WithinDuration(t, now.Add(tt.wantNbfAt), claims.NotBefore.Time, 2*time.Second)
+		})
+	}
+}
+
+func TestTokenFetcher_FetchToken_Concurrent(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	release := make(chan struct{})
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).
+		Run(func(mock.Arguments) { <-release }).
+		Return(newResponse(200, `{"access_token":"token"}`), nil).Once()
+
+	tf, err := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider, Introspect: IntrospectNever})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 10)
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokens[i], _ = tf.Fetch(context.Background())
+		}()
+	}
+	// give the goroutines time to join the in flight fetch before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	client.AssertNumberOfCalls(t, "Do", 1)
+	for _, tok := range tokens {
+		assert.Equal(t, "token", tok)
+	}
+}
+
+func TestTokenFetcher_FetchTokenFor_ConcurrentRetries(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(503, ``), nil).Times(2)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).Return(func(*http.Request) (*http.Response, error) {
+		return newResponse(200, `{"access_token":"token"}`), nil
+	})
+
+	tf, err := NewTokenFetcher(TokenParams{
+		HttpClient:    client,
+		Credentials:   provider,
+		Introspect:    IntrospectNever,
+		BackoffPolicy: &BackoffPolicy{Initial: time.Millisecond, Jitter: JitterFull},
+	})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for _, username := range []string{"first@example.com", "second@example.com"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := tf.FetchTokenFor(context.Background(), username)
+			assert.NoError(t, err)
+			assert.Equal(t, "token", token.AccessToken)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTokenFetcher_Fetch_ContextCancelled(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).
+		Run(func(mock.Arguments) { close(started); <-release }).
+		Return(newResponse(200, `{"access_token":"token"}`), nil).Once()
+
+	tf, err := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider, Introspect: IntrospectNever})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err = tf.Fetch(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// the fetch carries on for callers still waiting, rather than failing with the caller that started it
+	got := make(chan string)
+	go func() {
+		tok, _ := tf.Fetch(context.Background())
+		got <- tok
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	assert.Equal(t, "token", <-got)
+	client.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestTokenFetcher_FetchToken_AuthError(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		wantErr  AuthError
+		wantHint bool
+	}{
+		{
+			name: "invalid_grant, decoded with hint",
+			resp: newResponse(400, `{"error":"invalid_grant","error_description":"user hasn't approved this consumer"}`),
+			wantErr: AuthError{
+				Endpoint:    "token",
+				StatusCode:  400,
+				Code:        "invalid_grant",
+				Description: "user hasn't approved this consumer",
+			},
+			wantHint: true,
+		},
+		{
+			name:    "non json body, status kept",
+			resp:    newResponse(503, `<html>Service Unavailable</html>`),
+			wantErr: AuthError{Endpoint: "token", StatusCode: 503},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(CredentialsProviderMock)
+			provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(isTokenRequest)).Return(tt.resp, nil).Once()
+			tf, _ := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider, Backoff: &backoff.StopBackOff{}})
+
+			_, err := tf.FetchToken(context.Background())
+			var authErr AuthError
+			assert.ErrorAs(t, err, &authErr)
+			assert.Equal(t, tt.wantErr, authErr)
+			assert.Equal(t, tt.wantHint, len(authErr.Hint()) > 0)
+		})
+	}
+
+	t.Run("empty access token, returns error", func(t *testing.T) {
+		provider := new(CredentialsProviderMock)
+		provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+		client := new(HttpClientMock)
+		client.On("Do", mock.MatchedBy(isTokenRequest)).Return(newResponse(200, `{}`), nil).Once()
+		tf, _ := NewTokenFetcher(TokenParams{HttpClient: client, Credentials: provider, Backoff: &backoff.StopBackOff{}})
+
+		_, err := tf.FetchToken(context.Background())
+		assert.ErrorContains(t, err, "no access token")
+	})
+}
@@ -0,0 +1,248 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ToolingQuery queries Tooling API objects, e.g. ApexClass, CustomField or MetadataContainer, page through the
+// results with QueryMore
+func ToolingQuery[E any](ctx context.Context, h *RequestHelper, q string) (*QueryResponse[E], error) {
+	reqUrl, err := h.dataUrl(ctx, "/tooling/query?q="+url.QueryEscape(q))
+	if err != nil {
+		return nil, err
+	}
+	return query[E](ctx, h, reqUrl, q)
+}
+
+// ToolingGet gets the Tooling API object of type name with id
+func ToolingGet[E any](ctx context.Context, h *RequestHelper, name, id string) (*E, error) {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/tooling/sobjects/%s/%s", name, id))
+	if err != nil {
+		return nil, err
+	}
+	return getJson[E](ctx, h, reqUrl)
+}
+
+// ToolingCreate creates a Tooling API object of type name, returning its id, e.g. a new ApexClass with its Name and
+// Body, or a CustomField with its FullName and Metadata. A CreateError is returned when salesforce rejects it
+func ToolingCreate(ctx context.Context, h *RequestHelper, name string, record any) (string, error) {
+	reqUrl, err := h.dataUrl(ctx, "/tooling/sobjects/"+name)
+	if err != nil {
+		return "", err
+	}
+	resp, err := sendTooling(ctx, h, http.MethodPost, reqUrl, record)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", newCreateError(name, resp.StatusCode, resBody)
+	}
+	var parsedResp PostResponse
+	if err = h.unmarshal(resBody, &parsedResp); err != nil {
+		return "", fmt.Errorf("unable to parse response body: %w", err)
+	}
+	return parsedResp.Id, nil
+}
+
+// ToolingUpdate updates the Tooling API object of type name with id, e.g. the Metadata of a CustomField. Apex is
+// updated with DeployApex
+func ToolingUpdate(ctx context.Context, h *RequestHelper, name, id string, record any) error {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/tooling/sobjects/%s/%s", name, id))
+	if err != nil {
+		return err
+	}
+	return sendToolingNoContent(ctx, h, http.MethodPatch, reqUrl, record)
+}
+
+// ToolingDelete deletes the Tooling API object of type name with id
+func ToolingDelete(ctx context.Context, h *RequestHelper, name, id string) error {
+	reqUrl, err := h.dataUrl(ctx, fmt.Sprintf("/tooling/sobjects/%s/%s", name, id))
+	if err != nil {
+		return err
+	}
+	return sendToolingNoContent(ctx, h, http.MethodDelete, reqUrl, nil)
+}
+
+// sendTooling sends record, when not nil, as the json body of a request to reqUrl
+func sendTooling(ctx context.Context, h *RequestHelper, method, reqUrl string, record any) (*http.Response, error) {
+	var reqBody io.Reader
+	if record != nil {
+		b, err := h.marshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := h.newRequest(ctx, method, reqUrl, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	return resp, nil
+}
+
+// sendToolingNoContent sends a request expecting no content in response
+func sendToolingNoContent(ctx context.Context, h *RequestHelper, method, reqUrl string, record any) error {
+	resp, err := sendTooling(ctx, h, method, reqUrl, record)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ApexMember the new body of an existing Apex class or trigger, deployed with DeployApex
+type ApexMember struct {
+	// Object ApexClassMember or ApexTriggerMember
+	Object string
+	// ContentEntityId the id of the ApexClass or ApexTrigger
+	ContentEntityId string
+	Body            string
+}
+
+// ApexClassMember the new body of the ApexClass with id
+func ApexClassMember(id, body string) ApexMember {
+	return ApexMember{Object: "ApexClassMember", ContentEntityId: id, Body: body}
+}
+
+// ApexTriggerMember the new body of the ApexTrigger with id
+func ApexTriggerMember(id, body string) ApexMember {
+	return ApexMember{Object: "ApexTriggerMember", ContentEntityId: id, Body: body}
+}
+
+// ContainerAsyncRequestState the state of a ContainerAsyncRequest
+type ContainerAsyncRequestState string
+
+const (
+	ContainerQueued      ContainerAsyncRequestState = "Queued"
+	ContainerCompleted   ContainerAsyncRequestState = "Completed"
+	ContainerFailed      ContainerAsyncRequestState = "Failed"
+	ContainerError       ContainerAsyncRequestState = "Error"
+	ContainerAborted     ContainerAsyncRequestState = "Aborted"
+	ContainerInvalidated ContainerAsyncRequestState = "Invalidated"
+)
+
+// DeployFailure a component which failed to compile or deploy
+type DeployFailure struct {
+	FullName      string `json:"fullName"`
+	ComponentType string `json:"componentType"`
+	Problem       string `json:"problem"`
+	ProblemType   string `json:"problemType"`
+	LineNumber    int    `json:"lineNumber"`
+	ColumnNumber  int    `json:"columnNumber"`
+}
+
+// ContainerAsyncRequest the compilation and deployment of a MetadataContainer
+type ContainerAsyncRequest struct {
+	Id            string                     `json:"Id"`
+	State         ContainerAsyncRequestState `json:"State"`
+	IsCheckOnly   bool                       `json:"IsCheckOnly"`
+	ErrorMsg      string                     `json:"ErrorMsg"`
+	DeployDetails *struct {
+		ComponentFailures []DeployFailure `json:"componentFailures"`
+	} `json:"DeployDetails"`
+}
+
+// Failures the components which failed to compile or deploy
+func (r ContainerAsyncRequest) Failures() []DeployFailure {
+	if r.DeployDetails == nil {
+		return nil
+	}
+	return r.DeployDetails.ComponentFailures
+}
+
+// DeployError returned by DeployApex when the deployment didn't complete, with the compile errors of its components
+type DeployError struct {
+	Request ContainerAsyncRequest
+}
+
+func (e DeployError) Error() string {
+	msg := fmt.Sprintf("apex deployment %s %s", e.Request.Id, strings.ToLower(string(e.Request.State)))
+	if len(e.Request.ErrorMsg) > 0 {
+		msg += ": " + e.Request.ErrorMsg
+	}
+	for _, f := range e.Request.Failures() {
+		msg += fmt.Sprintf(", %s line %d: %s", f.FullName, f.LineNumber, f.Problem)
+	}
+	return msg
+}
+
+// DeployApex compiles and deploys new bodies of existing Apex classes and triggers through a MetadataContainer,
+// waiting between polls of the deployment as policy sets until it finishes. With checkOnly the code is compiled but
+// not saved. A DeployError is returned with the ContainerAsyncRequest when the deployment fails. The container is
+// deleted once done
+func DeployApex(ctx context.Context, h *RequestHelper, members []ApexMember, checkOnly bool, policy BackoffPolicy) (*ContainerAsyncRequest, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("apex members need to be provided")
+	}
+	// container names are limited to 32 characters
+	containerId, err := ToolingCreate(ctx, h, "MetadataContainer", map[string]any{"Name": "deploy-" + uuid.NewString()[:25]})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create metadata container: %w", err)
+	}
+	defer func() {
+		// best effort, the container is only a staging area
+		_ = ToolingDelete(context.WithoutCancel(ctx), h, "MetadataContainer", containerId)
+	}()
+
+	for _, m := range members {
+		member := map[string]any{"MetadataContainerId": containerId, "ContentEntityId": m.ContentEntityId, "Body": m.Body}
+		if _, err = ToolingCreate(ctx, h, m.Object, member); err != nil {
+			return nil, fmt.Errorf("unable to add %s %s to metadata container: %w", m.Object, m.ContentEntityId, err)
+		}
+	}
+
+	requestId, err := ToolingCreate(ctx, h, "ContainerAsyncRequest", map[string]any{"MetadataContainerId": containerId, "IsCheckOnly": checkOnly})
+	if err != nil {
+		return nil, fmt.Errorf("unable to deploy metadata container: %w", err)
+	}
+	return waitForContainer(ctx, h, requestId, policy)
+}
+
+// waitForContainer polls a ContainerAsyncRequest until it is no longer queued
+func waitForContainer(ctx context.Context, h *RequestHelper, id string, policy BackoffPolicy) (*ContainerAsyncRequest, error) {
+	ctx, cancel := withTimeout(ctx, h.timeouts.Poll)
+	defer cancel()
+	b := policy.BackOff()
+	for {
+		r, err := ToolingGet[ContainerAsyncRequest](ctx, h, "ContainerAsyncRequest", id)
+		if err != nil {
+			return nil, err
+		}
+		switch r.State {
+		case ContainerCompleted:
+			return r, nil
+		case ContainerQueued:
+		default:
+			return r, DeployError{Request: *r}
+		}
+		wait := b.NextBackOff()
+		if wait == backoff.Stop {
+			return r, fmt.Errorf("apex deployment %s still queued after waiting", id)
+		}
+		if !sleepContext(ctx, wait) {
+			return r, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolingCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "created  id returned",
+			resp:    newResponse(http.StatusCreated, `{"id":"00Nxx0000000001","success":true,"errors":[]}`),
+			want:    "00Nxx0000000001",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "rejected  CreateError returned",
+			resp: newResponse(http.StatusBadRequest, `[{"errorCode":"DUPLICATE_DEVELOPER_NAME","message":"duplicate"}]`),
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var createErr CreateError
+				return assert.ErrorAs(t, err, &createErr, i...) && assert.True(t, createErr.HasCode("DUPLICATE_DEVELOPER_NAME"), i...)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUrl string
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				gotUrl = req.URL.String()
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := ToolingCreate(context.Background(), h, "CustomField", map[string]any{"FullName": "Account.Tier__c"})
+
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, "https://org/services/data/v55.0/tooling/sobjects/CustomField", gotUrl)
+		})
+	}
+}
+
+func TestDeployApex(t *testing.T) {
+	tests := []struct {
+		name      string
+		states    []string
+		wantState ContainerAsyncRequestState
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "completed  request returned",
+			states:    []string{`{"Id":"1drxx0000000001","State":"Queued"}`, `{"Id":"1drxx0000000001","State":"Completed"}`},
+			wantState: ContainerCompleted,
+			wantErr:   assert.NoError,
+		},
+		{
+			name: "failed  DeployError returned",
+			states: []string{`{"Id":"1drxx0000000001","State":"Failed","DeployDetails":{"componentFailures":` +
+				`[{"fullName":"AccountService","componentType":"ApexClass","problem":"Unexpected token","lineNumber":3}]}}`},
+			wantState: ContainerFailed,
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var deployErr DeployError
+				return assert.ErrorAs(t, err, &deployErr, i...) &&
+					assert.EqualError(t, err, "apex deployment 1drxx0000000001 failed, AccountService line 3: Unexpected token", i...)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			polls := 0
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				path := strings.TrimPrefix(req.URL.Path, "/services/data/v55.0/tooling/sobjects/")
+				var body map[string]any
+				if req.Body != nil {
+					b, _ := io.ReadAll(req.Body)
+					_ = json.Unmarshal(b, &body)
+				}
+				switch {
+				case req.Method == http.MethodPost && path == "MetadataContainer":
+					got = append(got, "create container")
+					return newResponse(http.StatusCreated, `{"id":"1dcxx0000000001","success":true}`), nil
+				case req.Method == http.MethodPost && path == "ApexClassMember":
+					got = append(got, "add "+body["ContentEntityId"].(string)+" to "+body["MetadataContainerId"].(string))
+					return newResponse(http.StatusCreated, `{"id":"400xx0000000001","success":true}`), nil
+				case req.Method == http.MethodPost && path == "ContainerAsyncRequest":
+					got = append(got, "deploy "+body["MetadataContainerId"].(string))
+					return newResponse(http.StatusCreated, `{"id":"1drxx0000000001","success":true}`), nil
+				case req.Method == http.MethodGet && path == "ContainerAsyncRequest/1drxx0000000001":
+					polls++
+					return newResponse(http.StatusOK, tt.states[polls-1]), nil
+				case req.Method == http.MethodDelete && path == "MetadataContainer/1dcxx0000000001":
+					got = append(got, "delete container")
+					return newResponse(http.StatusNoContent, ""), nil
+				}
+				return newResponse(http.StatusNotFound, ""), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			r, err := DeployApex(context.Background(), h, []ApexMember{ApexClassMember("01pxx0000000001", "public class AccountService {}")},
+				false, BackoffPolicy{Initial: time.Millisecond})
+
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.wantState, r.State)
+			assert.Equal(t, []string{"create container", "add 01pxx0000000001 to 1dcxx0000000001", "deploy 1dcxx0000000001", "delete container"}, got)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// LayoutType the kind of page layout, the full record page or the compact highlights
+type LayoutType string
+
+const (
+	LayoutFull    LayoutType = "Full"
+	LayoutCompact LayoutType = "Compact"
+)
+
+// LayoutMode the mode a page layout is rendered for, which changes the fields shown and which are editable
+type LayoutMode string
+
+const (
+	ModeView   LayoutMode = "View"
+	ModeEdit   LayoutMode = "Edit"
+	ModeCreate LayoutMode = "Create"
+)
+
+// ObjectInfo the UI API metadata of an object, its fields and the record types available to the running user
+type ObjectInfo struct {
+	ApiName             string                     `json:"apiName"`
+	Label               string                     `json:"label"`
+	LabelPlural         string                     `json:"labelPlural"`
+	KeyPrefix           string                     `json:"keyPrefix"`
+	Createable          bool                       `json:"createable"`
+	Updateable          bool                       `json:"updateable"`
+	Deletable           bool                       `json:"deletable"`
+	DefaultRecordTypeId string                     `json:"defaultRecordTypeId"`
+	Fields              map[string]ObjectInfoField `json:"fields"`
+	// RecordTypeInfos the record types, by id
+	RecordTypeInfos map[string]RecordTypeInfo `json:"recordTypeInfos"`
+}
+
+// RecordTypeId the id of the record type with the given name, and whether there is one
+func (o ObjectInfo) RecordTypeId(name string) (string, bool) {
+	for id, rt := range o.RecordTypeInfos {
+		if rt.Name == name {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// ObjectInfoField the UI API metadata of a field
+type ObjectInfoField struct {
+	ApiName    string `json:"apiName"`
+	Label      string `json:"label"`
+	DataType   string `json:"dataType"`
+	Length     int    `json:"length"`
+	Required   bool   `json:"required"`
+	Createable bool   `json:"createable"`
+	Updateable bool   `json:"updateable"`
+	Calculated bool   `json:"calculated"`
+	Reference  bool   `json:"reference"`
+}
+
+// RecordTypeInfo a record type of an object
+type RecordTypeInfo struct {
+	RecordTypeId             string `json:"recordTypeId"`
+	Name                     string `json:"name"`
+	Available                bool   `json:"available"`
+	DefaultRecordTypeMapping bool   `json:"defaultRecordTypeMapping"`
+	Master                   bool   `json:"master"`
+}
+
+// Layout a page layout of an object, its sections of rows of fields
+type Layout struct {
+	Id            string          `json:"id"`
+	LayoutType    LayoutType      `json:"layoutType"`
+	Mode          LayoutMode      `json:"mode"`
+	ObjectApiName string          `json:"objectApiName"`
+	RecordTypeId  string          `json:"recordTypeId"`
+	Sections      []LayoutSection `json:"sections"`
+}
+
+// Fields the api names of the fields of the layout, in layout order
+func (l Layout) Fields() []string {
+	var fields []string
+	for _, section := range l.Sections {
+		for _, row := range section.LayoutRows {
+			for _, item := range row.LayoutItems {
+				for _, c := range item.LayoutComponents {
+					if c.ComponentType == "Field" && len(c.ApiName) > 0 {
+						fields = append(fields, c.ApiName)
+					}
+				}
+			}
+		}
+	}
+	return fields
+}
+
+// LayoutSection a section of a page layout
+type LayoutSection struct {
+	Id          string      `json:"id"`
+	Heading     string      `json:"heading"`
+	Collapsible bool        `json:"collapsible"`
+	Columns     int         `json:"columns"`
+	LayoutRows  []LayoutRow `json:"layoutRows"`
+}
+
+// LayoutRow a row of a layout section, an item per column
+type LayoutRow struct {
+	LayoutItems []LayoutItem `json:"layoutItems"`
+}
+
+// LayoutItem a cell of a layout row, usually a single field but compound fields e.g. an address have several
+// components
+type LayoutItem struct {
+	Label             string            `json:"label"`
+	Required          bool              `json:"required"`
+	EditableForNew    bool              `json:"editableForNew"`
+	EditableForUpdate bool              `json:"editableForUpdate"`
+	LayoutComponents  []LayoutComponent `json:"layoutComponents"`
+}
+
+// LayoutComponent a field, or other component, of a layout item
+type LayoutComponent struct {
+	ApiName string `json:"apiName"`
+	// ComponentType Field, CustomLink, EmptySpace or VisualforcePage
+	ComponentType string `json:"componentType"`
+	Label         string `json:"label"`
+}
+
+// GetObjectInfo fetches the UI API metadata of an object, its fields and the record types available to the running
+// user
+func GetObjectInfo(ctx context.Context, h *RequestHelper, object string) (*ObjectInfo, error) {
+	if len(object) == 0 {
+		return nil, fmt.Errorf("object name needs to be provided")
+	}
+	reqUrl, err := h.dataUrl(ctx, "/ui-api/object-info/"+url.PathEscape(object))
+	if err != nil {
+		return nil, err
+	}
+	return getJson[ObjectInfo](ctx, h, reqUrl)
+}
+
+// GetLayout fetches the page layout of an object assigned to the running user's profile for a record type, e.g. to
+// render a form with the same fields as the org's record pages
+// - recordTypeId defaults to the object's default record type when empty
+func GetLayout(ctx context.Context, h *RequestHelper, object, recordTypeId string, layoutType LayoutType, mode LayoutMode) (*Layout, error) {
+	if len(object) == 0 {
+		return nil, fmt.Errorf("object name needs to be provided")
+	}
+	params := url.Values{"layoutType": {string(layoutType)}, "mode": {string(mode)}}
+	if len(recordTypeId) > 0 {
+		params.Set("recordTypeId", recordTypeId)
+	}
+	reqUrl, err := h.dataUrl(ctx, "/ui-api/layout/"+url.PathEscape(object)+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	return getJson[Layout](ctx, h, reqUrl)
+}
+
+// UiRecord a record fetched through the UI API, each field with both its value and its display value
+type UiRecord struct {
+	ApiName      string                  `json:"apiName"`
+	Id           string                  `json:"id"`
+	RecordTypeId string                  `json:"recordTypeId"`
+	Fields       map[string]UiFieldValue `json:"fields"`
+}
+
+// UiFieldValue the value of a field of a UiRecord
+type UiFieldValue struct {
+	// DisplayValue the value formatted for the running user, e.g. a currency with its symbol or a picklist's translated
+	// label, nil when the value needs no formatting
+	DisplayValue *string `json:"displayValue"`
+	// Value the raw value, a map of its fields for a lookup's record
+	Value any `json:"value"`
+}
+
+// Display the display value of a field, or its value when salesforce doesn't format it, empty for a missing field or
+// null value
+func (r UiRecord) Display(field string) string {
+	v, ok := r.Fields[field]
+	if !ok {
+		return ""
+	}
+	if v.DisplayValue != nil {
+		return *v.DisplayValue
+	}
+	switch value := v.Value.(type) {
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// GetUiRecord fetches a record through the UI API, with the fields of its page layout of layoutType in mode, so
+// display values e.g. formatted currencies and translated picklist labels come back without formatting them manually
+// - optionalFields adds fields, qualified by object e.g. Account.Industry, returned when the running user can see them
+func GetUiRecord(ctx context.Context, h *RequestHelper, id string, layoutType LayoutType, mode LayoutMode, optionalFields ...string) (*UiRecord, error) {
+	if !ValidId(id) {
+		return nil, fmt.Errorf("invalid id %q", id)
+	}
+	params := url.Values{"layoutTypes": {string(layoutType)}, "modes": {string(mode)}}
+	if len(optionalFields) > 0 {
+		params.Set("optionalFields", strings.Join(optionalFields, ","))
+	}
+	reqUrl, err := h.dataUrl(ctx, "/ui-api/records/"+id+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	return getJson[UiRecord](ctx, h, reqUrl)
+}
@@ -0,0 +1,133 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestGetObjectInfo(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/services/data/v55.0/ui-api/object-info/Account", req.URL.Path)
+		return newResponse(http.StatusOK, `{"apiName":"Account","label":"Account","labelPlural":"Accounts","keyPrefix":"001",
+			"createable":true,"updateable":true,"deletable":false,"defaultRecordTypeId":"012xx0000000001AAA",
+			"fields":{"Name":{"apiName":"Name","label":"Account Name","dataType":"String","length":255,"required":true,"createable":true,"updateable":true}},
+			"recordTypeInfos":{"012xx0000000001AAA":{"recordTypeId":"012xx0000000001AAA","name":"Customer","available":true,"defaultRecordTypeMapping":true,"master":false}}}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := GetObjectInfo(context.Background(), h, "Account")
+	assert.NoError(t, err)
+	assert.Equal(t, ObjectInfoField{ApiName: "Name", Label: "Account Name", DataType: "String", Length: 255, Required: true,
+		Createable: true, Updateable: true}, got.Fields["Name"])
+	id, ok := got.RecordTypeId("Customer")
+	assert.True(t, ok)
+	assert.Equal(t, "012xx0000000001AAA", id)
+	_, ok = got.RecordTypeId("Partner")
+	assert.False(t, ok)
+}
+
+func TestGetLayout(t *testing.T) {
+	tests := []struct {
+		name         string
+		recordTypeId string
+		wantQuery    url.Values
+		resp         *http.Response
+		wantFields   []string
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name:         "record type  layout fields returned in order",
+			recordTypeId: "012xx0000000001AAA",
+			wantQuery:    url.Values{"layoutType": {"Full"}, "mode": {"Edit"}, "recordTypeId": {"012xx0000000001AAA"}},
+			resp: newResponse(http.StatusOK, `{"id":"00hxx0000000001","layoutType":"Full","mode":"Edit","objectApiName":"Account",
+				"recordTypeId":"012xx0000000001AAA","sections":[{"id":"01Bxx0000000001","heading":"Account Information","columns":2,
+				"layoutRows":[{"layoutItems":[
+				{"label":"Account Name","required":true,"editableForNew":true,"editableForUpdate":true,"layoutComponents":[{"apiName":"Name","componentType":"Field","label":"Account Name"}]},
+				{"label":"","layoutComponents":[{"apiName":null,"componentType":"EmptySpace"}]}]},
+				{"layoutItems":[{"label":"Billing Address","layoutComponents":[
+				{"apiName":"BillingStreet","componentType":"Field"},{"apiName":"BillingCity","componentType":"Field"}]}]}]}]}`),
+			wantFields: []string{"Name", "BillingStreet", "BillingCity"},
+			wantErr:    assert.NoError,
+		},
+		{
+			name:      "no record type  default used",
+			wantQuery: url.Values{"layoutType": {"Full"}, "mode": {"Edit"}},
+			resp:      newResponse(http.StatusOK, `{"id":"00hxx0000000001","sections":[]}`),
+			wantErr:   assert.NoError,
+		},
+		{
+			name:      "not found  error returned",
+			wantQuery: url.Values{"layoutType": {"Full"}, "mode": {"Edit"}},
+			resp:      newResponse(http.StatusNotFound, ""),
+			wantErr:   assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/ui-api/layout/Account", req.URL.Path)
+				assert.Equal(t, tt.wantQuery, req.URL.Query())
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := GetLayout(context.Background(), h, "Account", tt.recordTypeId, LayoutFull, ModeEdit)
+			tt.wantErr(t, err)
+			if got != nil {
+				assert.Equal(t, tt.wantFields, got.Fields())
+			}
+		})
+	}
+}
+
+func TestGetUiRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		resp    *http.Response
+		want    map[string]string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "record  display values resolved",
+			id:   "006xx0000000001",
+			resp: newResponse(http.StatusOK, `{"apiName":"Opportunity","id":"006xx0000000001","recordTypeId":"012000000000000AAA",
+				"fields":{"Amount":{"displayValue":"£1,500.00","value":1500},"StageName":{"displayValue":"Gewonnen","value":"Closed Won"},
+				"Name":{"displayValue":null,"value":"Renewal"},"Probability":{"displayValue":null,"value":87.5},
+				"IsPrivate":{"displayValue":null,"value":false},"Description":{"displayValue":null,"value":null},
+				"Account":{"displayValue":"Acme","value":{"apiName":"Account","id":"001xx0000000001","fields":{}}}}}`),
+			want: map[string]string{"Amount": "£1,500.00", "StageName": "Gewonnen", "Name": "Renewal", "Probability": "87.5",
+				"IsPrivate": "false", "Description": "", "Account": "Acme", "Missing": ""},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid id  error returned",
+			id:      "006xx/../0000001",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/services/data/v55.0/ui-api/records/006xx0000000001", req.URL.Path)
+				assert.Equal(t, url.Values{"layoutTypes": {"Full"}, "modes": {"View"}, "optionalFields": {"Opportunity.Description,Opportunity.IsPrivate"}},
+					req.URL.Query())
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := GetUiRecord(context.Background(), h, tt.id, LayoutFull, ModeView, "Opportunity.Description", "Opportunity.IsPrivate")
+			tt.wantErr(t, err)
+			for field, want := range tt.want {
+				assert.Equal(t, want, got.Display(field), field)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type passwordStatus struct {
+	IsExpired bool `json:"isExpired"`
+}
+
+type newPassword struct {
+	NewPassword string `json:"NewPassword"`
+}
+
+// passwordUrl the url of a user's password, userId is checked as it is interpolated into the path
+func (h *RequestHelper) passwordUrl(ctx context.Context, userId string) (string, error) {
+	if !ValidId(userId) {
+		return "", fmt.Errorf("invalid userId %q", userId)
+	}
+	return h.dataUrl(ctx, fmt.Sprintf("/sobjects/User/%s/password", userId))
+}
+
+// PasswordExpired whether a user's password has expired
+func PasswordExpired(ctx context.Context, h *RequestHelper, userId string) (bool, error) {
+	reqUrl, err := h.passwordUrl(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	status, err := getJson[passwordStatus](ctx, h, reqUrl)
+	if err != nil {
+		return false, err
+	}
+	return status.IsExpired, nil
+}
+
+// SetPassword sets a user's password, salesforce rejects passwords which don't meet the org's password policy
+func SetPassword(ctx context.Context, h *RequestHelper, userId, password string) error {
+	reqUrl, err := h.passwordUrl(ctx, userId)
+	if err != nil {
+		return err
+	}
+	reqBody, err := json.Marshal(newPassword{NewPassword: password})
+	if err != nil {
+		return fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	req, err := h.newRequest(ctx, http.MethodPost, reqUrl, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request to salesforce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected salesforce response code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ResetPassword resets a user's password to one generated by salesforce, which is returned
+func ResetPassword(ctx context.Context, h *RequestHelper, userId string) (string, error) {
+	reqUrl, err := h.passwordUrl(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	resp, err := sendJson[newPassword](ctx, h, http.MethodDelete, reqUrl, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.NewPassword, nil
+}
@@ -0,0 +1,104 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+const passwordPath = "/services/data/v55.0/sobjects/User/005xx0000000001/password"
+
+func TestPasswordExpired(t *testing.T) {
+	tests := []struct {
+		name    string
+		userId  string
+		resp    *http.Response
+		want    bool
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "expired  true returned",
+			userId:  "005xx0000000001",
+			resp:    newResponse(http.StatusOK, `{"isExpired":true}`),
+			want:    true,
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid user id  error returned",
+			userId:  "005/../Account",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "not found  error returned",
+			userId:  "005xx0000000001",
+			resp:    newResponse(http.StatusNotFound, ""),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, http.MethodGet, req.Method)
+				assert.Equal(t, passwordPath, req.URL.Path)
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			got, err := PasswordExpired(context.Background(), h, tt.userId)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSetPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "set  no error",
+			resp:    newResponse(http.StatusNoContent, ""),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "rejected by policy  error returned",
+			resp:    newResponse(http.StatusBadRequest, `[{"errorCode":"INVALID_NEW_PASSWORD"}]`),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, http.MethodPost, req.Method)
+				assert.Equal(t, passwordPath, req.URL.Path)
+				b, _ := io.ReadAll(req.Body)
+				assert.JSONEq(t, `{"NewPassword":"s3cret!Pass"}`, string(b))
+				return tt.resp, nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			tt.wantErr(t, SetPassword(context.Background(), h, "005xx0000000001", "s3cret!Pass"))
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	client := new(HttpClientMock)
+	client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodDelete, req.Method)
+		assert.Equal(t, passwordPath, req.URL.Path)
+		return newResponse(http.StatusOK, `{"NewPassword":"gEn3rated"}`), nil
+	})
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+	got, err := ResetPassword(context.Background(), h, "005xx0000000001")
+	assert.NoError(t, err)
+	assert.Equal(t, "gEn3rated", got)
+}
@@ -0,0 +1,115 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"github.com/ellogroup/ello-golang-cache/cache"
+	"github.com/ellogroup/ello-golang-cache/driver"
+	"time"
+)
+
+// userTokenCacheSize the most users kept in a UserTokenCache, the least recently fetched are evicted beyond it
+const userTokenCacheSize = 1000
+
+// UserTokenCache caches a token per username, so operations can run as specific integration users and have their
+// sharing rules applied. Each user must be pre-authorised for the connected app
+type UserTokenCache struct {
+	d            driver.Cache[string, cache.RecordCacheItem[Token]]
+	tf           *TokenFetcher
+	ttl          time.Duration
+	refreshAhead time.Duration
+	size         int
+}
+
+// NewUserTokenCache creates a token cache keyed by username, tokens are fetched on demand when first used and kept for
+// CacheTtl, the Refresh token param is ignored. Stale tokens are evicted as new ones are stored, and at most 1,000
+// users are kept
+func NewUserTokenCache(p TokenParams) (*UserTokenCache, error) {
+	tf, err := NewTokenFetcher(p)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := p.CacheTtl
+	if ttl == 0 {
+		ttl = tokenCacheTtl
+	}
+	refreshAhead := p.RefreshAhead
+	if refreshAhead == 0 {
+		refreshAhead = tokenExpiryMargin
+	}
+
+	return &UserTokenCache{
+		d:            &lockedCache[string, cache.RecordCacheItem[Token]]{d: driver.NewMemoryCache[string, cache.RecordCacheItem[Token]]()},
+		tf:           tf,
+		ttl:          ttl,
+		refreshAhead: refreshAhead,
+		size:         userTokenCacheSize,
+	}, nil
+}
+
+// Token returns the cached token for username, fetching it with ctx if it isn't cached, is stale or is about to expire
+func (u *UserTokenCache) Token(ctx context.Context, username string) (Token, error) {
+	if len(username) == 0 {
+		return Token{}, fmt.Errorf("username needs to be provided")
+	}
+	if item, ok := u.d.Get(ctx, username); ok && time.Since(item.T) < u.ttl && !item.V.expiring(u.refreshAhead) {
+		return item.V, nil
+	}
+	token, err := u.tf.FetchTokenFor(ctx, username)
+	if err != nil {
+		return Token{}, err
+	}
+	u.d.Set(ctx, username, cache.RecordCacheItem[Token]{V: token, T: time.Now()})
+	u.evict(ctx)
+	return token, nil
+}
+
+// evict deletes stale tokens, then the least recently fetched until the cache is within its size
+func (u *UserTokenCache) evict(ctx context.Context) {
+	items := u.d.All(ctx)
+	for username, item := range items {
+		if time.Since(item.T) >= u.ttl {
+			u.d.Delete(ctx, username)
+			delete(items, username)
+		}
+	}
+	for len(items) > u.size {
+		oldest := ""
+		for username, item := range items {
+			if oldest == "" || item.T.Before(items[oldest].T) {
+				oldest = username
+			}
+		}
+		u.d.Delete(ctx, oldest)
+		delete(items, oldest)
+	}
+}
+
+// For returns a TokenGetter for username, e.g. for NewRequestHelperWithInstanceUrl to send requests as that user
+func (u *UserTokenCache) For(username string) UserToken {
+	return UserToken{c: u, username: username}
+}
+
+// UserToken the token of a single user from a UserTokenCache, implements InstanceTokenGetter
+type UserToken struct {
+	c        *UserTokenCache
+	username string
+}
+
+func (u UserToken) Get(ctx context.Context) (string, error) {
+	token, err := u.c.Token(ctx, u.username)
+	return token.AccessToken, err
+}
+
+// InstanceUrl returns the instance_url returned alongside the user's token
+func (u UserToken) InstanceUrl(ctx context.Context) (string, error) {
+	token, err := u.c.Token(ctx, u.username)
+	if err != nil {
+		return "", err
+	}
+	if len(token.InstanceUrl) == 0 {
+		return "", fmt.Errorf("instance url not returned by salesforce token endpoint")
+	}
+	return token.InstanceUrl, nil
+}
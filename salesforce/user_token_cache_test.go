@@ -0,0 +1,125 @@
+package salesforce
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// isTokenRequestFor matches token requests with a JWT assertion for subject
+func isTokenRequestFor(subject string) func(req *http.Request) bool {
+	return func(req *http.Request) bool {
+		if !isTokenRequest(req) {
+			return false
+		}
+		claims := jwt.RegisteredClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(req.URL.Query().Get("assertion"), &claims); err != nil {
+			return false
+		}
+		return claims.Subject == subject
+	}
+}
+
+func TestUserTokenCache(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequestFor("alice@example.com"))).
+		Return(newResponse(200, `{"access_token":"alice-token","instance_url":"https://org"}`), nil).Once()
+	client.On("Do", mock.MatchedBy(isTokenRequestFor("bob@example.com"))).
+		Return(newResponse(200, `{"access_token":"bob-token","instance_url":"https://org"}`), nil).Once()
+
+	utc, err := NewUserTokenCache(TokenParams{HttpClient: client, Credentials: provider, Introspect: IntrospectNever})
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		got, err := utc.For("alice@example.com").Get(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "alice-token", got)
+	}
+	got, err := utc.For("bob@example.com").Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "bob-token", got)
+
+	instanceUrl, err := utc.For("bob@example.com").InstanceUrl(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "https://org", instanceUrl)
+
+	client.AssertNumberOfCalls(t, "Do", 2)
+
+	_, err = utc.For("").Get(context.Background())
+	assert.Error(t, err)
+}
+
+func TestUserTokenCache_ContextDeadline(t *testing.T) {
+	provider := new(CredentialsProviderMock)
+	provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	client := new(HttpClientMock)
+	client.On("Do", mock.MatchedBy(isTokenRequest)).
+		Run(func(mock.Arguments) { <-release }).
+		Return(newResponse(200, `{"access_token":"late"}`), nil)
+
+	utc, err := NewUserTokenCache(TokenParams{HttpClient: client, Credentials: provider, Introspect: IntrospectNever})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = utc.Token(ctx, "alice@example.com")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestUserTokenCache_Evict(t *testing.T) {
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		size      int
+		usernames []string
+		wantKept  []string
+	}{
+		{
+			name:      "stale tokens evicted",
+			ttl:       50 * time.Millisecond,
+			size:      10,
+			usernames: []string{"alice@example.com", "bob@example.com"},
+			wantKept:  []string{"bob@example.com"},
+		},
+		{
+			name:      "least recently fetched evicted beyond size",
+			ttl:       time.Hour,
+			size:      2,
+			usernames: []string{"alice@example.com", "bob@example.com", "carol@example.com"},
+			wantKept:  []string{"bob@example.com", "carol@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(CredentialsProviderMock)
+			provider.On("Credentials", mock.Anything).Return(newTestCredentials(t), nil)
+			client := new(HttpClientMock)
+			client.On("Do", mock.MatchedBy(isTokenRequest)).Return(func(*http.Request) (*http.Response, error) {
+				return newResponse(200, `{"access_token":"token"}`), nil
+			})
+
+			utc, err := NewUserTokenCache(TokenParams{HttpClient: client, Credentials: provider, Introspect: IntrospectNever, CacheTtl: tt.ttl})
+			assert.NoError(t, err)
+			utc.size = tt.size
+
+			for _, username := range tt.usernames {
+				time.Sleep(60 * time.Millisecond)
+				_, err := utc.Token(context.Background(), username)
+				assert.NoError(t, err)
+			}
+			kept := make([]string, 0)
+			for username := range utc.d.All(context.Background()) {
+				kept = append(kept, username)
+			}
+			assert.ElementsMatch(t, tt.wantKept, kept)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package salesforce
+
+import (
+	"context"
+)
+
+// GetUserInfo fetches the user and org the RequestHelper's token acts as, e.g. to log them or check the configuration
+// at startup
+func GetUserInfo(ctx context.Context, h *RequestHelper) (*UserInfo, error) {
+	reqUrl, err := h.instanceUrl(ctx, "/services/oauth2/userinfo")
+	if err != nil {
+		return nil, err
+	}
+	return getJson[UserInfo](ctx, h, reqUrl)
+}
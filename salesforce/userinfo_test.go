@@ -0,0 +1,61 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestGetUserInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *HttpClientMock
+		want    *UserInfo
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "successful request  user info returned",
+			client: newHttpClientMock(&http.Response{Body: io.NopCloser(
+				bytes.NewReader([]byte(`{"user_id":"005xx0000012345","organization_id":"00Dxx0000001gEH","preferred_username":"integration@example.com","locale":"en_GB","active":true}`))),
+				StatusCode: 200,
+			}, nil),
+			want: &UserInfo{
+				UserId:            "005xx0000012345",
+				OrganizationId:    "00Dxx0000001gEH",
+				PreferredUsername: "integration@example.com",
+				Locale:            "en_GB",
+				Active:            true,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "403 status code  error returned",
+			client:  newHttpClientMock(&http.Response{Body: io.NopCloser(nil), StatusCode: 403}, nil),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "http.Do() returns error  error returned",
+			client:  newHttpClientMock(&http.Response{Body: io.NopCloser(nil), StatusCode: 0}, fmt.Errorf("http client error")),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewRequestHelper(tt.client, newTokenGetterMock("token", nil), "https://org.my.salesforce.com", 55)
+
+			got, err := GetUserInfo(context.Background(), h)
+			if !tt.wantErr(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			tt.client.AssertCalled(t, "Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.URL.String() == "https://org.my.salesforce.com/services/oauth2/userinfo"
+			}))
+		})
+	}
+}
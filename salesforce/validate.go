@@ -0,0 +1,150 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// prepareBody drops the fields of a request body's record which aren't writable when WithWritableFieldsOnly is set,
+// then checks the record against the describe of its object when WithValidation is set. update checks the fields are
+// updateable rather than createable
+func (o requestOptions) prepareBody(ctx context.Context, h *RequestHelper, name string, body []byte, update bool) ([]byte, error) {
+	if !o.validate && !o.writable {
+		return body, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+	}
+	d, err := h.cachedDescribe(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe %s: %w", name, err)
+	}
+	if o.writable {
+		if body, err = pruneFields(d, fields, update); err != nil {
+			return nil, fmt.Errorf("unable to create salesforce payload: %w", err)
+		}
+	}
+	if o.validate {
+		if err = validateFields(d, fields, update); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// pruneFields deletes the fields the running user can't create, or update, from fields, returning the remaining fields
+// marshaled. Fields missing from the describe are hidden by field level security so are deleted too
+func pruneFields(d *ObjectDescribe, fields map[string]json.RawMessage, update bool) ([]byte, error) {
+	for field := range fields {
+		if field == "attributes" {
+			continue
+		}
+		f := d.Field(field)
+		if f == nil || (update && !f.Updateable) || (!update && !f.Createable) {
+			delete(fields, field)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// validateFields checks fields against the describe of their object, returning a ValidationError of every problem
+// found. update checks the fields are updateable rather than createable and doesn't require the required fields
+func validateFields(d *ObjectDescribe, fields map[string]json.RawMessage, update bool) error {
+	var errs []FieldError
+	set := make(map[string]bool, len(fields))
+	for field, value := range fields {
+		if field == "attributes" {
+			continue
+		}
+		set[strings.ToLower(field)] = true
+		f := d.Field(field)
+		if f == nil {
+			errs = append(errs, FieldError{Field: field, Message: "not a field of the object"})
+			continue
+		}
+		if msg := validateField(*f, value, update); len(msg) > 0 {
+			errs = append(errs, FieldError{Field: field, Message: msg})
+		}
+	}
+	if !update {
+		for _, f := range d.Fields {
+			if !set[strings.ToLower(f.Name)] && f.Required() {
+				errs = append(errs, FieldError{Field: f.Name, Message: "required"})
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return ValidationError{Object: d.Name, Errors: errs}
+}
+
+// validateField checks a single field's value against its describe, returning what is wrong or an empty string
+func validateField(f FieldDescribe, value json.RawMessage, update bool) string {
+	switch {
+	case update && !f.Updateable:
+		return "not updateable, the field is read only"
+	case !update && !f.Createable:
+		return "not createable, the field is read only"
+	}
+	if string(value) == "null" {
+		if !f.Nillable && f.Type != "boolean" && (update || f.Required()) {
+			return "required"
+		}
+		return ""
+	}
+
+	switch f.Type {
+	case "boolean":
+		var b bool
+		if json.Unmarshal(value, &b) != nil {
+			return "must be a boolean"
+		}
+	case "int", "long", "double", "currency", "percent":
+		var n float64
+		if json.Unmarshal(value, &n) != nil {
+			return "must be a number"
+		}
+	case "address", "location", "base64":
+		// compound and binary values aren't checked
+	default:
+		var s string
+		if json.Unmarshal(value, &s) != nil {
+			return "must be a string"
+		}
+		if f.Length > 0 && utf8.RuneCountInString(s) > f.Length {
+			return fmt.Sprintf("longer than the maximum length of %d", f.Length)
+		}
+		if f.RestrictedPicklist {
+			return validatePicklist(f, s)
+		}
+	}
+	return ""
+}
+
+// validatePicklist checks each value of a restricted picklist, or multi-select picklist, is an active value
+func validatePicklist(f FieldDescribe, s string) string {
+	values := []string{s}
+	if f.Type == "multipicklist" {
+		values = strings.Split(s, ";")
+	}
+	for _, v := range values {
+		valid := false
+		for _, entry := range f.PicklistValues {
+			if entry.Active && entry.Value == v {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Sprintf("%q is not a value of the restricted picklist", v)
+		}
+	}
+	return ""
+}
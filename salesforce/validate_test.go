@@ -0,0 +1,176 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const opportunityDescribe = `{"name":"Opportunity","fields":[
+	{"name":"Id","type":"id","nillable":false,"createable":false,"updateable":false},
+	{"name":"Name","type":"string","length":10,"nillable":false,"createable":true,"updateable":true},
+	{"name":"Amount","type":"currency","nillable":true,"createable":true,"updateable":true},
+	{"name":"IsPrivate","type":"boolean","nillable":false,"createable":true,"updateable":true,"defaultedOnCreate":true},
+	{"name":"StageName","type":"picklist","length":255,"nillable":false,"createable":true,"updateable":true,"restrictedPicklist":true,
+		"picklistValues":[{"value":"Prospecting","active":true},{"value":"Closed Won","active":true},{"value":"Legacy","active":false}]},
+	{"name":"Regions__c","type":"multipicklist","length":4099,"nillable":true,"createable":true,"updateable":true,"restrictedPicklist":true,
+		"picklistValues":[{"value":"EMEA","active":true},{"value":"APAC","active":true}]},
+	{"name":"ExpectedRevenue","type":"currency","nillable":true,"createable":false,"updateable":false,"calculated":true}
+]}`
+
+func TestPostWithValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		record     map[string]any
+		wantPosted bool
+		wantFields []string
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "valid record  posted",
+			record:     map[string]any{"Name": "Renewal", "Amount": 1500, "StageName": "Prospecting", "Regions__c": "EMEA;APAC"},
+			wantPosted: true,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "invalid record  ValidationError returned without posting",
+			record: map[string]any{"Name": "Renewal of the year", "Amount": "1500", "IsPrivate": "yes", "StageName": "Legacy",
+				"Regions__c": "EMEA;LATAM", "ExpectedRevenue": 10, "Unknown__c": "x"},
+			wantFields: []string{"Amount", "ExpectedRevenue", "IsPrivate", "Name", "Regions__c", "StageName", "Unknown__c"},
+			wantErr:    assert.Error,
+		},
+		{
+			name:       "missing required fields  ValidationError returned without posting",
+			record:     map[string]any{"Amount": nil},
+			wantFields: []string{"Name", "StageName"},
+			wantErr:    assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posted := false
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				if strings.HasSuffix(req.URL.Path, "/describe") {
+					return newResponse(http.StatusOK, opportunityDescribe), nil
+				}
+				posted = true
+				return newResponse(http.StatusCreated, `{"id":"006xx0000000001","success":true}`), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			_, err := Post(context.Background(), h, "Opportunity", tt.record, WithValidation())
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.wantPosted, posted)
+
+			var validationErr ValidationError
+			if errors.As(err, &validationErr) {
+				var fields []string
+				for _, fe := range validationErr.Errors {
+					fields = append(fields, fe.Field)
+				}
+				assert.Equal(t, tt.wantFields, fields)
+			}
+		})
+	}
+}
+
+func TestPatchWithValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		record      map[string]any
+		opts        []RequestOption
+		wantPatched bool
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "partial update  required fields not needed",
+			record:      map[string]any{"Amount": 2000},
+			opts:        []RequestOption{WithValidation()},
+			wantPatched: true,
+			wantErr:     assert.NoError,
+		},
+		{
+			name:    "null required field  ValidationError returned",
+			record:  map[string]any{"Name": nil},
+			opts:    []RequestOption{WithValidation()},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "masked read only field  ValidationError returned",
+			record:  map[string]any{"Amount": 2000},
+			opts:    []RequestOption{WithValidation(), WithFieldMask("Amount", "ExpectedRevenue")},
+			wantErr: assert.Error,
+		},
+		{
+			name:        "no validation  patched",
+			record:      map[string]any{"Unknown__c": "x"},
+			wantPatched: true,
+			wantErr:     assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched := false
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				if strings.HasSuffix(req.URL.Path, "/describe") {
+					return newResponse(http.StatusOK, opportunityDescribe), nil
+				}
+				patched = true
+				return newResponse(http.StatusNoContent, ""), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			_, err := Patch(context.Background(), h, "Opportunity", "006xx0000000001", tt.record, tt.opts...)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.wantPatched, patched)
+		})
+	}
+}
+
+func TestPatchWithWritableFieldsOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   map[string]any
+		opts     []RequestOption
+		wantBody string
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "read only and hidden fields  dropped",
+			record:   map[string]any{"Id": "006xx0000000001", "Amount": 2000, "ExpectedRevenue": 2000, "Hidden__c": "x"},
+			opts:     []RequestOption{WithWritableFieldsOnly()},
+			wantBody: `{"Amount":2000}`,
+			wantErr:  assert.NoError,
+		},
+		{
+			name:    "with validation  remaining fields validated",
+			record:  map[string]any{"Id": "006xx0000000001", "Name": "Renewal of the year"},
+			opts:    []RequestOption{WithWritableFieldsOnly(), WithValidation()},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(HttpClientMock)
+			client.On("Do", mock.Anything).Return(func(req *http.Request) (*http.Response, error) {
+				if strings.HasSuffix(req.URL.Path, "/describe") {
+					return newResponse(http.StatusOK, opportunityDescribe), nil
+				}
+				b, _ := io.ReadAll(req.Body)
+				assert.JSONEq(t, tt.wantBody, string(b))
+				return newResponse(http.StatusNoContent, ""), nil
+			})
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org", 55)
+
+			_, err := Patch(context.Background(), h, "Opportunity", "006xx0000000001", tt.record, tt.opts...)
+			tt.wantErr(t, err)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Versions lists the REST API versions the org supports, oldest first
+func Versions(ctx context.Context, h *RequestHelper) ([]ApiVersion, error) {
+	reqUrl, err := h.instanceUrl(ctx, h.servicesDataPath())
+	if err != nil {
+		return nil, err
+	}
+	versions, err := getJson[[]ApiVersion](ctx, h, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	if versions == nil {
+		return nil, fmt.Errorf("salesforce returned no api versions")
+	}
+	return *versions, nil
+}
+
+// SelectLatestApiVersion sets the api version to the latest the org supports, less behind versions, so services
+// don't drift behind a hard-coded version. Call at startup, before the RequestHelper is shared
+func (h *RequestHelper) SelectLatestApiVersion(ctx context.Context, behind int) error {
+	if behind < 0 {
+		return fmt.Errorf("behind must not be negative")
+	}
+	versions, err := Versions(ctx, h)
+	if err != nil {
+		return err
+	}
+
+	var majors []int
+	for _, v := range versions {
+		major, err := strconv.Atoi(strings.TrimSuffix(v.Version, ".0"))
+		if err != nil {
+			continue
+		}
+		majors = append(majors, major)
+	}
+	slices.Sort(majors)
+	if len(majors) <= behind {
+		return fmt.Errorf("salesforce returned %d api versions, unable to select %d behind latest", len(majors), behind)
+	}
+	h.apiVersion = majors[len(majors)-1-behind]
+	return nil
+}
+
+// ApiVersion the api version requests are sent to
+func (h *RequestHelper) ApiVersion() int {
+	return h.apiVersion
+}
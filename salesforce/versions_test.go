@@ -0,0 +1,59 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"testing"
+)
+
+const versionsBody = `[
+	{"label":"Winter '24","url":"/services/data/v59.0","version":"59.0"},
+	{"label":"Spring '24","url":"/services/data/v60.0","version":"60.0"},
+	{"label":"Summer '23","url":"/services/data/v58.0","version":"58.0"}
+]`
+
+func TestVersions(t *testing.T) {
+	client := newHttpClientMock(&http.Response{Body: io.NopCloser(bytes.NewReader([]byte(versionsBody))), StatusCode: 200}, nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org.my.salesforce.com", 55)
+
+	got, err := Versions(context.Background(), h)
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+	assert.Equal(t, ApiVersion{Label: "Winter '24", Url: "/services/data/v59.0", Version: "59.0"}, got[0])
+	assert.Equal(t, "https://org.my.salesforce.com/services/data", client.Calls[0].Arguments.Get(0).(*http.Request).URL.String())
+}
+
+func TestVersions_NullBody(t *testing.T) {
+	client := newHttpClientMock(&http.Response{Body: io.NopCloser(bytes.NewReader([]byte(`null`))), StatusCode: 200}, nil)
+	h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org.my.salesforce.com", 55)
+
+	got, err := Versions(context.Background(), h)
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
+func TestRequestHelper_SelectLatestApiVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		behind  int
+		want    int
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "latest  highest version selected", behind: 0, want: 60, wantErr: assert.NoError},
+		{name: "latest minus one  previous version selected", behind: 1, want: 59, wantErr: assert.NoError},
+		{name: "more behind than versions  error returned, version unchanged", behind: 3, want: 55, wantErr: assert.Error},
+		{name: "negative behind  error returned, version unchanged", behind: -1, want: 55, wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newHttpClientMock(&http.Response{Body: io.NopCloser(bytes.NewReader([]byte(versionsBody))), StatusCode: 200}, nil)
+			h, _ := NewRequestHelper(client, newTokenGetterMock("token", nil), "https://org.my.salesforce.com", 55)
+
+			tt.wantErr(t, h.SelectLatestApiVersion(context.Background(), tt.behind))
+			assert.Equal(t, tt.want, h.ApiVersion())
+		})
+	}
+}